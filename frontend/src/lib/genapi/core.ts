@@ -30,22 +30,310 @@ const Document = z
   })
   .passthrough();
 const Documents = z.array(Document);
+const AnalysisKindName = z.string();
+const LLMProvider = z.enum(["openai", "gemini", "fake"]);
+const AnalysisParameters = z
+  .object({
+    count: z.number().int().gte(1).lte(100),
+    difficulty: z.enum(["easy", "medium", "hard"]),
+    audience: z.enum(["intro", "undergraduate", "graduate"]),
+    length: z.enum(["short", "medium", "long"]),
+    focusTopics: z.array(z.string()),
+  })
+  .partial()
+  .passthrough();
 const AnalyzeCollectionRequest = z
-  .object({ type: z.enum(["summary", "flashcards", "quiz", "deep_summary"]) })
+  .object({
+    type: AnalysisKindName,
+    provider: LLMProvider.optional(),
+    parameters: AnalysisParameters.optional(),
+    regenerate: z.boolean().optional(),
+  })
+  .passthrough();
+const ModelUsage = z
+  .object({
+    model: z.string(),
+    promptHash: z.string(),
+    inputTokens: z.number().int(),
+    outputTokens: z.number().int(),
+    latencyMs: z.number().int(),
+    requests: z.number().int(),
+  })
   .passthrough();
 const CollectionAnalysis = z
   .object({
     id: z.string().uuid(),
-    type: z.enum(["summary", "flashcards", "quiz", "deep_summary"]),
+    type: AnalysisKindName,
     result: z.string(),
-    createdAt: z.string().datetime().optional(),
+    createdAt: z.string().datetime(),
+    parameters: AnalysisParameters,
+    templateId: z.string().uuid().optional(),
+    usage: ModelUsage,
+    reused: z.boolean().optional(),
+    imported: z.boolean().optional(),
+  })
+  .passthrough();
+const QuotaExceededError = z
+  .object({
+    message: z.string(),
+    period: z.enum(["day", "month"]),
+    metric: z.enum(["tokens", "requests"]),
+    limit: z.number().int(),
+    used: z.number().int(),
+    resetsAt: z.string().datetime(),
+  })
+  .passthrough();
+const AnalysisValidationError = z
+  .object({
+    message: z.string(),
+    type: z.string(),
+    attempts: z.number().int(),
+    violations: z.array(z.string()),
+  })
+  .passthrough();
+const AnalysisJob = z
+  .object({
+    id: z.string().uuid(),
+    collectionID: z.string().uuid(),
+    type: AnalysisKindName,
+    status: z.enum([
+      "queued",
+      "extracting",
+      "analyzing",
+      "succeeded",
+      "failed",
+    ]),
+    provider: z.string(),
+    analysisID: z.string().uuid().optional(),
+    error: z.string().optional(),
+    createdAt: z.string().datetime(),
+    updatedAt: z.string().datetime(),
+    startedAt: z.string().datetime().optional(),
+    finishedAt: z.string().datetime().optional(),
+  })
+  .passthrough();
+const UsageTotals = z
+  .object({ requests: z.number().int(), tokens: z.number().int() })
+  .passthrough();
+const UsagePeriod = z
+  .object({
+    start: z.string().datetime(),
+    resetsAt: z.string().datetime(),
+    used: UsageTotals,
+    extraction: UsageTotals,
+    analysis: UsageTotals,
+    embedding: UsageTotals,
+    tokenLimit: z.number().int().optional(),
+    requestLimit: z.number().int().optional(),
+  })
+  .passthrough();
+const UsageSummary = z
+  .object({ day: UsagePeriod, month: UsagePeriod })
+  .passthrough();
+const SearchResult = z
+  .object({
+    segmentId: z.string().uuid(),
+    content: z.string(),
+    score: z.number(),
+    documentId: z.string().uuid(),
+    documentTitle: z.string(),
+    page: z.number().int(),
+    collectionId: z.string().uuid(),
+    collectionTitle: z.string(),
+    course: z.string(),
+  })
+  .passthrough();
+const SearchResults = z
+  .object({
+    query: z.string(),
+    results: z.array(SearchResult),
+    usage: ModelUsage,
   })
   .passthrough();
+const AnalysisKind = z
+  .object({ name: z.string(), description: z.string(), schema: z.string() })
+  .passthrough();
+const AnalysisKinds = z.array(AnalysisKind);
 const CollectionAnalyses = z.array(CollectionAnalysis);
 const NewCourseRequest = z.object({ name: z.string() }).passthrough();
 const NewCourseResponse = z.object({ courseName: z.string() }).passthrough();
 const CourseNames = z.array(z.string());
 const CollectionNames = z.array(Collection);
+const PromptTemplate = z
+  .object({
+    id: z.string().uuid(),
+    course: z.string(),
+    kind: AnalysisKindName,
+    version: z.number().int(),
+    template: z.string(),
+    active: z.boolean(),
+    createdAt: z.string().datetime(),
+    retiredAt: z.string().datetime().optional(),
+  })
+  .passthrough();
+const PromptTemplates = z.array(PromptTemplate);
+const SavePromptTemplateRequest = z
+  .object({ kind: AnalysisKindName, template: z.string() })
+  .passthrough();
+const Source = z
+  .object({
+    documentId: z.string().uuid(),
+    title: z.string(),
+    page: z.number().int(),
+  })
+  .passthrough();
+const ReviewCard = z
+  .object({
+    id: z.string().uuid(),
+    analysisId: z.string().uuid(),
+    itemId: z.string().uuid(),
+    position: z.number().int(),
+    collectionId: z.string().uuid(),
+    collectionTitle: z.string(),
+    course: z.string(),
+    question: z.string(),
+    answer: z.string(),
+    sources: z.array(Source),
+    repetitions: z.number().int(),
+    intervalDays: z.number().int(),
+    easeFactor: z.number(),
+    dueAt: z.string().datetime(),
+    lastReviewedAt: z.string().datetime().optional(),
+  })
+  .passthrough();
+const ReviewCards = z.array(ReviewCard);
+const ReviewQueue = z
+  .object({ cards: ReviewCards, due: z.number().int() })
+  .passthrough();
+const GradeReviewCardRequest = z
+  .object({ grade: z.number().int().gte(0).lte(5) })
+  .passthrough();
+const QuizQuestion = z
+  .object({
+    index: z.number().int(),
+    question: z.string(),
+    options: z.array(z.string()),
+  })
+  .passthrough();
+const QuizAnswerResult = z
+  .object({
+    index: z.number().int(),
+    question: z.string(),
+    selectedIndex: z.number().int().optional(),
+    correctIndex: z.number().int(),
+    correct: z.boolean(),
+    timeMs: z.number().int(),
+  })
+  .passthrough();
+const QuizAttempt = z
+  .object({
+    id: z.string().uuid(),
+    analysisId: z.string().uuid(),
+    collectionId: z.string().uuid(),
+    collectionTitle: z.string(),
+    course: z.string(),
+    startedAt: z.string().datetime(),
+    submittedAt: z.string().datetime().optional(),
+    score: z.number().int().optional(),
+    total: z.number().int().optional(),
+    durationMs: z.number().int().optional(),
+    questions: z.array(QuizQuestion).optional(),
+    results: z.array(QuizAnswerResult).optional(),
+  })
+  .passthrough();
+const QuizAttempts = z.array(QuizAttempt);
+const QuizAnswer = z
+  .object({
+    index: z.number().int(),
+    selectedIndex: z.number().int().optional(),
+    timeMs: z.number().int().gte(0).optional(),
+  })
+  .passthrough();
+const SubmitQuizAttemptRequest = z
+  .object({ answers: z.array(QuizAnswer) })
+  .passthrough();
+const AnalysisItem = z
+  .object({
+    id: z.string().uuid(),
+    analysisId: z.string().uuid(),
+    position: z.number().int(),
+    content: z.object({}).partial().passthrough(),
+    generated: z.object({}).partial().passthrough().optional(),
+    edited: z.boolean(),
+    createdAt: z.string().datetime(),
+    updatedAt: z.string().datetime(),
+  })
+  .passthrough();
+const AnalysisItems = z.array(AnalysisItem);
+const AnalysisItemRequest = z
+  .object({ content: z.object({}).partial().passthrough() })
+  .passthrough();
+const ReorderAnalysisItemsRequest = z
+  .object({ itemIds: z.array(z.string().uuid()) })
+  .passthrough();
+const GradeFreeResponseRequest = z
+  .object({ answer: z.string(), provider: LLMProvider.optional() })
+  .passthrough();
+const RubricCriterionGrade = z
+  .object({
+    criterion: z.string(),
+    points: z.number().int(),
+    awarded: z.number().int(),
+    explanation: z.string(),
+  })
+  .passthrough();
+const FreeResponseGrade = z
+  .object({
+    itemId: z.string().uuid(),
+    question: z.string(),
+    referenceAnswer: z.string(),
+    score: z.number().int(),
+    maxScore: z.number().int(),
+    hit: z.array(RubricCriterionGrade),
+    missed: z.array(RubricCriterionGrade),
+    feedback: z.string(),
+    sources: z.array(Source),
+    usage: ModelUsage,
+  })
+  .passthrough();
+const FlashcardExportFormat = z.enum(["apkg", "csv", "tsv"]);
+const StudyGuideFormat = z.enum(["pdf", "markdown", "latex"]);
+const ImportFormat = z.enum(["apkg", "csv", "tsv", "quizlet"]);
+const ImportResult = z
+  .object({
+    analysisId: z.string().uuid(),
+    created: z.number().int(),
+    updated: z.number().int(),
+    unchanged: z.number().int(),
+    skipped: z.number().int(),
+    problems: z.array(z.string()),
+  })
+  .passthrough();
+const ChatMessage = z
+  .object({
+    id: z.string().uuid(),
+    role: z.enum(["user", "assistant"]),
+    content: z.string(),
+    createdAt: z.string().datetime(),
+    sources: z.array(Source),
+    usage: ModelUsage,
+  })
+  .passthrough();
+const Chat = z
+  .object({
+    id: z.string().uuid(),
+    collectionId: z.string().uuid().optional(),
+    course: z.string().optional(),
+    title: z.string(),
+    createdAt: z.string().datetime(),
+    updatedAt: z.string().datetime(),
+    messages: z.array(ChatMessage).optional(),
+  })
+  .passthrough();
+const Chats = z.array(Chat);
+const SendChatMessageRequest = z
+  .object({ content: z.string(), provider: LLMProvider.optional() })
+  .passthrough();
 
 export const schemas = {
   NewCollectionRequest,
@@ -56,171 +344,1131 @@ export const schemas = {
   UploadFileResponse,
   Document,
   Documents,
+  AnalysisKindName,
+  LLMProvider,
+  AnalysisParameters,
   AnalyzeCollectionRequest,
+  ModelUsage,
   CollectionAnalysis,
+  QuotaExceededError,
+  AnalysisValidationError,
+  AnalysisJob,
+  UsageTotals,
+  UsagePeriod,
+  UsageSummary,
+  SearchResult,
+  SearchResults,
+  AnalysisKind,
+  AnalysisKinds,
   CollectionAnalyses,
   NewCourseRequest,
   NewCourseResponse,
   CourseNames,
   CollectionNames,
+  PromptTemplate,
+  PromptTemplates,
+  SavePromptTemplateRequest,
+  Source,
+  ReviewCard,
+  ReviewCards,
+  ReviewQueue,
+  GradeReviewCardRequest,
+  QuizQuestion,
+  QuizAnswerResult,
+  QuizAttempt,
+  QuizAttempts,
+  QuizAnswer,
+  SubmitQuizAttemptRequest,
+  AnalysisItem,
+  AnalysisItems,
+  AnalysisItemRequest,
+  ReorderAnalysisItemsRequest,
+  GradeFreeResponseRequest,
+  RubricCriterionGrade,
+  FreeResponseGrade,
+  FlashcardExportFormat,
+  StudyGuideFormat,
+  ImportFormat,
+  ImportResult,
+  ChatMessage,
+  Chat,
+  Chats,
+  SendChatMessageRequest,
 };
 
 const endpoints = makeApi([
+  {
+    method: "put",
+    path: "/core/analysis-item/:itemID",
+    alias: "editAnalysisItem",
+    description: `Replace an item&#x27;s content. The generated original is kept and can be restored.
+Leaving sources out keeps the ones the item already cites.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: AnalysisItemRequest,
+      },
+      {
+        name: "itemID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: AnalysisItem,
+    errors: [
+      {
+        status: 400,
+        description: `The item doesn&#x27;t match the kind&#x27;s schema`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Item not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "delete",
+    path: "/core/analysis-item/:itemID",
+    alias: "deleteAnalysisItem",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "itemID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.void(),
+    errors: [
+      {
+        status: 404,
+        description: `Item not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "delete",
+    path: "/core/analysis-item/:itemID/edit",
+    alias: "revertAnalysisItem",
+    description: `Discard the user&#x27;s edit and go back to the generated item`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "itemID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: AnalysisItem,
+    errors: [
+      {
+        status: 400,
+        description: `The item was added by hand and has nothing to revert to`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Item not found`,
+        schema: z.void(),
+      },
+    ],
+  },
   {
     method: "post",
-    path: "/core/collection",
-    alias: "newCollection",
+    path: "/core/analysis-item/:itemID/grade",
+    alias: "gradeFreeResponse",
+    description: `Score a typed answer to a free_response question against its rubric. The model grades with the
+pages the question cites, or the collection&#x27;s most relevant pages when it cites none, and its
+feedback cites them. Nothing is stored; grading counts towards the user&#x27;s quota.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: GradeFreeResponseRequest,
+      },
+      {
+        name: "itemID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: FreeResponseGrade,
+    errors: [
+      {
+        status: 400,
+        description: `An empty or overlong answer, an item that isn&#x27;t a free-response question, or an unknown provider`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Item not found, or the collection has no extracted content`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `The user&#x27;s quota is used up`,
+        schema: QuotaExceededError,
+      },
+      {
+        status: 502,
+        description: `The model&#x27;s grade failed schema validation after every repair attempt`,
+        schema: z
+          .object({
+            message: z.string(),
+            type: z.string(),
+            attempts: z.number().int(),
+            violations: z.array(z.string()),
+          })
+          .passthrough(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/analysis-job/:jobID",
+    alias: "getAnalysisJob",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "jobID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: AnalysisJob,
+    errors: [
+      {
+        status: 404,
+        description: `Job not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/analysis-kinds",
+    alias: "getAnalysisKinds",
+    requestFormat: "json",
+    response: z.array(AnalysisKind),
+  },
+  {
+    method: "get",
+    path: "/core/analysis/:analysisID/export",
+    alias: "exportFlashcards",
+    description: `Download a flashcard analysis, with the user&#x27;s edits, as an Anki package or a CSV/TSV file
+Anki can import. LaTeX is given MathJax delimiters. Cards keep their ids across exports,
+so importing a newer export into Anki updates the cards instead of duplicating them.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+      {
+        name: "format",
+        type: "Query",
+        schema: FlashcardExportFormat.optional(),
+      },
+    ],
+    response: z.void(),
+    errors: [
+      {
+        status: 400,
+        description: `Invalid format, or the analysis isn&#x27;t flashcards`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Analysis not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/analysis/:analysisID/items",
+    alias: "getAnalysisItems",
+    description: `The items of a list-shaped analysis (flashcards, quiz questions, glossary terms, ...) in order,
+with the user&#x27;s edits applied. Deleted items are left out.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(AnalysisItem),
+    errors: [
+      {
+        status: 400,
+        description: `The analysis kind isn&#x27;t a list of items`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Analysis not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/analysis/:analysisID/items",
+    alias: "addAnalysisItem",
+    description: `Add an item to the end of an analysis. It must match the kind&#x27;s item schema.`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: AnalysisItemRequest,
+      },
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: AnalysisItem,
+    errors: [
+      {
+        status: 400,
+        description: `The item doesn&#x27;t match the kind&#x27;s schema, or the kind isn&#x27;t a list of items`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Analysis not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "put",
+    path: "/core/analysis/:analysisID/items/order",
+    alias: "reorderAnalysisItems",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: z.object({ itemIds: z.array(z.string().uuid()) }).passthrough(),
+      },
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(AnalysisItem),
+    errors: [
+      {
+        status: 400,
+        description: `The ids aren&#x27;t exactly the analysis&#x27; items`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Analysis not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/analysis/:analysisID/quiz-attempts",
+    alias: "getAnalysisQuizAttempts",
+    description: `The user&#x27;s attempts at a quiz, newest first`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(QuizAttempt),
+  },
+  {
+    method: "post",
+    path: "/core/analysis/:analysisID/quiz-attempts",
+    alias: "startQuizAttempt",
+    description: `Start an attempt at a quiz analysis. The questions are returned without their answers.`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: QuizAttempt,
+    errors: [
+      {
+        status: 400,
+        description: `The analysis isn&#x27;t a quiz`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Analysis not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/analysis/:analysisID/review-cards",
+    alias: "enrollReviewCards",
+    description: `Schedule every card of a flashcard analysis for spaced-repetition review, due today.
+Cards already enrolled keep their schedule, so this can safely be called again.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(ReviewCard),
+    errors: [
+      {
+        status: 400,
+        description: `The analysis isn&#x27;t a flashcard analysis`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Analysis not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/chat/:chatID",
+    alias: "getChat",
+    description: `A chat with its messages, oldest first`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "chatID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: Chat,
+    errors: [
+      {
+        status: 404,
+        description: `Chat not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "delete",
+    path: "/core/chat/:chatID",
+    alias: "deleteChat",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "chatID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.void(),
+    errors: [
+      {
+        status: 404,
+        description: `Chat not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/chat/:chatID/messages",
+    alias: "sendChatMessage",
+    description: `Ask a question in a chat. It is answered only from the existing text extractions of the chat&#x27;s
+documents, using the pages closest to the question and the conversation so far; documents are never
+extracted again. The answer marks its claims [1], [2], ... numbering its sources. The question and
+the answer are stored together once answered. Answers count towards the user&#x27;s quota.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: SendChatMessageRequest,
+      },
+      {
+        name: "chatID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: ChatMessage,
+    errors: [
+      {
+        status: 400,
+        description: `An empty or overlong message, or an unknown provider`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Chat not found, or none of its documents have been extracted`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `The user&#x27;s quota is used up`,
+        schema: QuotaExceededError,
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/chat/:chatID/messages/stream",
+    alias: "streamChatMessage",
+    description: `Ask a question as sendChatMessage does, streaming the answer as Server-Sent Events.
+Each event&#x27;s data is a JSON object:
+
+- &#x60;token&#x60; (ChatTokenEvent): text of the answer as it is generated. It cites [S#] labels, which the
+  stored answer replaces with numbered markers.
+- &#x60;message&#x60; (ChatMessage): the stored answer. This is the last event on success.
+- &#x60;error&#x60; (ChatStreamError): answering failed and nothing was stored. This is the last event on failure.
+
+Errors raised before the first event are returned as regular responses.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: SendChatMessageRequest,
+      },
+      {
+        name: "chatID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.void(),
+    errors: [
+      {
+        status: 400,
+        description: `An empty or overlong message, or an unknown provider`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Chat not found, or none of its documents have been extracted`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `The user&#x27;s quota is used up`,
+        schema: QuotaExceededError,
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/collection",
+    alias: "newCollection",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: z
+          .object({ title: z.string(), course: z.string(), type: z.string() })
+          .passthrough(),
+      },
+    ],
+    response: z.object({ collectionID: z.string().uuid() }).passthrough(),
+  },
+  {
+    method: "get",
+    path: "/core/collection/:id",
+    alias: "getCollection",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z
+      .object({
+        ID: z.string().uuid(),
+        title: z.string(),
+        course: z.string(),
+        type: z.string(),
+      })
+      .passthrough(),
+  },
+  {
+    method: "get",
+    path: "/core/collection/:id/analyses",
+    alias: "getCollectionAnalyses",
+    description: `Returns all AI analyses that have been generated
+for the given collection.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(CollectionAnalysis),
+    errors: [
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+      {
+        status: 500,
+        description: `Failed to retrieve analyses`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/collection/:id/analysis-jobs",
+    alias: "enqueueCollectionAnalysis",
+    description: `Queues an analysis job for the collection and returns immediately.
+Poll the job until it reaches a terminal status to get the analysis.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: AnalyzeCollectionRequest,
+      },
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: AnalysisJob,
+    errors: [
+      {
+        status: 400,
+        description: `Invalid request`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `A usage quota is used up. Retry-After gives the seconds until it resets.`,
+        schema: QuotaExceededError,
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/collection/:id/analysis/:analysisID",
+    alias: "getAnalysis",
+    description: `Returns all AI analyses that have been generated
+for the given collection.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+      {
+        name: "analysisID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: CollectionAnalysis,
+    errors: [
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+      {
+        status: 500,
+        description: `Failed to retrieve analyses`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/collection/:id/analyze",
+    alias: "analyzeCollection",
+    description: `Performs an AI analysis on all documents in a collection.
+The analysis is run on a snapshot of the collection content.
+If the collection was already analyzed with identical content and parameters,
+that analysis is returned unless regenerate is set.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: AnalyzeCollectionRequest,
+      },
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: CollectionAnalysis,
+    errors: [
+      {
+        status: 400,
+        description: `Invalid request`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `A usage quota is used up. Retry-After gives the seconds until it resets.`,
+        schema: QuotaExceededError,
+      },
+      {
+        status: 500,
+        description: `Analysis failed`,
+        schema: z.void(),
+      },
+      {
+        status: 502,
+        description: `The model&#x27;s output failed schema validation after every repair attempt`,
+        schema: z
+          .object({
+            message: z.string(),
+            type: z.string(),
+            attempts: z.number().int(),
+            violations: z.array(z.string()),
+          })
+          .passthrough(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/collection/:id/analyze/stream",
+    alias: "streamCollectionAnalysis",
+    description: `Runs the same analysis as analyzeCollection but responds with Server-Sent Events
+as it progresses. Each event&#x27;s data is a JSON object:
+
+- &#x60;status&#x60; (AnalysisStatusEvent): the analysis entered a new stage
+- &#x60;document_extracted&#x60; (AnalysisDocumentEvent): a document&#x27;s text is ready
+- &#x60;snapshot_created&#x60; (AnalysisSnapshotEvent): the collection content was snapshotted
+- &#x60;token&#x60; (AnalysisTokenEvent): text generated by the model. A new attempt restarts the output.
+  Not sent for quizzes or free-response questions, whose raw output holds the answers.
+- &#x60;partial_result&#x60; (AnalysisPartialResultEvent): a chunk of a large collection was analyzed
+- &#x60;analysis&#x60; (CollectionAnalysis): the persisted analysis. This is the last event on success.
+- &#x60;error&#x60; (AnalysisStreamError): the analysis failed. This is the last event on failure.
+
+Errors raised before the first event are returned as regular responses.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: AnalyzeCollectionRequest,
+      },
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.void(),
+    errors: [
+      {
+        status: 400,
+        description: `Invalid request`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `A usage quota is used up. Retry-After gives the seconds until it resets.`,
+        schema: QuotaExceededError,
+      },
+      {
+        status: 500,
+        description: `Analysis failed`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/collection/:id/chats",
+    alias: "getCollectionChats",
+    description: `The user&#x27;s chats over a collection, most recently active first, without their messages`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(Chat),
+  },
+  {
+    method: "post",
+    path: "/core/collection/:id/chats",
+    alias: "createCollectionChat",
+    description: `Start a chat with the notes of a collection`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: Chat,
+    errors: [
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/collection/:id/import",
+    alias: "importAnalysisItems",
+    description: `Import flashcards or quiz questions from a file into the collection&#x27;s imported analysis of that kind,
+where they are edited, reviewed and exported like generated items. The body is the raw file.
+Importing the same file again changes nothing. Importing an updated file updates the items it had
+before, keeping edits, and adds the new ones; items deleted since stay deleted.
+
+Formats:
+  - apkg: an Anki package (.apkg or .colpkg). Flashcards only; a note&#x27;s first field is the front and
+    its second the back, and cloze notes become a card with the deletions blanked.
+  - csv, tsv: Anki&#x27;s text export or any file with a header row naming question/front/term and
+    answer/back/definition columns. Quiz files name an &quot;options&quot; column (separated by &quot;|&quot;) or option
+    columns, and an answer column holding a letter, a number from 1, or the option&#x27;s text. Without a
+    header, a row is question, answer for flashcards, and question, options..., answer for quizzes.
+  - quizlet: Quizlet&#x27;s export, a term and its definition per line separated by a tab or comma.
+    Flashcards only.
+`,
+    requestFormat: "binary",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: z.instanceof(File),
+      },
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+      {
+        name: "kind",
+        type: "Query",
+        schema: z.enum(["flashcards", "quiz"]),
+      },
+      {
+        name: "format",
+        type: "Query",
+        schema: ImportFormat,
+      },
+    ],
+    response: z
+      .object({
+        analysisId: z.string().uuid(),
+        created: z.number().int(),
+        updated: z.number().int(),
+        unchanged: z.number().int(),
+        skipped: z.number().int(),
+        problems: z.array(z.string()),
+      })
+      .passthrough(),
+    errors: [
+      {
+        status: 400,
+        description: `Invalid kind or format, or a file that can&#x27;t be read`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Collection not found`,
+        schema: z.void(),
+      },
+      {
+        status: 413,
+        description: `The file is larger than 64 MiB`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/collection/:id/study-guide",
+    alias: "exportStudyGuide",
+    description: `Download a study guide built from the collection&#x27;s newest summary and deep summary: a title page,
+an overview, a section per deep-summary concept, and appendices with the newest flashcards and
+quiz answer key. Edits to items are included. Rendered on the server.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+      {
+        name: "format",
+        type: "Query",
+        schema: StudyGuideFormat.optional(),
+      },
+    ],
+    response: z.void(),
+    errors: [
+      {
+        status: 400,
+        description: `Invalid format`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Collection not found, or it has no summary or deep summary`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/collections/:courseID/:type",
+    alias: "filterCollections",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "courseID",
+        type: "Path",
+        schema: z.string(),
+      },
+      {
+        name: "type",
+        type: "Path",
+        schema: z.string(),
+      },
+    ],
+    response: z.array(Collection),
+  },
+  {
+    method: "get",
+    path: "/core/collections/:id/documents",
+    alias: "getCollectionDocuments",
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "id",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: z.array(Document),
+  },
+  {
+    method: "post",
+    path: "/core/course",
+    alias: "newCourse",
     requestFormat: "json",
     parameters: [
       {
         name: "body",
         type: "Body",
-        schema: z
-          .object({ title: z.string(), course: z.string(), type: z.string() })
-          .passthrough(),
+        schema: z.object({ name: z.string() }).passthrough(),
       },
     ],
-    response: z.object({ collectionID: z.string().uuid() }).passthrough(),
+    response: z.object({ courseName: z.string() }).passthrough(),
   },
   {
     method: "get",
-    path: "/core/collection/:id",
-    alias: "getCollection",
+    path: "/core/course/:courseID/chats",
+    alias: "getCourseChats",
+    description: `The user&#x27;s chats over a course, most recently active first, without their messages`,
     requestFormat: "json",
     parameters: [
       {
-        name: "id",
+        name: "courseID",
         type: "Path",
-        schema: z.string().uuid(),
+        schema: z.string(),
       },
     ],
-    response: z
-      .object({
-        ID: z.string().uuid(),
-        title: z.string(),
-        course: z.string(),
-        type: z.string(),
-      })
-      .passthrough(),
+    response: z.array(Chat),
   },
   {
-    method: "get",
-    path: "/core/collection/:id/analyses",
-    alias: "getCollectionAnalyses",
-    description: `Returns all AI analyses that have been generated
-for the given collection.
-`,
+    method: "post",
+    path: "/core/course/:courseID/chats",
+    alias: "createCourseChat",
+    description: `Start a chat with the notes of every collection in a course`,
     requestFormat: "json",
     parameters: [
       {
-        name: "id",
+        name: "courseID",
         type: "Path",
-        schema: z.string().uuid(),
+        schema: z.string(),
       },
     ],
-    response: z.array(CollectionAnalysis),
+    response: Chat,
     errors: [
       {
         status: 404,
-        description: `Collection not found`,
+        description: `Course not found`,
         schema: z.void(),
       },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/course/:courseID/collections",
+    alias: "getCourseCollections",
+    requestFormat: "json",
+    parameters: [
       {
-        status: 500,
-        description: `Failed to retrieve analyses`,
-        schema: z.void(),
+        name: "courseID",
+        type: "Path",
+        schema: z.string(),
       },
     ],
+    response: z.array(Collection),
   },
   {
     method: "get",
-    path: "/core/collection/:id/analysis/:analysisID",
-    alias: "getAnalysis",
-    description: `Returns all AI analyses that have been generated
-for the given collection.
+    path: "/core/course/:courseID/flashcards/export",
+    alias: "exportCourseFlashcards",
+    description: `Download the newest flashcard analysis of every collection in a course, with a deck per
+collection and the course name as a tag
 `,
     requestFormat: "json",
     parameters: [
       {
-        name: "id",
+        name: "courseID",
         type: "Path",
-        schema: z.string().uuid(),
+        schema: z.string(),
       },
       {
-        name: "analysisID",
-        type: "Path",
-        schema: z.string().uuid(),
+        name: "format",
+        type: "Query",
+        schema: FlashcardExportFormat.optional(),
       },
     ],
-    response: z
-      .object({
-        id: z.string().uuid(),
-        type: z.enum(["summary", "flashcards", "quiz", "deep_summary"]),
-        result: z.string(),
-      })
-      .passthrough(),
+    response: z.void(),
     errors: [
       {
-        status: 404,
-        description: `Collection not found`,
+        status: 400,
+        description: `Invalid format`,
         schema: z.void(),
       },
       {
-        status: 500,
-        description: `Failed to retrieve analyses`,
+        status: 404,
+        description: `The course has no flashcards`,
         schema: z.void(),
       },
     ],
   },
+  {
+    method: "get",
+    path: "/core/course/:courseID/prompt-templates",
+    alias: "getPromptTemplates",
+    description: `Every version of the course&#x27;s prompt templates, newest first within each kind`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "courseID",
+        type: "Path",
+        schema: z.string(),
+      },
+    ],
+    response: z.array(PromptTemplate),
+  },
   {
     method: "post",
-    path: "/core/collection/:id/analyze",
-    alias: "analyzeCollection",
-    description: `Performs an AI analysis on all documents in a collection.
-The analysis is run on a snapshot of the collection content.
+    path: "/core/course/:courseID/prompt-templates",
+    alias: "savePromptTemplate",
+    description: `Save a new version of the course&#x27;s template for an analysis kind. The new version
+is used by every later analysis of that kind in the course.
 `,
     requestFormat: "json",
     parameters: [
       {
         name: "body",
         type: "Body",
-        schema: AnalyzeCollectionRequest,
+        schema: SavePromptTemplateRequest,
       },
       {
-        name: "id",
+        name: "courseID",
         type: "Path",
-        schema: z.string().uuid(),
+        schema: z.string(),
       },
     ],
-    response: z
-      .object({
-        id: z.string().uuid(),
-        type: z.enum(["summary", "flashcards", "quiz", "deep_summary"]),
-        result: z.string(),
-      })
-      .passthrough(),
+    response: PromptTemplate,
     errors: [
       {
         status: 400,
-        description: `Invalid request`,
+        description: `Unknown analysis type, or the template doesn&#x27;t parse or leaves out the schema`,
         schema: z.void(),
       },
       {
         status: 404,
-        description: `Collection not found`,
-        schema: z.void(),
-      },
-      {
-        status: 500,
-        description: `Analysis failed`,
+        description: `Course not found`,
         schema: z.void(),
       },
     ],
   },
   {
-    method: "get",
-    path: "/core/collections/:courseID/:type",
-    alias: "filterCollections",
+    method: "delete",
+    path: "/core/course/:courseID/prompt-templates/:kind",
+    alias: "retirePromptTemplates",
+    description: `Go back to the default instructions for an analysis kind in the course.
+Retired versions are kept and still listed.
+`,
     requestFormat: "json",
     parameters: [
       {
@@ -229,45 +1477,18 @@ The analysis is run on a snapshot of the collection content.
         schema: z.string(),
       },
       {
-        name: "type",
+        name: "kind",
         type: "Path",
         schema: z.string(),
       },
     ],
-    response: z.array(Collection),
-  },
-  {
-    method: "get",
-    path: "/core/collections/:id/documents",
-    alias: "getCollectionDocuments",
-    requestFormat: "json",
-    parameters: [
-      {
-        name: "id",
-        type: "Path",
-        schema: z.string().uuid(),
-      },
-    ],
-    response: z.array(Document),
-  },
-  {
-    method: "post",
-    path: "/core/course",
-    alias: "newCourse",
-    requestFormat: "json",
-    parameters: [
-      {
-        name: "body",
-        type: "Body",
-        schema: z.object({ name: z.string() }).passthrough(),
-      },
-    ],
-    response: z.object({ courseName: z.string() }).passthrough(),
+    response: z.void(),
   },
   {
     method: "get",
-    path: "/core/course/:courseID/collections",
-    alias: "getCourseCollections",
+    path: "/core/course/:courseID/quiz-attempts",
+    alias: "getCourseQuizAttempts",
+    description: `The user&#x27;s attempts at every quiz in the course, newest first`,
     requestFormat: "json",
     parameters: [
       {
@@ -276,7 +1497,7 @@ The analysis is run on a snapshot of the collection content.
         schema: z.string(),
       },
     ],
-    response: z.array(Collection),
+    response: z.array(QuizAttempt),
   },
   {
     method: "get",
@@ -323,6 +1544,192 @@ The analysis is run on a snapshot of the collection content.
       })
       .passthrough(),
   },
+  {
+    method: "get",
+    path: "/core/quiz-attempt/:attemptID",
+    alias: "getQuizAttempt",
+    description: `An attempt, with per-question results once it has been submitted`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "attemptID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: QuizAttempt,
+    errors: [
+      {
+        status: 404,
+        description: `Attempt not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/quiz-attempt/:attemptID/submit",
+    alias: "submitQuizAttempt",
+    description: `Grade an attempt. Questions left out of the answers count as wrong.`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: SubmitQuizAttemptRequest,
+      },
+      {
+        name: "attemptID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: QuizAttempt,
+    errors: [
+      {
+        status: 400,
+        description: `An answer refers to a question or option that doesn&#x27;t exist`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Attempt not found`,
+        schema: z.void(),
+      },
+      {
+        status: 409,
+        description: `Attempt already submitted`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "post",
+    path: "/core/review-card/:cardID/grade",
+    alias: "gradeReviewCard",
+    description: `Record how well a card was recalled and schedule its next review (SM-2)`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "body",
+        type: "Body",
+        schema: z
+          .object({ grade: z.number().int().gte(0).lte(5) })
+          .passthrough(),
+      },
+      {
+        name: "cardID",
+        type: "Path",
+        schema: z.string().uuid(),
+      },
+    ],
+    response: ReviewCard,
+    errors: [
+      {
+        status: 400,
+        description: `Grade outside 0-5`,
+        schema: z.void(),
+      },
+      {
+        status: 404,
+        description: `Card not found`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/reviews/due",
+    alias: "getDueReviews",
+    description: `Cards due for review by the end of the day across all of the user&#x27;s collections and courses,
+most overdue first
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "course",
+        type: "Query",
+        schema: z.string().optional(),
+      },
+      {
+        name: "collectionId",
+        type: "Query",
+        schema: z.string().uuid().optional(),
+      },
+      {
+        name: "limit",
+        type: "Query",
+        schema: z.number().int().gte(1).lte(500).optional().default(50),
+      },
+      {
+        name: "timezone",
+        description: `IANA name of the user&#x27;s timezone, such as Europe/Berlin, whose day the queue covers`,
+        type: "Query",
+        schema: z.string().optional().default("UTC"),
+      },
+    ],
+    response: ReviewQueue,
+    errors: [
+      {
+        status: 400,
+        description: `Invalid limit or timezone`,
+        schema: z.void(),
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/search",
+    alias: "search",
+    description: `Find the passages of the user&#x27;s extracted documents closest in meaning to a query, across every
+collection and course they own unless filtered. Pages can be found once they are embedded, which
+happens as they are extracted; pages extracted earlier are embedded in the background. Embedding
+the query counts towards the user&#x27;s quota.
+`,
+    requestFormat: "json",
+    parameters: [
+      {
+        name: "q",
+        type: "Query",
+        schema: z.string().max(1000),
+      },
+      {
+        name: "course",
+        type: "Query",
+        schema: z.string().optional(),
+      },
+      {
+        name: "collectionId",
+        type: "Query",
+        schema: z.string().uuid().optional(),
+      },
+      {
+        name: "limit",
+        type: "Query",
+        schema: z.number().int().gte(1).lte(50).optional().default(10),
+      },
+    ],
+    response: SearchResults,
+    errors: [
+      {
+        status: 400,
+        description: `An empty or overlong query, or a limit out of range`,
+        schema: z.void(),
+      },
+      {
+        status: 429,
+        description: `The user&#x27;s quota is used up`,
+        schema: QuotaExceededError,
+      },
+    ],
+  },
+  {
+    method: "get",
+    path: "/core/usage",
+    alias: "getUsage",
+    requestFormat: "json",
+    response: UsageSummary,
+  },
 ]);
 
 export const api = new Zodios(endpoints);
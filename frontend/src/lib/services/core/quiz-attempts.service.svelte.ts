@@ -0,0 +1,67 @@
+/**
+ * Quiz attempts service
+ */
+
+import { BaseService } from '../shared/service-base';
+import { coreApiClient } from '../shared/api-client';
+import type { ServiceResult } from '../shared/types';
+import type { z } from 'zod';
+import type { schemas } from '$lib/genapi/core';
+
+type QuizAttempt = z.infer<typeof schemas.QuizAttempt>;
+type QuizAnswer = z.infer<typeof schemas.QuizAnswer>;
+
+class QuizAttemptsService extends BaseService {
+	currentAttempt = $state<QuizAttempt | null>(null);
+	loading = $state(false);
+	error = $state<string | null>(null);
+
+	/**
+	 * Start an attempt at a quiz. Its questions come without their answers.
+	 */
+	async start(analysisId: string): Promise<ServiceResult<QuizAttempt>> {
+		this.loading = true;
+		this.error = null;
+
+		const result = await this.execute(async () => {
+			const attempt = await coreApiClient.startQuizAttempt(undefined, {
+				params: { analysisID: analysisId }
+			});
+			this.currentAttempt = attempt;
+			return attempt;
+		});
+
+		this.loading = false;
+		if (result.error) {
+			this.error = result.error.message;
+		}
+
+		return result;
+	}
+
+	/**
+	 * Submit the answers to an attempt, which the server grades
+	 */
+	async submit(attemptId: string, answers: QuizAnswer[]): Promise<ServiceResult<QuizAttempt>> {
+		this.loading = true;
+		this.error = null;
+
+		const result = await this.execute(async () => {
+			const attempt = await coreApiClient.submitQuizAttempt(
+				{ answers },
+				{ params: { attemptID: attemptId } }
+			);
+			this.currentAttempt = attempt;
+			return attempt;
+		});
+
+		this.loading = false;
+		if (result.error) {
+			this.error = result.error.message;
+		}
+
+		return result;
+	}
+}
+
+export const quizAttemptsService = new QuizAttemptsService();
@@ -12,3 +12,4 @@ export { collectionsService } from './core/collections.service.svelte';
 export { documentsService } from './core/documents.service.svelte';
 export { coursesService } from './core/courses.service.svelte';
 export { analysesService } from './core/analyses.service.svelte';
+export { quizAttemptsService } from './core/quiz-attempts.service.svelte';
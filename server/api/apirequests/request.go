@@ -2,12 +2,19 @@ package apirequests
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"server/api/tools/internaltools/webtokens"
 
 	"github.com/google/uuid"
 )
 
+// Errors
+var (
+	ErrBodyTooLarge error = errors.New("request body too large")
+)
+
 func Request[T any](r *http.Request) (*T, error) {
 	var request T
 	err := json.NewDecoder(r.Body).Decode(&request)
@@ -17,6 +24,18 @@ func Request[T any](r *http.Request) (*T, error) {
 	return &request, nil
 }
 
+// Body reads a raw request body of up to maxBytes
+func Body(r *http.Request, maxBytes int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrBodyTooLarge
+	}
+	return data, nil
+}
+
 func User(r *http.Request) (*uuid.UUID, error) {
 	return webtokens.GetUserIDfromRequest(r)
 }
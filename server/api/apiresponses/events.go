@@ -0,0 +1,57 @@
+package apiresponses
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// EventStream writes Server-Sent Events, flushing each one to the client as it is sent.
+// Headers are written with the first event, so a handler can still fall back to a
+// regular error response until then. It is safe to send from multiple goroutines.
+type EventStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	mu         sync.Mutex
+	started    bool
+}
+
+func NewEventStream(w http.ResponseWriter) *EventStream {
+	return &EventStream{
+		w:          w,
+		controller: http.NewResponseController(w),
+	}
+}
+
+// Send writes a named event with a JSON encoded payload
+func (stream *EventStream) Send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if !stream.started {
+		stream.w.Header().Set("Content-Type", "text/event-stream")
+		stream.w.Header().Set("Cache-Control", "no-cache")
+		stream.w.Header().Set("Connection", "keep-alive")
+		stream.w.Header().Set("X-Accel-Buffering", "no") // Stop reverse proxies from buffering events
+		stream.w.WriteHeader(http.StatusOK)
+		stream.started = true
+	}
+
+	if _, err := fmt.Fprintf(stream.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return stream.controller.Flush()
+}
+
+// Started reports whether any event, and so the response headers, have been written
+func (stream *EventStream) Started() bool {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return stream.started
+}
@@ -2,7 +2,9 @@ package apiresponses
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/gommon/log"
 )
@@ -37,6 +39,22 @@ func Success(w http.ResponseWriter, response any) {
 	}
 }
 
+// File returns a 200 OK response that downloads as the named file
+func File(w http.ResponseWriter, filename string, contentType string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		log.Error(err)
+	}
+}
+
+// Accepted returns a 202 Accepted response for work that will complete asynchronously
+func Accepted(w http.ResponseWriter, response any) {
+	ErrorWithBody(w, response, http.StatusAccepted)
+}
+
 // BadRequest logs the error and returns a 400 Bad Request response
 func BadRequest(w http.ResponseWriter, message string, err error) {
 	if err != nil {
@@ -5,14 +5,18 @@ import (
 	"log"
 	"net/http"
 	"server/api/serviceaccess"
+	"server/api/tools/externaltools/geminiapi"
 	"server/api/tools/externaltools/gptapi"
 	"server/api/tools/externaltools/minioapi"
 	"server/api/tools/externaltools/postgresapi"
+	"server/api/tools/features/llm"
 	"server/environment"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/openai/openai-go/v3"
 	"github.com/pressly/goose/v3"
+	"google.golang.org/genai"
 )
 
 func NewServer(
@@ -50,25 +54,74 @@ func NewServer(
 		return nil, err
 	}
 
-	// Init Gemini - Currently unused, keeping for future features
-	// log.Println("connecting to gemini...")
-	// geminiClient, err := geminiapi.Connect(env)
-	// if err != nil {
-	// 	return nil, err
-	// }
+	// Init LLM providers. Each vendor is only enabled when its key is set.
+	var providers []llm.Provider
+
+	var geminiClient *genai.Client
+	if env.GeminiAIKey != "" {
+		log.Println("connecting to gemini...")
+		geminiClient, err = geminiapi.Connect(env)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, llm.NewRateLimitedProvider(
+			llm.NewGeminiProvider(geminiClient, env.GeminiModel),
+			env.GeminiRequestsPerMin,
+		))
+	}
+
+	var openAIClient *openai.Client
+	if env.OpenAIKey != "" {
+		log.Println("connecting to openai...")
+		openAIClient, err = gptapi.Connect(env)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, llm.NewRateLimitedProvider(
+			llm.NewOpenAIProvider(openAIClient, env.OpenAIModel),
+			env.OpenAIRequestsPerMin,
+		))
+	}
+
+	// The fake provider serves offline development, so it's only available when chosen as the default
+	if env.LLMProvider == llm.ProviderFake {
+		log.Println("using the fake llm provider, model output is canned")
+		providers = append(providers, llm.NewFakeProvider())
+	}
 
-	// Init OpenAI
-	log.Println("connecting to openai...")
-	openAIClient, err := gptapi.Connect(env)
+	llmRegistry, err := llm.NewRegistry(llm.NewRegistryParams{
+		Default:   env.LLMProvider,
+		Providers: providers,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Init the embedder for semantic search. It needs its vendor's key like the providers above.
+	embeddingProvider := env.EmbeddingProvider
+	if embeddingProvider == "" {
+		embeddingProvider = env.LLMProvider
+	}
+
+	var embedder llm.Embedder
+	switch {
+	case embeddingProvider == llm.ProviderOpenAI && openAIClient != nil:
+		embedder = llm.NewOpenAIEmbedder(openAIClient, env.OpenAIEmbeddingModel)
+	case embeddingProvider == llm.ProviderGemini && geminiClient != nil:
+		embedder = llm.NewGeminiEmbedder(geminiClient, env.GeminiEmbeddingModel)
+	case embeddingProvider == llm.ProviderFake:
+		embedder = llm.NewFakeEmbedder()
+	default:
+		return nil, fmt.Errorf("%w: %q can't embed", llm.ErrUnknownProvider, embeddingProvider)
+	}
+
 	appServices := serviceaccess.Access{
 		Postgres: postgresClient,
 		Minio:    minioClient,
-		Gemini:   nil, // Disabled for now
+		Gemini:   geminiClient,
 		OpenAI:   openAIClient,
+		LLM:      llmRegistry,
+		Embedder: embedder,
 	}
 
 	mux.Use(cors.Handler(*corsConfig(env)))
@@ -2,6 +2,7 @@ package serviceaccess
 
 import (
 	"database/sql"
+	"server/api/tools/features/llm"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/openai/openai-go/v3"
@@ -11,6 +12,8 @@ import (
 type Access struct {
 	Postgres *sql.DB
 	Minio    *minio.Client
-	Gemini   *genai.Client
-	OpenAI   *openai.Client
+	Gemini   *genai.Client  // nil when GEMINI_API_KEY is unset
+	OpenAI   *openai.Client // nil when OPENAI_API_KEY is unset
+	LLM      *llm.Registry
+	Embedder llm.Embedder
 }
@@ -0,0 +1,74 @@
+package anki
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"html"
+	"regexp"
+	"server/api/tools/features/latexmath"
+	"strings"
+)
+
+// Deck is one deck of a package. Decks nest with "::" in their name, as in Anki.
+type Deck struct {
+	// Key identifies the deck across exports, so importing a newer export updates the same deck
+	Key   string
+	Name  string
+	Notes []Note
+}
+
+// Note is a Basic note. Fields hold HTML when writing a package, see Field, and plain text when reading one.
+type Note struct {
+	// GUID identifies the note across exports, so importing a newer export updates the note instead of duplicating it
+	GUID    string
+	Front   string
+	Back    string
+	Sources string
+	Tags    []string
+}
+
+const (
+	MimeType      = "application/apkg"
+	FileExtension = ".apkg"
+
+	// ModelName is the note type notes are exported with. It is Basic with an extra field for sources.
+	ModelName = "Basic (with sources)"
+
+	// MaxCollectionSize is how large a package's collection may grow once decompressed. Packages are
+	// small compressed, so larger ones are rejected rather than unpacked.
+	MaxCollectionSize = 256 << 20
+)
+
+var ( // Errors
+	ErrInvalidPackage error = errors.New("invalid anki package")
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	tagUnsafePattern  = regexp.MustCompile(`[\s"]+`)
+	lineBreakReplacer = strings.NewReplacer("\r\n", "<br>", "\n", "<br>")
+)
+
+// Field turns plain text into the HTML of a note field: math is given the delimiters Anki's MathJax reads,
+// markup is escaped and line breaks are kept
+func Field(text string) string {
+	return lineBreakReplacer.Replace(html.EscapeString(latexmath.Delimit(text)))
+}
+
+// StripHTML returns the text of a field, as Anki does for sorting and duplicate checks
+func StripHTML(field string) string {
+	field = strings.ReplaceAll(field, "<br>", " ")
+	return strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(field, "")))
+}
+
+// Tag turns a name into an Anki tag, which can't hold spaces
+func Tag(name string) string {
+	return strings.Trim(tagUnsafePattern.ReplaceAllString(strings.TrimSpace(name), "_"), "_")
+}
+
+// stableID derives a positive id from a key. It stays below 2^53 so JavaScript add-ons can hold it.
+func stableID(kind string, key string) int64 {
+	sum := sha1.Sum([]byte(kind + ":" + key))
+	return int64(binary.BigEndian.Uint64(sum[:8]) >> 11)
+}
@@ -0,0 +1,297 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// A package is a zip holding a legacy (schema 11) collection, which every Anki release since 2.1 imports
+
+const (
+	collectionFile = "collection.anki2"
+	mediaFile      = "media"
+
+	collectionVersion = 11
+	defaultDeckID     = 1
+	defaultConfID     = 1
+	fieldSeparator    = "\x1f"
+)
+
+const collectionSchema = `
+CREATE TABLE col (
+    id integer PRIMARY KEY, crt integer NOT NULL, mod integer NOT NULL, scm integer NOT NULL,
+    ver integer NOT NULL, dty integer NOT NULL, usn integer NOT NULL, ls integer NOT NULL,
+    conf text NOT NULL, models text NOT NULL, decks text NOT NULL, dconf text NOT NULL, tags text NOT NULL
+);
+CREATE TABLE notes (
+    id integer PRIMARY KEY, guid text NOT NULL, mid integer NOT NULL, mod integer NOT NULL,
+    usn integer NOT NULL, tags text NOT NULL, flds text NOT NULL, sfld integer NOT NULL,
+    csum integer NOT NULL, flags integer NOT NULL, data text NOT NULL
+);
+CREATE TABLE cards (
+    id integer PRIMARY KEY, nid integer NOT NULL, did integer NOT NULL, ord integer NOT NULL,
+    mod integer NOT NULL, usn integer NOT NULL, type integer NOT NULL, queue integer NOT NULL,
+    due integer NOT NULL, ivl integer NOT NULL, factor integer NOT NULL, reps integer NOT NULL,
+    lapses integer NOT NULL, left integer NOT NULL, odue integer NOT NULL, odid integer NOT NULL,
+    flags integer NOT NULL, data text NOT NULL
+);
+CREATE TABLE revlog (
+    id integer PRIMARY KEY, cid integer NOT NULL, usn integer NOT NULL, ease integer NOT NULL,
+    ivl integer NOT NULL, lastIvl integer NOT NULL, factor integer NOT NULL, time integer NOT NULL,
+    type integer NOT NULL
+);
+CREATE TABLE graves (usn integer NOT NULL, oid integer NOT NULL, type integer NOT NULL);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+const modelCSS = `.card {
+    font-family: arial;
+    font-size: 20px;
+    text-align: center;
+    color: black;
+    background-color: white;
+}
+
+.sources {
+    margin-top: 1em;
+    font-size: 14px;
+    color: gray;
+}
+`
+
+// WritePackage builds an .apkg holding the decks. Every note becomes one new card.
+func WritePackage(ctx context.Context, decks []Deck) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "apkg-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, collectionFile)
+	if err := writeCollection(ctx, path, decks); err != nil {
+		return nil, err
+	}
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{collectionFile, collection},
+		{mediaFile, []byte("{}")},
+	} {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCollection(ctx context.Context, path string, decks []Deck) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, collectionSchema); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	modelID := stableID("model", ModelName)
+
+	deckIDs := make([]int64, len(decks))
+	for i, deck := range decks {
+		deckIDs[i] = stableID("deck", deck.Key)
+	}
+
+	conf, models, deckConf, deckJSON, err := collectionJSON(now, modelID, decks, deckIDs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO col VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		now.Unix(), now.UnixMilli(), now.UnixMilli(), collectionVersion,
+		conf, models, deckJSON, deckConf,
+	); err != nil {
+		return err
+	}
+
+	due := 0
+	for i, deck := range decks {
+		for _, note := range deck.Notes {
+			due++
+			noteID := stableID("note", note.GUID)
+			sortField := StripHTML(note.Front)
+
+			tags := ""
+			if len(note.Tags) > 0 {
+				tags = " " + strings.Join(note.Tags, " ") + " "
+			}
+
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+				noteID, note.GUID, modelID, now.Unix(), tags,
+				strings.Join([]string{note.Front, note.Back, note.Sources}, fieldSeparator),
+				sortField, fieldChecksum(sortField),
+			); err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+				stableID("card", note.GUID), noteID, deckIDs[i], now.Unix(), due,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// collectionJSON returns the collection's configuration, note types, deck options and decks
+func collectionJSON(now time.Time, modelID int64, decks []Deck, deckIDs []int64) (conf, models, deckConf, deckJSON []byte, err error) {
+	conf, err = json.Marshal(map[string]any{
+		"nextPos":       1,
+		"estTimes":      true,
+		"activeDecks":   []int64{defaultDeckID},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       defaultDeckID,
+		"newBury":       true,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      modelID,
+		"collapseTime":  1200,
+	})
+	if err != nil {
+		return
+	}
+
+	field := func(name string, ord int) map[string]any {
+		return map[string]any{
+			"name": name, "ord": ord, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []any{},
+		}
+	}
+
+	models, err = json.Marshal(map[string]any{
+		strconv.FormatInt(modelID, 10): map[string]any{
+			"id":    modelID,
+			"name":  ModelName,
+			"type":  0,
+			"mod":   now.Unix(),
+			"usn":   -1,
+			"sortf": 0,
+			"did":   defaultDeckID,
+			"tmpls": []map[string]any{{
+				"name":  "Card 1",
+				"ord":   0,
+				"qfmt":  "{{Front}}",
+				"afmt":  `{{FrontSide}}<hr id=answer>{{Back}}{{#Sources}}<div class="sources">{{Sources}}</div>{{/Sources}}`,
+				"did":   nil,
+				"bqfmt": "",
+				"bafmt": "",
+			}},
+			"flds":      []map[string]any{field("Front", 0), field("Back", 1), field("Sources", 2)},
+			"css":       modelCSS,
+			"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+			"latexPost": "\\end{document}",
+			"latexsvg":  false,
+			"req":       []any{[]any{0, "any", []int{0}}},
+			"tags":      []string{},
+			"vers":      []any{},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	deckConf, err = json.Marshal(map[string]any{
+		strconv.Itoa(defaultConfID): map[string]any{
+			"id": defaultConfID, "name": "Default", "mod": 0, "usn": 0,
+			"maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+			"new": map[string]any{
+				"bury": false, "delays": []float64{1, 10}, "initialFactor": 2500,
+				"ints": []int{1, 4, 0}, "order": 1, "perDay": 20,
+			},
+			"lapse": map[string]any{
+				"delays": []float64{10}, "leechAction": 1, "leechFails": 8, "minInt": 1, "mult": 0,
+			},
+			"rev": map[string]any{
+				"bury": false, "ease4": 1.3, "ivlFct": 1, "maxIvl": 36500, "perDay": 200, "hardFactor": 1.2,
+			},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	deck := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "mod": now.Unix(), "usn": -1,
+			"lrnToday": []int{0, 0}, "revToday": []int{0, 0}, "newToday": []int{0, 0}, "timeToday": []int{0, 0},
+			"collapsed": false, "browserCollapsed": false, "desc": "", "dyn": 0, "conf": defaultConfID,
+			"extendNew": 0, "extendRev": 0,
+		}
+	}
+
+	all := map[string]any{
+		strconv.Itoa(defaultDeckID): deck(defaultDeckID, "Default"),
+	}
+	for i, d := range decks {
+		all[strconv.FormatInt(deckIDs[i], 10)] = deck(deckIDs[i], d.Name)
+	}
+	deckJSON, err = json.Marshal(all)
+
+	return
+}
+
+// fieldChecksum is the first 32 bits of the field's SHA-1, which Anki uses to find duplicates
+func fieldChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
@@ -0,0 +1,156 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Collections a package may hold, newest first. Packages with an anki21b collection also carry
+// an anki2 one holding a single note that asks to update Anki, so the newest is read.
+var packageCollections = []string{"collection.anki21b", "collection.anki21", collectionFile}
+
+var (
+	clozePattern     = regexp.MustCompile(`\{\{c\d+::([\s\S]*?)(?:::([\s\S]*?))?\}\}`)
+	blockTagPattern  = regexp.MustCompile(`(?i)<br\s*/?>|</(div|p|li|tr|h[1-6])>`)
+	blankLinePattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// ReadPackage reads the notes of an .apkg or .colpkg. Front is a note's first field and Back its second.
+// Cloze notes become a card with the deletions blanked on the front and shown on the back.
+// Fields come back as plain text.
+func ReadPackage(ctx context.Context, data []byte) ([]Note, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPackage, err)
+	}
+
+	var collection []byte
+	for _, name := range packageCollections {
+		file, err := archive.Open(name)
+		if err != nil {
+			continue
+		}
+		collection, err = io.ReadAll(io.LimitReader(file, MaxCollectionSize+1))
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPackage, err)
+		}
+		if len(collection) > MaxCollectionSize {
+			return nil, fmt.Errorf("%w: the collection is larger than %d MB", ErrInvalidPackage, MaxCollectionSize>>20)
+		}
+		if name == "collection.anki21b" {
+			collection, err = decompress(collection)
+			if errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+				return nil, fmt.Errorf("%w: the collection is larger than %d MB", ErrInvalidPackage, MaxCollectionSize>>20)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidPackage, err)
+			}
+		}
+		break
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("%w: no collection in the package", ErrInvalidPackage)
+	}
+
+	dir, err := os.MkdirTemp("", "apkg-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, collectionFile)
+	if err := os.WriteFile(path, collection, 0o600); err != nil {
+		return nil, err
+	}
+
+	return readNotes(ctx, path)
+}
+
+func readNotes(ctx context.Context, path string) ([]Note, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `SELECT guid, flds, tags FROM notes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPackage, err)
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var guid, fields, tags string
+		if err := rows.Scan(&guid, &fields, &tags); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPackage, err)
+		}
+
+		values := strings.Split(fields, fieldSeparator)
+		note := Note{
+			GUID: guid,
+			Tags: strings.Fields(tags),
+		}
+
+		if clozePattern.MatchString(values[0]) {
+			note.Front = PlainText(clozePattern.ReplaceAllStringFunc(values[0], clozeBlank))
+			note.Back = PlainText(clozePattern.ReplaceAllString(values[0], "$1"))
+			if len(values) > 1 && PlainText(values[1]) != "" {
+				note.Back += "\n\n" + PlainText(values[1])
+			}
+		} else {
+			note.Front = PlainText(values[0])
+			if len(values) > 1 {
+				note.Back = PlainText(values[1])
+			}
+		}
+
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPackage, err)
+	}
+
+	return notes, nil
+}
+
+// PlainText turns the HTML of a field into text, keeping line breaks. Media and styling are dropped.
+func PlainText(field string) string {
+	field = blockTagPattern.ReplaceAllString(field, "\n")
+	field = html.UnescapeString(htmlTagPattern.ReplaceAllString(field, ""))
+	field = strings.ReplaceAll(field, "\u00a0", " ") // &nbsp;
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(field, "\n\n"))
+}
+
+// clozeBlank hides a deletion, showing its hint when it has one
+func clozeBlank(deletion string) string {
+	groups := clozePattern.FindStringSubmatch(deletion)
+	if groups[2] != "" {
+		return "[" + groups[2] + "]"
+	}
+	return "[...]"
+}
+
+// decompress unpacks an anki21b collection, refusing to grow it past MaxCollectionSize
+func decompress(data []byte) ([]byte, error) {
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxCollectionSize))
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+
+	return decoder.DecodeAll(data, nil)
+}
@@ -6,11 +6,11 @@ import (
 	"net/url"
 )
 
-func (ftr ImageAnalyzer[T]) AnalyzeURL(ctx context.Context, kind AnalysisType, imageURL *url.URL) (*T, error) {
+func (ftr ImageAnalyzer[T]) AnalyzeURL(ctx context.Context, task string, imageURL *url.URL) (*T, error) {
 	var x T
 
 	response, err := ftr.queryImageURL(ctx, AIQueryImageParams{
-		Instructions: analysisInstructions(kind, x.Describe()),
+		Instructions: analysisInstructions(task, x.Describe()),
 		ImageURL:     imageURL,
 	})
 	if err != nil {
@@ -46,3 +46,24 @@ func (ftr ImageAnalyzer[T]) ExtractText(
 
 	return &result, nil
 }
+
+// ExtractTextFromImage is ExtractText for an image already in memory, such as a rasterized PDF page
+func (ftr ImageAnalyzer[T]) ExtractTextFromImage(
+	ctx context.Context,
+	image []byte,
+	mimeType string,
+) (*T, error) {
+	var x T
+
+	response, err := ftr.queryImage(ctx, imageTextExtractionInstructions(x.Describe()), image, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var result T
+	if err := json.Unmarshal([]byte(*response), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -4,8 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/url"
-
-	"github.com/openai/openai-go/v3"
+	"server/api/tools/features/llm"
 )
 
 type describable_type interface {
@@ -13,19 +12,21 @@ type describable_type interface {
 }
 
 type image_analyzer_interface[T describable_type] interface {
-	AnalyzeURL(ctx context.Context, kind AnalysisType, imageURL *url.URL) (*T, error)
+	AnalyzeURL(ctx context.Context, task string, imageURL *url.URL) (*T, error)
 	ExtractText(ctx context.Context, imageURL *url.URL) (*T, error)
+	ExtractTextFromImage(ctx context.Context, image []byte, mimeType string) (*T, error)
 
 	// Internal
 	queryImageURL(ctx context.Context, request AIQueryImageParams) (*string, error)
+	queryImage(ctx context.Context, instructions string, image []byte, mimeType string) (*string, error)
 }
 
 type ImageAnalyzer[T describable_type] struct {
-	AI *openai.Client
+	AI llm.Provider
 }
 
 type NewImageAnalyzerParams struct {
-	AI *openai.Client
+	AI llm.Provider
 }
 
 func NewImageAnalyzer[T describable_type](data NewImageAnalyzerParams) (*ImageAnalyzer[T], error) {
@@ -37,10 +38,6 @@ func NewImageAnalyzer[T describable_type](data NewImageAnalyzerParams) (*ImageAn
 	return ftr.(*ImageAnalyzer[T]), nil
 }
 
-const ( // Consts
-	ChatModel = openai.ChatModelGPT5ChatLatest
-)
-
 var ( // Errors
 	ErrNoSchema error = errors.New("no schema specified")
 )
@@ -2,14 +2,11 @@ package imageanalysis
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-
-	"github.com/openai/openai-go/v3"
-	"github.com/openai/openai-go/v3/responses"
+	"server/api/tools/features/llm"
 )
 
 type AIQueryImageParams struct {
@@ -38,46 +35,24 @@ func (ftr ImageAnalyzer[T]) queryImageURL(ctx context.Context, request AIQueryIm
 		return nil, fmt.Errorf("failed to read image bytes: %w", err)
 	}
 
-	// 2️⃣ Encode as base64 data URL
 	mimeType := httpResp.Header.Get("Content-Type")
 	if mimeType == "" {
 		mimeType = "application/octet-stream"
 	}
-	dataURL := fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(imageBytes))
 
-	// 3️⃣ Prepare input for OpenAI
-	inputImageParam := &responses.ResponseInputImageParam{
-		Type:     "input_image",                          // required
-		Detail:   responses.ResponseInputImageDetailAuto, // choose appropriate detail level
-		ImageURL: openai.String(dataURL),                 // send bytes as data URL
-	}
+	// 2️⃣ Send to the configured provider
+	return ftr.queryImage(ctx, request.Instructions, imageBytes, mimeType)
+}
 
-	// 4️⃣ Send request
-	response, err := ftr.AI.Responses.New(ctx, responses.ResponseNewParams{
-		Model:        ChatModel,
-		Instructions: openai.String(request.Instructions),
-		Input: responses.ResponseNewParamsInputUnion{
-			OfInputItemList: responses.ResponseInputParam{
-				responses.ResponseInputItemUnionParam{
-					OfMessage: &responses.EasyInputMessageParam{
-						Role: "user",
-						Type: "message",
-						Content: responses.EasyInputMessageContentUnionParam{
-							OfInputItemContentList: responses.ResponseInputMessageContentListParam{
-								{
-									OfInputImage: inputImageParam,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
+func (ftr ImageAnalyzer[T]) queryImage(ctx context.Context, instructions string, image []byte, mimeType string) (*string, error) {
+	response, err := ftr.AI.DescribeImage(ctx, llm.ImageRequest{
+		Instructions: instructions,
+		Image:        image,
+		MimeType:     mimeType,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI request failed: %w", err)
+		return nil, err
 	}
 
-	result := response.OutputText()
-	return &result, nil
+	return &response.Text, nil
 }
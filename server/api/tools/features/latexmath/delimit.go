@@ -0,0 +1,185 @@
+package latexmath
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Math is found the way the frontend's Latex component finds it, so exports render it as the app does
+
+var (
+	// $$...$$ or \[...\], then $...$ or \(...\). One pattern, so converted math isn't matched again.
+	mathPattern = regexp.MustCompile(`\$\$([\s\S]*?)\$\$|\\\[([\s\S]*?)\\\]|\$([^$]+?)\$|\\\(([\s\S]*?)\\\)`)
+
+	// Math as Delimit leaves it
+	delimitedPattern = regexp.MustCompile(`\\\[([\s\S]*?)\\\]|\\\(([\s\S]*?)\\\)`)
+
+	hasMath = regexp.MustCompile(`\$\$[\s\S]*?\$\$|\\\[[\s\S]*?\\\]|\$[^$]+?\$|\\\([\s\S]*?\\\)`)
+
+	exponentPattern  = regexp.MustCompile(`(\|?\w+\|?)\^(\{[^}]+\}|\d+)`)
+	subscriptPattern = regexp.MustCompile(`\b([a-zA-Z])_(\{[^}]+\}|\d+)`)
+	sqrtPattern      = regexp.MustCompile(`(?i)sqrt\(([^)]+)\)`)
+	radicalPattern   = regexp.MustCompile(`√(\w+|\d+)`)
+	greekPattern     = regexp.MustCompile(`(?i)\b(alpha|beta|gamma|delta|epsilon|zeta|eta|theta|iota|kappa|lambda|mu|nu|xi|pi|rho|sigma|tau|upsilon|phi|chi|psi|omega)\b`)
+	functionPattern  = regexp.MustCompile(`(?i)\b(sin|cos|tan|cot|sec|csc|arcsin|arccos|arctan|sinh|cosh|tanh|log|ln|exp)\(([^)]+)\)`)
+	fractionPattern  = regexp.MustCompile(`\b(\d+|[a-zA-Z])\s*/\s*(\d+|[a-zA-Z])\b`)
+	emptyMathPattern = regexp.MustCompile(`\$\s*\$`)
+
+	commandPattern = regexp.MustCompile(`^[a-zA-Z]+`)
+)
+
+var greekLetters = map[string]string{
+	"alpha": `\alpha`, "beta": `\beta`, "gamma": `\gamma`, "delta": `\delta`,
+	"epsilon": `\epsilon`, "zeta": `\zeta`, "eta": `\eta`, "theta": `\theta`,
+	"iota": `\iota`, "kappa": `\kappa`, "lambda": `\lambda`, "mu": `\mu`,
+	"nu": `\nu`, "xi": `\xi`, "pi": `\pi`, "rho": `\rho`,
+	"sigma": `\sigma`, "tau": `\tau`, "upsilon": `\upsilon`, "phi": `\phi`,
+	"chi": `\chi`, "psi": `\psi`, "omega": `\omega`,
+}
+
+// Delimit rewrites the math in a text with LaTeX's own delimiters, which MathJax also reads:
+// \(...\) inline and \[...\] on display. Text without any delimiters has common notation
+// such as x^2, sqrt(x) and 1/2 detected first.
+func Delimit(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	text = detectMath(text)
+
+	return replaceSubmatches(mathPattern, text, func(groups []string) string {
+		switch {
+		case strings.HasPrefix(groups[0], "$$") || strings.HasPrefix(groups[0], `\[`):
+			return `\[` + strings.TrimSpace(groups[1]+groups[2]) + `\]`
+		default:
+			return `\(` + strings.TrimSpace(groups[3]+groups[4]) + `\)`
+		}
+	})
+}
+
+// Segment is a run of text, or of math without its delimiters
+type Segment struct {
+	Text    string
+	Math    bool
+	Display bool
+}
+
+// Split breaks a text into prose and math, detecting math as Delimit does
+func Split(text string) []Segment {
+	text = Delimit(text)
+
+	var segments []Segment
+	last := 0
+	for _, match := range delimitedPattern.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] > last {
+			segments = append(segments, Segment{Text: text[last:match[0]]})
+		}
+		if match[2] >= 0 {
+			segments = append(segments, Segment{Text: text[match[2]:match[3]], Math: true, Display: true})
+		} else {
+			segments = append(segments, Segment{Text: text[match[4]:match[5]], Math: true})
+		}
+		last = match[1]
+	}
+	if last < len(text) {
+		segments = append(segments, Segment{Text: text[last:]})
+	}
+
+	return segments
+}
+
+// Balanced reports whether math, without its delimiters, has its braces, \left and \right, and
+// \begin and \end paired, so that it can be set without breaking the text around it
+func Balanced(math string) bool {
+	braces, delimiters, environments := 0, 0, 0
+	for i := 0; i < len(math); i++ {
+		switch math[i] {
+		case '{':
+			braces++
+		case '}':
+			braces--
+		case '\\':
+			command := commandPattern.FindString(math[i+1:])
+			switch command {
+			case "left":
+				delimiters++
+			case "right":
+				delimiters--
+			case "begin":
+				environments++
+			case "end":
+				environments--
+			}
+			if command == "" {
+				i++ // An escaped character, such as \{
+			} else {
+				i += len(command)
+			}
+		}
+		if braces < 0 || delimiters < 0 || environments < 0 {
+			return false
+		}
+	}
+	return braces == 0 && delimiters == 0 && environments == 0
+}
+
+// detectMath wraps clear mathematical notation in $ delimiters, unless the text already has delimiters
+func detectMath(text string) string {
+	if hasMath.MatchString(text) {
+		return text
+	}
+
+	text = replaceSubmatches(exponentPattern, text, func(groups []string) string {
+		return "$" + groups[1] + "^" + braced(groups[2]) + "$"
+	})
+	text = replaceSubmatches(subscriptPattern, text, func(groups []string) string {
+		return "$" + groups[1] + "_" + braced(groups[2]) + "$"
+	})
+	text = replaceSubmatches(sqrtPattern, text, func(groups []string) string {
+		return `$\sqrt{` + groups[1] + `}$`
+	})
+	text = replaceSubmatches(radicalPattern, text, func(groups []string) string {
+		return `$\sqrt{` + groups[1] + `}$`
+	})
+	text = replaceSubmatches(greekPattern, text, func(groups []string) string {
+		if latex, ok := greekLetters[strings.ToLower(groups[1])]; ok {
+			return "$" + latex + "$"
+		}
+		return groups[0]
+	})
+	text = replaceSubmatches(functionPattern, text, func(groups []string) string {
+		return `$\` + strings.ToLower(groups[1]) + "(" + groups[2] + ")$"
+	})
+	text = replaceSubmatches(fractionPattern, text, func(groups []string) string {
+		return `$\frac{` + groups[1] + "}{" + groups[2] + "}$"
+	})
+
+	// Merge adjacent math
+	return emptyMathPattern.ReplaceAllString(text, " ")
+}
+
+func braced(expression string) string {
+	if strings.HasPrefix(expression, "{") {
+		return expression
+	}
+	return "{" + expression + "}"
+}
+
+// replaceSubmatches is ReplaceAllStringFunc with the match's groups passed to replace
+func replaceSubmatches(pattern *regexp.Regexp, text string, replace func(groups []string) string) string {
+	var result strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		groups := make([]string, len(match)/2)
+		for i := range groups {
+			if match[2*i] >= 0 {
+				groups[i] = text[match[2*i]:match[2*i+1]]
+			}
+		}
+		result.WriteString(text[last:match[0]])
+		result.WriteString(replace(groups))
+		last = match[1]
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
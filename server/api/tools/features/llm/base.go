@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Provider is a model vendor able to answer text prompts and read images
+type Provider interface {
+	Name() string
+	Model() string // Model requests are sent to
+	Complete(ctx context.Context, request CompletionRequest) (*Completion, error)
+	DescribeImage(ctx context.Context, request ImageRequest) (*Completion, error)
+}
+
+type CompletionRequest struct {
+	Instructions string
+	Input        string
+
+	// Schema, when set, is the JSON schema the response must follow. Providers that can't
+	// enforce it natively rely on the instructions, which already include it.
+	Schema string
+
+	// OnDelta, when set, streams the response and receives each piece of text as it is generated.
+	// The returned Completion still holds the full text.
+	OnDelta func(delta string)
+}
+
+type ImageRequest struct {
+	Instructions string
+	Image        []byte
+	MimeType     string
+}
+
+type Completion struct {
+	Text  string
+	Model string
+
+	// Tokens billed for the request, as reported by the provider
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// Registry holds the configured providers and the deployment default
+type Registry struct {
+	providers   map[string]Provider
+	defaultName string
+}
+
+type NewRegistryParams struct {
+	Default   string
+	Providers []Provider
+}
+
+func NewRegistry(data NewRegistryParams) (*Registry, error) {
+	registry := &Registry{
+		providers:   make(map[string]Provider, len(data.Providers)),
+		defaultName: data.Default,
+	}
+
+	for _, provider := range data.Providers {
+		registry.providers[provider.Name()] = provider
+	}
+
+	if _, ok := registry.providers[data.Default]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, data.Default)
+	}
+
+	return registry, nil
+}
+
+// Get returns the named provider, or the deployment default when name is empty
+func (registry *Registry) Get(name string) (Provider, error) {
+	if name == "" {
+		name = registry.defaultName
+	}
+
+	provider, ok := registry.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+
+	return provider, nil
+}
+
+// Default returns the deployment default provider
+func (registry *Registry) Default() Provider {
+	return registry.providers[registry.defaultName]
+}
+
+const ( // Provider names
+	ProviderOpenAI = "openai"
+	ProviderGemini = "gemini"
+	ProviderFake   = "fake"
+)
+
+var ( // Errors
+	ErrUnknownProvider error = errors.New("unknown llm provider")
+	ErrNoImage         error = errors.New("no image provided")
+
+	ErrInvalidEmbeddings error = errors.New("invalid embeddings")
+)
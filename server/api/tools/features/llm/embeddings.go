@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// EmbeddingDimensions is the length of every embedding. Models that can produce other lengths are asked
+// for this one, so the vectors of any configured model fit the same column.
+const EmbeddingDimensions = 768
+
+// Embedder turns text into vectors that lie close together when the texts are close in meaning
+type Embedder interface {
+	Name() string
+	Model() string
+	Embed(ctx context.Context, request EmbeddingRequest) (*Embeddings, error)
+}
+
+type EmbeddingRequest struct {
+	Texts []string
+
+	// Query marks the texts as search queries rather than passages to be found. Models that embed
+	// the two differently use it; the others ignore it.
+	Query bool
+}
+
+type Embeddings struct {
+	Vectors [][]float32 // One per text, in order
+	Model   string
+
+	// Tokens billed for the request, as reported by the provider. Zero when it doesn't report them.
+	InputTokens int64
+}
+
+// checkEmbeddings makes sure a provider answered with one vector of the expected length per text
+func checkEmbeddings(request EmbeddingRequest, embeddings *Embeddings) error {
+	if len(embeddings.Vectors) != len(request.Texts) {
+		return fmt.Errorf("%w: %d vectors for %d texts", ErrInvalidEmbeddings, len(embeddings.Vectors), len(request.Texts))
+	}
+	for i, vector := range embeddings.Vectors {
+		if len(vector) != EmbeddingDimensions {
+			return fmt.Errorf("%w: vector %d has %d dimensions, not %d", ErrInvalidEmbeddings, i, len(vector), EmbeddingDimensions)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"server/api/tools/internaltools/jsonschema"
+	"sort"
+	"strings"
+)
+
+const FakeModel = "fake-deterministic"
+
+// FakeProvider answers without a network, for development and tests. The same request always gets the
+// same response: JSON built from the request's Schema when it has one, otherwise a digest of the input.
+type FakeProvider struct{}
+
+func NewFakeProvider() *FakeProvider {
+	var provider Provider = &FakeProvider{}
+	return provider.(*FakeProvider)
+}
+
+func (provider FakeProvider) Name() string {
+	return ProviderFake
+}
+
+func (provider FakeProvider) Model() string {
+	return FakeModel
+}
+
+func (provider FakeProvider) Complete(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	text := fakeText(request.Input)
+	if request.Schema != "" {
+		schema, err := jsonschema.Compile(request.Schema)
+		if err != nil {
+			return nil, err
+		}
+
+		generator := newFakeGenerator(request.Instructions+"\x00"+request.Input, request.Input)
+		document, err := json.Marshal(generator.value(schema, "", 0))
+		if err != nil {
+			return nil, err
+		}
+		text = string(document)
+	}
+
+	// Stream word by word, the way a real model would
+	if request.OnDelta != nil {
+		for _, piece := range strings.SplitAfter(text, " ") {
+			request.OnDelta(piece)
+		}
+	}
+
+	return &Completion{
+		Text:         text,
+		Model:        FakeModel,
+		InputTokens:  fakeTokens(request.Instructions + request.Input),
+		OutputTokens: fakeTokens(text),
+	}, nil
+}
+
+// DescribeImage answers with OCR-style text derived from the image bytes, as the
+// {"content": "..."} document text extraction asks for
+func (provider FakeProvider) DescribeImage(ctx context.Context, request ImageRequest) (*Completion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(request.Image) == 0 {
+		return nil, ErrNoImage
+	}
+
+	sum := sha256.Sum256(request.Image)
+	digest := hex.EncodeToString(sum[:])
+
+	// Pick words by the image's hash so different images read differently
+	words := make([]string, 0, len(sum))
+	for _, b := range sum {
+		words = append(words, fakeVocabulary[int(b)%len(fakeVocabulary)])
+	}
+
+	document, err := json.Marshal(map[string]string{
+		"content": fmt.Sprintf(
+			"Scanned page %s (%d bytes, %s).\n%s.",
+			digest[:12],
+			len(request.Image),
+			request.MimeType,
+			strings.Join(words, " "),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completion{
+		Text:         string(document),
+		Model:        FakeModel,
+		InputTokens:  fakeTokens(request.Instructions) + int64(len(request.Image)/750),
+		OutputTokens: fakeTokens(string(document)),
+	}, nil
+}
+
+var fakeVocabulary = []string{
+	"cell", "energy", "membrane", "protein", "reaction", "system", "theory", "model",
+	"process", "structure", "function", "equation", "variable", "force", "market", "period",
+	"evidence", "analysis", "principle", "concept", "example", "definition", "result", "method",
+}
+
+// sourceLabelPattern matches the [S#] labels analysis content is divided into
+var sourceLabelPattern = regexp.MustCompile(`\[(S\d+)\]`)
+
+// fakeGenerator builds a schema-valid document whose text is drawn from the request input
+type fakeGenerator struct {
+	seed   string // Varies the output between requests
+	words  []string
+	labels []string
+}
+
+func newFakeGenerator(seed string, input string) *fakeGenerator {
+	generator := &fakeGenerator{seed: seed}
+
+	for _, match := range sourceLabelPattern.FindAllStringSubmatch(input, -1) {
+		generator.labels = append(generator.labels, match[1])
+	}
+
+	for _, word := range strings.Fields(sourceLabelPattern.ReplaceAllString(input, " ")) {
+		word = strings.Trim(word, `.,;:!?"'()[]{}`)
+		if len(word) > 2 {
+			generator.words = append(generator.words, word)
+		}
+	}
+	if len(generator.words) == 0 {
+		generator.words = fakeVocabulary
+	}
+
+	return generator
+}
+
+// value generates a value for schema. name is the property it belongs to and index its position in an array.
+func (generator *fakeGenerator) value(schema *jsonschema.Schema, name string, index int) any {
+	if len(schema.Enum) > 0 {
+		return schema.Enum[generator.pick(name, index, len(schema.Enum))]
+	}
+
+	switch schema.Type {
+	case "object":
+		names := make([]string, 0, len(schema.Properties))
+		for property := range schema.Properties {
+			names = append(names, property)
+		}
+		sort.Strings(names)
+
+		object := make(map[string]any, len(names))
+		for _, property := range names {
+			object[property] = generator.value(schema.Properties[property], property, index)
+		}
+		return object
+
+	case "array":
+		// Citations point at the sources the input actually has
+		if name == "sources" {
+			if len(generator.labels) == 0 {
+				return []any{}
+			}
+			return []any{generator.labels[index%len(generator.labels)]}
+		}
+
+		count := 3
+		if schema.MinItems != nil {
+			count = max(count, *schema.MinItems)
+		}
+		if schema.MaxItems != nil {
+			count = min(count, *schema.MaxItems)
+		}
+
+		items := make([]any, 0, count)
+		for i := range count {
+			if schema.Items == nil {
+				items = append(items, nil)
+				continue
+			}
+			items = append(items, generator.value(schema.Items, name, i))
+		}
+		return items
+
+	case "string":
+		return generator.text(schema, name, index)
+
+	case "integer", "number":
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+		return 0
+
+	case "boolean":
+		return false
+
+	default:
+		return nil
+	}
+}
+
+// text generates a string. Graph-shaped fields (id, from, to) get node ids that refer to each other,
+// so results like concept maps stay consistent.
+func (generator *fakeGenerator) text(schema *jsonschema.Schema, name string, index int) string {
+	switch name {
+	case "id":
+		return fmt.Sprintf("n%d", index+1)
+	case "from":
+		return "n1"
+	case "to":
+		return "n2"
+	}
+
+	start := generator.pick(name, index, len(generator.words))
+	phrase := make([]string, 0, 6)
+	for i := range 6 {
+		phrase = append(phrase, generator.words[(start+i)%len(generator.words)])
+	}
+
+	text := strings.Join(phrase, " ")
+	if name != "" {
+		text = fmt.Sprintf("%s %d: %s", name, index+1, text)
+	}
+
+	if schema.MinLength != nil {
+		for len([]rune(text)) < *schema.MinLength {
+			text += " " + text
+		}
+	}
+
+	return text
+}
+
+// pick deterministically chooses an index below n for a field
+func (generator *fakeGenerator) pick(name string, index int, n int) int {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", generator.seed, name, index)))
+	return int(binary.BigEndian.Uint32(sum[:4]) % uint32(n))
+}
+
+// fakeText is the plain-text answer to a request without a schema
+func fakeText(input string) string {
+	words := strings.Fields(input)
+	if len(words) > 40 {
+		words = words[:40]
+	}
+	return "Offline response based on: " + strings.Join(words, " ")
+}
+
+// fakeTokens approximates a token count at roughly four characters per token
+func fakeTokens(text string) int64 {
+	return int64((len([]rune(text)) + 3) / 4)
+}
+
+const FakeEmbeddingModel = "fake-hashed-words"
+
+// FakeEmbedder embeds without a network by hashing each word of a text into a vector. Texts that share
+// words land close together, so search works offline, though it only matches words, not meaning.
+type FakeEmbedder struct{}
+
+func NewFakeEmbedder() *FakeEmbedder {
+	var embedder Embedder = &FakeEmbedder{}
+	return embedder.(*FakeEmbedder)
+}
+
+func (embedder FakeEmbedder) Name() string {
+	return ProviderFake
+}
+
+func (embedder FakeEmbedder) Model() string {
+	return FakeEmbeddingModel
+}
+
+func (embedder FakeEmbedder) Embed(ctx context.Context, request EmbeddingRequest) (*Embeddings, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	embeddings := &Embeddings{
+		Vectors: make([][]float32, 0, len(request.Texts)),
+		Model:   FakeEmbeddingModel,
+	}
+	for _, text := range request.Texts {
+		embeddings.Vectors = append(embeddings.Vectors, fakeEmbedding(text))
+		embeddings.InputTokens += fakeTokens(text)
+	}
+
+	return embeddings, nil
+}
+
+// fakeEmbedding adds each word into the dimension its hash picks, then normalizes the vector
+func fakeEmbedding(text string) []float32 {
+	vector := make([]float32, EmbeddingDimensions)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, `.,;:!?"'()[]{}`)
+		if word == "" {
+			continue
+		}
+
+		sum := sha256.Sum256([]byte(word))
+		index := binary.BigEndian.Uint32(sum[:4]) % EmbeddingDimensions
+		if sum[4]&1 == 0 {
+			vector[index]++
+		} else {
+			vector[index]--
+		}
+	}
+
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
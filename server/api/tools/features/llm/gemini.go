@@ -0,0 +1,181 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+type GeminiProvider struct {
+	Client          *genai.Client
+	CompletionModel string
+}
+
+func NewGeminiProvider(client *genai.Client, model string) *GeminiProvider {
+	var provider Provider = &GeminiProvider{
+		Client:          client,
+		CompletionModel: model,
+	}
+	return provider.(*GeminiProvider)
+}
+
+func (provider GeminiProvider) Name() string {
+	return ProviderGemini
+}
+
+func (provider GeminiProvider) Model() string {
+	return provider.CompletionModel
+}
+
+func (provider GeminiProvider) Complete(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	if request.OnDelta != nil {
+		return provider.stream(ctx, request.Instructions, genai.NewPartFromText(request.Input), request.OnDelta)
+	}
+	return provider.generate(ctx, request.Instructions, genai.NewPartFromText(request.Input))
+}
+
+func (provider GeminiProvider) DescribeImage(ctx context.Context, request ImageRequest) (*Completion, error) {
+	if len(request.Image) == 0 {
+		return nil, ErrNoImage
+	}
+
+	return provider.generate(ctx, request.Instructions, genai.NewPartFromBytes(request.Image, request.MimeType))
+}
+
+func (provider GeminiProvider) generate(ctx context.Context, instructions string, part *genai.Part) (*Completion, error) {
+	resp, err := provider.Client.Models.GenerateContent(
+		ctx,
+		provider.CompletionModel,
+		[]*genai.Content{genai.NewContentFromParts([]*genai.Part{part}, genai.RoleUser)},
+		&genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(instructions, genai.RoleUser),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Gemini request failed: %w", err)
+	}
+
+	completion := &Completion{
+		Text:  resp.Text(),
+		Model: provider.CompletionModel,
+	}
+	if resp.ModelVersion != "" {
+		completion.Model = resp.ModelVersion
+	}
+	setGeminiUsage(completion, resp.UsageMetadata)
+
+	return completion, nil
+}
+
+// stream generates with streaming enabled, forwarding text deltas as they arrive
+func (provider GeminiProvider) stream(
+	ctx context.Context,
+	instructions string,
+	part *genai.Part,
+	onDelta func(delta string),
+) (*Completion, error) {
+
+	var text strings.Builder
+	completion := &Completion{Model: provider.CompletionModel}
+
+	for resp, err := range provider.Client.Models.GenerateContentStream(
+		ctx,
+		provider.CompletionModel,
+		[]*genai.Content{genai.NewContentFromParts([]*genai.Part{part}, genai.RoleUser)},
+		&genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(instructions, genai.RoleUser),
+		},
+	) {
+		if err != nil {
+			return nil, fmt.Errorf("Gemini request failed: %w", err)
+		}
+
+		if delta := resp.Text(); delta != "" {
+			text.WriteString(delta)
+			onDelta(delta)
+		}
+		if resp.ModelVersion != "" {
+			completion.Model = resp.ModelVersion
+		}
+
+		// Each chunk reports the running total, so the last one holds the request's usage
+		setGeminiUsage(completion, resp.UsageMetadata)
+	}
+
+	completion.Text = text.String()
+	return completion, nil
+}
+
+// setGeminiUsage copies token counts onto a completion. Thinking tokens are billed as output.
+func setGeminiUsage(completion *Completion, usage *genai.GenerateContentResponseUsageMetadata) {
+	if usage == nil {
+		return
+	}
+	completion.InputTokens = int64(usage.PromptTokenCount)
+	completion.OutputTokens = int64(usage.CandidatesTokenCount) + int64(usage.ThoughtsTokenCount)
+}
+
+// GeminiEmbedder embeds text with a Gemini embedding model, shortened to EmbeddingDimensions
+type GeminiEmbedder struct {
+	Client         *genai.Client
+	EmbeddingModel string
+}
+
+func NewGeminiEmbedder(client *genai.Client, model string) *GeminiEmbedder {
+	var embedder Embedder = &GeminiEmbedder{
+		Client:         client,
+		EmbeddingModel: model,
+	}
+	return embedder.(*GeminiEmbedder)
+}
+
+func (embedder GeminiEmbedder) Name() string {
+	return ProviderGemini
+}
+
+func (embedder GeminiEmbedder) Model() string {
+	return embedder.EmbeddingModel
+}
+
+func (embedder GeminiEmbedder) Embed(ctx context.Context, request EmbeddingRequest) (*Embeddings, error) {
+	contents := make([]*genai.Content, 0, len(request.Texts))
+	for _, text := range request.Texts {
+		contents = append(contents, genai.NewContentFromText(text, genai.RoleUser))
+	}
+
+	taskType := "RETRIEVAL_DOCUMENT"
+	if request.Query {
+		taskType = "RETRIEVAL_QUERY"
+	}
+
+	dimensions := int32(EmbeddingDimensions)
+	resp, err := embedder.Client.Models.EmbedContent(ctx, embedder.EmbeddingModel, contents, &genai.EmbedContentConfig{
+		TaskType:             taskType,
+		OutputDimensionality: &dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini request failed: %w", err)
+	}
+
+	// Token counts are only reported by Vertex
+	embeddings := &Embeddings{
+		Vectors: make([][]float32, 0, len(resp.Embeddings)),
+		Model:   embedder.EmbeddingModel,
+	}
+	for _, embedding := range resp.Embeddings {
+		if embedding == nil {
+			return nil, fmt.Errorf("%w: missing vector", ErrInvalidEmbeddings)
+		}
+		embeddings.Vectors = append(embeddings.Vectors, embedding.Values)
+		if embedding.Statistics != nil {
+			embeddings.InputTokens += int64(embedding.Statistics.TokenCount)
+		}
+	}
+
+	if err := checkEmbeddings(request, embeddings); err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
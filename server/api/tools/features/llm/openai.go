@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+type OpenAIProvider struct {
+	Client          *openai.Client
+	CompletionModel string
+}
+
+func NewOpenAIProvider(client *openai.Client, model string) *OpenAIProvider {
+	var provider Provider = &OpenAIProvider{
+		Client:          client,
+		CompletionModel: model,
+	}
+	return provider.(*OpenAIProvider)
+}
+
+func (provider OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (provider OpenAIProvider) Model() string {
+	return provider.CompletionModel
+}
+
+func (provider OpenAIProvider) Complete(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	params := responses.ResponseNewParams{
+		Model:        provider.CompletionModel,
+		Instructions: openai.String(request.Instructions),
+		Input: responses.ResponseNewParamsInputUnion{
+			OfString: openai.String(request.Input),
+		},
+	}
+
+	if request.OnDelta != nil {
+		return provider.stream(ctx, params, request.OnDelta)
+	}
+
+	resp, err := provider.Client.Responses.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI request failed: %w", err)
+	}
+
+	return &Completion{
+		Text:         resp.OutputText(),
+		Model:        resp.Model,
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}, nil
+}
+
+func (provider OpenAIProvider) DescribeImage(ctx context.Context, request ImageRequest) (*Completion, error) {
+	if len(request.Image) == 0 {
+		return nil, ErrNoImage
+	}
+
+	// Send bytes as a base64 data URL
+	dataURL := fmt.Sprintf("data:%s;base64,%s", request.MimeType, base64.StdEncoding.EncodeToString(request.Image))
+
+	inputImageParam := &responses.ResponseInputImageParam{
+		Type:     "input_image",
+		Detail:   responses.ResponseInputImageDetailAuto,
+		ImageURL: openai.String(dataURL),
+	}
+
+	resp, err := provider.Client.Responses.New(ctx, responses.ResponseNewParams{
+		Model:        provider.CompletionModel,
+		Instructions: openai.String(request.Instructions),
+		Input: responses.ResponseNewParamsInputUnion{
+			OfInputItemList: responses.ResponseInputParam{
+				responses.ResponseInputItemUnionParam{
+					OfMessage: &responses.EasyInputMessageParam{
+						Role: "user",
+						Type: "message",
+						Content: responses.EasyInputMessageContentUnionParam{
+							OfInputItemContentList: responses.ResponseInputMessageContentListParam{
+								{
+									OfInputImage: inputImageParam,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI request failed: %w", err)
+	}
+
+	return &Completion{
+		Text:         resp.OutputText(),
+		Model:        resp.Model,
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}, nil
+}
+
+// stream runs a request with streaming enabled, forwarding text deltas as they arrive
+func (provider OpenAIProvider) stream(
+	ctx context.Context,
+	params responses.ResponseNewParams,
+	onDelta func(delta string),
+) (*Completion, error) {
+
+	stream := provider.Client.Responses.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	var text strings.Builder
+	completion := &Completion{Model: provider.CompletionModel}
+
+	for stream.Next() {
+		event := stream.Current()
+		switch event.Type {
+		case "response.output_text.delta":
+			text.WriteString(event.Delta)
+			onDelta(event.Delta)
+		case "response.completed":
+			completion.Model = event.Response.Model
+			completion.InputTokens = event.Response.Usage.InputTokens
+			completion.OutputTokens = event.Response.Usage.OutputTokens
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("OpenAI request failed: %w", err)
+	}
+
+	completion.Text = text.String()
+	return completion, nil
+}
+
+// OpenAIEmbedder embeds text with an OpenAI embedding model, shortened to EmbeddingDimensions
+type OpenAIEmbedder struct {
+	Client         *openai.Client
+	EmbeddingModel string
+}
+
+func NewOpenAIEmbedder(client *openai.Client, model string) *OpenAIEmbedder {
+	var embedder Embedder = &OpenAIEmbedder{
+		Client:         client,
+		EmbeddingModel: model,
+	}
+	return embedder.(*OpenAIEmbedder)
+}
+
+func (embedder OpenAIEmbedder) Name() string {
+	return ProviderOpenAI
+}
+
+func (embedder OpenAIEmbedder) Model() string {
+	return embedder.EmbeddingModel
+}
+
+func (embedder OpenAIEmbedder) Embed(ctx context.Context, request EmbeddingRequest) (*Embeddings, error) {
+	resp, err := embedder.Client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Model: embedder.EmbeddingModel,
+		Input: openai.EmbeddingNewParamsInputUnion{
+			OfArrayOfStrings: request.Texts,
+		},
+		Dimensions:     openai.Int(EmbeddingDimensions),
+		EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI request failed: %w", err)
+	}
+
+	embeddings := &Embeddings{
+		Vectors:     make([][]float32, len(request.Texts)),
+		Model:       embedder.EmbeddingModel,
+		InputTokens: resp.Usage.PromptTokens,
+	}
+	for _, data := range resp.Data {
+		if data.Index < 0 || int(data.Index) >= len(request.Texts) {
+			return nil, fmt.Errorf("%w: index %d out of range", ErrInvalidEmbeddings, data.Index)
+		}
+
+		vector := make([]float32, len(data.Embedding))
+		for i, value := range data.Embedding {
+			vector[i] = float32(value)
+		}
+		embeddings.Vectors[data.Index] = vector
+	}
+
+	if err := checkEmbeddings(request, embeddings); err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitedProvider holds every request to the wrapped provider until its limiter allows it.
+// All callers sharing the provider share the budget, so concurrent extractions and analyses can't exceed it.
+type RateLimitedProvider struct {
+	Provider Provider
+	Limiter  *rate.Limiter
+}
+
+// NewRateLimitedProvider limits provider to requestsPerMinute. A limit of zero or less leaves it unlimited.
+func NewRateLimitedProvider(provider Provider, requestsPerMinute int) Provider {
+	if requestsPerMinute <= 0 {
+		return provider
+	}
+
+	var limited Provider = &RateLimitedProvider{
+		Provider: provider,
+		Limiter:  rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), 1),
+	}
+	return limited
+}
+
+func (provider RateLimitedProvider) Name() string {
+	return provider.Provider.Name()
+}
+
+func (provider RateLimitedProvider) Model() string {
+	return provider.Provider.Model()
+}
+
+func (provider RateLimitedProvider) Complete(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	if err := provider.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return provider.Provider.Complete(ctx, request)
+}
+
+func (provider RateLimitedProvider) DescribeImage(ctx context.Context, request ImageRequest) (*Completion, error) {
+	if err := provider.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return provider.Provider.DescribeImage(ctx, request)
+}
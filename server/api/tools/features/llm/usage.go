@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Usage totals what a series of requests to a provider consumed
+type Usage struct {
+	Model        string // Model that answered the most recent request
+	PromptHash   string // SHA-256 of the instructions sent with the first request
+	InputTokens  int64
+	OutputTokens int64
+	Latency      time.Duration // Time spent waiting on requests, including rate limiting
+	Requests     int
+}
+
+// UsageRecorder wraps a provider and totals the usage of every request made through it.
+// Create one per unit of work that should be accounted for separately.
+type UsageRecorder struct {
+	Provider Provider
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+func NewUsageRecorder(provider Provider) *UsageRecorder {
+	var recorder Provider = &UsageRecorder{
+		Provider: provider,
+	}
+	return recorder.(*UsageRecorder)
+}
+
+func (recorder *UsageRecorder) Name() string {
+	return recorder.Provider.Name()
+}
+
+func (recorder *UsageRecorder) Model() string {
+	return recorder.Provider.Model()
+}
+
+func (recorder *UsageRecorder) Complete(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	start := time.Now()
+	completion, err := recorder.Provider.Complete(ctx, request)
+	recorder.record(request.Instructions, completion, time.Since(start))
+	return completion, err
+}
+
+func (recorder *UsageRecorder) DescribeImage(ctx context.Context, request ImageRequest) (*Completion, error) {
+	start := time.Now()
+	completion, err := recorder.Provider.DescribeImage(ctx, request)
+	recorder.record(request.Instructions, completion, time.Since(start))
+	return completion, err
+}
+
+// Usage returns the totals so far
+func (recorder *UsageRecorder) Usage() Usage {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.usage
+}
+
+// record adds one request to the totals. Failed requests count towards latency but have no tokens.
+func (recorder *UsageRecorder) record(instructions string, completion *Completion, latency time.Duration) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	usage := &recorder.usage
+	if usage.Requests == 0 {
+		hash := sha256.Sum256([]byte(instructions))
+		usage.PromptHash = hex.EncodeToString(hash[:])
+	}
+	usage.Requests++
+	usage.Latency += latency
+
+	if completion != nil {
+		usage.Model = completion.Model
+		usage.InputTokens += completion.InputTokens
+		usage.OutputTokens += completion.OutputTokens
+	}
+}
+
+// UsageTracker is anything that totals the usage of the requests made through it
+type UsageTracker interface {
+	Usage() Usage
+}
+
+// EmbeddingRecorder wraps an embedder and totals the usage of every request made through it, like UsageRecorder
+type EmbeddingRecorder struct {
+	Embedder Embedder
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+func NewEmbeddingRecorder(embedder Embedder) *EmbeddingRecorder {
+	var recorder Embedder = &EmbeddingRecorder{
+		Embedder: embedder,
+	}
+	return recorder.(*EmbeddingRecorder)
+}
+
+func (recorder *EmbeddingRecorder) Name() string {
+	return recorder.Embedder.Name()
+}
+
+func (recorder *EmbeddingRecorder) Model() string {
+	return recorder.Embedder.Model()
+}
+
+func (recorder *EmbeddingRecorder) Embed(ctx context.Context, request EmbeddingRequest) (*Embeddings, error) {
+	start := time.Now()
+	embeddings, err := recorder.Embedder.Embed(ctx, request)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	usage := &recorder.usage
+	usage.Requests++
+	usage.Latency += time.Since(start)
+	if embeddings != nil {
+		usage.Model = embeddings.Model
+		usage.InputTokens += embeddings.InputTokens
+	}
+
+	return embeddings, err
+}
+
+// Usage returns the totals so far
+func (recorder *EmbeddingRecorder) Usage() Usage {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.usage
+}
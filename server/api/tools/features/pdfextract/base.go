@@ -0,0 +1,50 @@
+package pdfextract
+
+import (
+	"context"
+	"errors"
+)
+
+// Page is the text of one page of a PDF. Page numbers start at 1.
+type Page struct {
+	Number int
+	Text   string
+}
+
+// HasText reports whether the page carries a usable text layer.
+// Scanned pages often hold nothing but a stray page number, so a few characters don't count.
+func (page Page) HasText() bool {
+	count := 0
+	for _, r := range page.Text {
+		if !isSpace(r) {
+			count++
+		}
+	}
+	return count >= MinTextRunes
+}
+
+// Rasterizer renders a single PDF page to an image for vision OCR
+type Rasterizer interface {
+	RasterizePage(ctx context.Context, document []byte, page int) (*Image, error)
+}
+
+type Image struct {
+	Data     []byte
+	MimeType string
+}
+
+const (
+	// MinTextRunes is the number of non-space characters below which a page is treated as scanned
+	MinTextRunes = 16
+
+	MimeType = "application/pdf"
+)
+
+var ( // Errors
+	ErrInvalidPDF   error = errors.New("invalid pdf")
+	ErrNoRasterizer error = errors.New("no pdf rasterizer available")
+)
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v'
+}
@@ -0,0 +1,73 @@
+package pdfextract
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// PopplerRasterizer renders pages with poppler's pdftoppm
+type PopplerRasterizer struct {
+	Binary string
+	DPI    int
+}
+
+type NewPopplerRasterizerParams struct {
+	Binary string // Path or name of pdftoppm
+	DPI    int
+}
+
+// NewPopplerRasterizer returns ErrNoRasterizer when the pdftoppm binary can't be found
+func NewPopplerRasterizer(data NewPopplerRasterizerParams) (*PopplerRasterizer, error) {
+	binary, err := exec.LookPath(data.Binary)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoRasterizer, err)
+	}
+
+	var rasterizer Rasterizer = &PopplerRasterizer{
+		Binary: binary,
+		DPI:    data.DPI,
+	}
+	return rasterizer.(*PopplerRasterizer), nil
+}
+
+func (rasterizer PopplerRasterizer) RasterizePage(ctx context.Context, document []byte, page int) (*Image, error) {
+	dir, err := os.MkdirTemp("", "pdfextract-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "document.pdf")
+	if err := os.WriteFile(input, document, 0o600); err != nil {
+		return nil, err
+	}
+
+	// -singlefile writes exactly <root>.png instead of numbering the output
+	root := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, rasterizer.Binary,
+		"-png",
+		"-r", strconv.Itoa(rasterizer.DPI),
+		"-f", strconv.Itoa(page),
+		"-l", strconv.Itoa(page),
+		"-singlefile",
+		input,
+		root,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm failed on page %d: %w: %s", page, err, output)
+	}
+
+	data, err := os.ReadFile(root + ".png")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		Data:     data,
+		MimeType: "image/png",
+	}, nil
+}
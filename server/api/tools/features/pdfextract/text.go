@@ -0,0 +1,54 @@
+package pdfextract
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// TextPages reads the embedded text layer of every page. Pages without one come back with empty text.
+func TextPages(document []byte) (pages []Page, err error) {
+	// The parser panics on malformed or unsupported input rather than returning errors
+	defer func() {
+		if r := recover(); r != nil {
+			pages = nil
+			err = fmt.Errorf("%w: %v", ErrInvalidPDF, r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(document), int64(len(document)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPDF, err)
+	}
+
+	fonts := map[string]*pdf.Font{}
+	total := reader.NumPage()
+	pages = make([]Page, 0, total)
+
+	for number := 1; number <= total; number++ {
+		page := reader.Page(number)
+
+		// Cache fonts across pages so their character maps are only parsed once
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+
+		text, err := page.GetPlainText(fonts)
+		if err != nil {
+			// A page whose content can't be decoded is handled like a scanned one
+			text = ""
+		}
+
+		pages = append(pages, Page{
+			Number: number,
+			Text:   strings.TrimSpace(text),
+		})
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,60 @@
+package studyguide
+
+import (
+	"time"
+)
+
+// Guide is everything a study guide holds. Text may carry LaTeX math, in any notation the app renders.
+type Guide struct {
+	Title  string // Collection title
+	Course string
+	Date   time.Time
+
+	Overview   *Summary // nil when there is no summary
+	Concepts   []Concept
+	Flashcards []Flashcard
+	Quiz       []QuizQuestion
+}
+
+type Summary struct {
+	Text    string
+	Sources []string
+}
+
+// Concept is one section of the guide
+type Concept struct {
+	Name       string
+	Definition string
+	Details    string
+	Sources    []string
+}
+
+type Flashcard struct {
+	Question string
+	Answer   string
+}
+
+type QuizQuestion struct {
+	Question     string
+	Options      []string
+	CorrectIndex int
+}
+
+const (
+	MarkdownMimeType = "text/markdown; charset=utf-8"
+	LaTeXMimeType    = "application/x-tex"
+	PDFMimeType      = "application/pdf"
+
+	overviewTitle   = "Overview"
+	flashcardsTitle = "Appendix A: Flashcards"
+	answerKeyTitle  = "Appendix B: Quiz answer key"
+	dateLayout      = "2 January 2006"
+)
+
+// optionLabel letters quiz options: A, B, C...
+func optionLabel(index int) string {
+	if index < 26 {
+		return string(rune('A' + index))
+	}
+	return string(rune('A'+index/26-1)) + string(rune('A'+index%26))
+}
@@ -0,0 +1,130 @@
+package studyguide
+
+import (
+	"bytes"
+	"fmt"
+	"server/api/tools/features/latexmath"
+	"strings"
+)
+
+// fontspec reads the source as UTF-8 and sets text in OpenType fonts, so accented and non-Latin text
+// compiles where pdflatex's inputenc would stop at the first character it has no definition for
+const latexPreamble = `\documentclass[11pt,titlepage]{article}
+\usepackage{fontspec}
+\usepackage{amsmath,amssymb}
+\usepackage[margin=2.5cm]{geometry}
+\usepackage{enumitem}
+\usepackage[hidelinks]{hyperref}
+`
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+
+	// Symbols the text fonts may lack, set as math so they print in any font
+	`α`, `\(\alpha\)`, `β`, `\(\beta\)`, `γ`, `\(\gamma\)`, `δ`, `\(\delta\)`, `ε`, `\(\epsilon\)`,
+	`θ`, `\(\theta\)`, `λ`, `\(\lambda\)`, `μ`, `\(\mu\)`, `π`, `\(\pi\)`, `ρ`, `\(\rho\)`,
+	`σ`, `\(\sigma\)`, `τ`, `\(\tau\)`, `φ`, `\(\phi\)`, `ω`, `\(\omega\)`,
+	`Γ`, `\(\Gamma\)`, `Δ`, `\(\Delta\)`, `Θ`, `\(\Theta\)`, `Λ`, `\(\Lambda\)`, `Π`, `\(\Pi\)`,
+	`Σ`, `\(\Sigma\)`, `Φ`, `\(\Phi\)`, `Ω`, `\(\Omega\)`,
+	`≤`, `\(\leq\)`, `≥`, `\(\geq\)`, `≠`, `\(\neq\)`, `≈`, `\(\approx\)`, `±`, `\(\pm\)`,
+	`×`, `\(\times\)`, `÷`, `\(\div\)`, `∞`, `\(\infty\)`, `√`, `\(\surd\)`, `∑`, `\(\sum\)`,
+	`∫`, `\(\int\)`, `∂`, `\(\partial\)`, `∈`, `\(\in\)`, `→`, `\(\rightarrow\)`, `←`, `\(\leftarrow\)`,
+	`⇒`, `\(\Rightarrow\)`, `⇔`, `\(\Leftrightarrow\)`, `°`, `\(^\circ\)`,
+)
+
+// LaTeX renders the guide as a standalone UTF-8 document that compiles with xelatex or lualatex
+func LaTeX(guide Guide) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(latexPreamble)
+	fmt.Fprintf(&buf, "\\title{%s}\n", latexText(guide.Title))
+	fmt.Fprintf(&buf, "\\author{%s}\n", latexText(guide.Course))
+	fmt.Fprintf(&buf, "\\date{%s}\n\n", guide.Date.Format(dateLayout))
+	buf.WriteString("\\begin{document}\n\n\\maketitle\n\\tableofcontents\n\\newpage\n\n")
+
+	if guide.Overview != nil {
+		fmt.Fprintf(&buf, "\\section{%s}\n\n%s\n\n", overviewTitle, latexText(guide.Overview.Text))
+		latexSources(&buf, guide.Overview.Sources)
+	}
+
+	for _, concept := range guide.Concepts {
+		fmt.Fprintf(&buf, "\\section{%s}\n\n", latexText(concept.Name))
+		if concept.Definition != "" {
+			fmt.Fprintf(&buf, "\\textbf{Definition.} %s\n\n", latexText(concept.Definition))
+		}
+		if concept.Details != "" {
+			fmt.Fprintf(&buf, "%s\n\n", latexText(concept.Details))
+		}
+		latexSources(&buf, concept.Sources)
+	}
+
+	if len(guide.Flashcards) > 0 || len(guide.Quiz) > 0 {
+		buf.WriteString("\\appendix\n\n")
+	}
+
+	if len(guide.Flashcards) > 0 {
+		fmt.Fprintf(&buf, "\\section*{%s}\n\\addcontentsline{toc}{section}{%s}\n\n", flashcardsTitle, flashcardsTitle)
+		buf.WriteString("\\begin{enumerate}\n")
+		for _, card := range guide.Flashcards {
+			fmt.Fprintf(&buf, "  \\item \\textbf{%s}\\\\\n  %s\n", latexText(card.Question), latexText(card.Answer))
+		}
+		buf.WriteString("\\end{enumerate}\n\n")
+	}
+
+	if len(guide.Quiz) > 0 {
+		fmt.Fprintf(&buf, "\\section*{%s}\n\\addcontentsline{toc}{section}{%s}\n\n", answerKeyTitle, answerKeyTitle)
+		buf.WriteString("\\begin{enumerate}\n")
+		for _, question := range guide.Quiz {
+			fmt.Fprintf(&buf, "  \\item %s\n", latexText(question.Question))
+			buf.WriteString("  \\begin{enumerate}[label=\\Alph*.]\n")
+			for j, option := range question.Options {
+				if j == question.CorrectIndex {
+					fmt.Fprintf(&buf, "    \\item \\textbf{%s} (correct)\n", latexText(option))
+				} else {
+					fmt.Fprintf(&buf, "    \\item %s\n", latexText(option))
+				}
+			}
+			buf.WriteString("  \\end{enumerate}\n")
+		}
+		buf.WriteString("\\end{enumerate}\n\n")
+	}
+
+	buf.WriteString("\\end{document}\n")
+
+	return buf.Bytes()
+}
+
+func latexSources(buf *bytes.Buffer, sources []string) {
+	if len(sources) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\\textit{Sources: %s}\n\n", latexText(strings.Join(sources, "; ")))
+}
+
+// latexText escapes prose and leaves math as it is. Math that would stop the document compiling, such as
+// an unclosed brace, is printed as its source instead.
+func latexText(text string) string {
+	var result strings.Builder
+	for _, segment := range latexmath.Split(text) {
+		switch {
+		case segment.Math && !latexmath.Balanced(segment.Text):
+			result.WriteString(`\texttt{` + latexEscaper.Replace(segment.Text) + `}`)
+		case segment.Display:
+			result.WriteString(`\[` + segment.Text + `\]`)
+		case segment.Math:
+			result.WriteString(`\(` + segment.Text + `\)`)
+		default:
+			result.WriteString(latexEscaper.Replace(segment.Text))
+		}
+	}
+	return strings.TrimSpace(result.String())
+}
@@ -0,0 +1,81 @@
+package studyguide
+
+import (
+	"bytes"
+	"fmt"
+	"server/api/tools/features/latexmath"
+	"strings"
+)
+
+// Markdown renders the guide with math in $...$ and $$...$$, which most Markdown renderers read
+func Markdown(guide Guide) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s\n\n", markdownText(guide.Title))
+	if guide.Course != "" {
+		fmt.Fprintf(&buf, "**Course:** %s  \n", markdownText(guide.Course))
+	}
+	fmt.Fprintf(&buf, "**Generated:** %s\n\n---\n\n", guide.Date.Format(dateLayout))
+
+	if guide.Overview != nil {
+		fmt.Fprintf(&buf, "## %s\n\n%s\n\n", overviewTitle, markdownText(guide.Overview.Text))
+		markdownSources(&buf, guide.Overview.Sources)
+	}
+
+	for _, concept := range guide.Concepts {
+		fmt.Fprintf(&buf, "## %s\n\n", markdownText(concept.Name))
+		if concept.Definition != "" {
+			fmt.Fprintf(&buf, "**Definition.** %s\n\n", markdownText(concept.Definition))
+		}
+		if concept.Details != "" {
+			fmt.Fprintf(&buf, "%s\n\n", markdownText(concept.Details))
+		}
+		markdownSources(&buf, concept.Sources)
+	}
+
+	if len(guide.Flashcards) > 0 {
+		fmt.Fprintf(&buf, "## %s\n\n", flashcardsTitle)
+		for i, card := range guide.Flashcards {
+			fmt.Fprintf(&buf, "**%d. %s**\n\n%s\n\n", i+1, markdownText(card.Question), markdownText(card.Answer))
+		}
+	}
+
+	if len(guide.Quiz) > 0 {
+		fmt.Fprintf(&buf, "## %s\n\n", answerKeyTitle)
+		for i, question := range guide.Quiz {
+			fmt.Fprintf(&buf, "%d. %s\n", i+1, markdownText(question.Question))
+			for j, option := range question.Options {
+				if j == question.CorrectIndex {
+					fmt.Fprintf(&buf, "   - **%s. %s** (correct)\n", optionLabel(j), markdownText(option))
+				} else {
+					fmt.Fprintf(&buf, "   - %s. %s\n", optionLabel(j), markdownText(option))
+				}
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func markdownSources(buf *bytes.Buffer, sources []string) {
+	if len(sources) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "*Sources: %s*\n\n", markdownText(strings.Join(sources, "; ")))
+}
+
+func markdownText(text string) string {
+	var result strings.Builder
+	for _, segment := range latexmath.Split(text) {
+		switch {
+		case segment.Display:
+			result.WriteString("$$" + segment.Text + "$$")
+		case segment.Math:
+			result.WriteString("$" + segment.Text + "$")
+		default:
+			result.WriteString(segment.Text)
+		}
+	}
+	return strings.TrimSpace(result.String())
+}
@@ -0,0 +1,201 @@
+package studyguide
+
+import (
+	"bytes"
+	"fmt"
+	"server/api/tools/features/latexmath"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	pdfMargin     = 20.0 // mm
+	pdfLineHeight = 6.0
+	pdfFontSize   = 11.0
+
+	// The Go fonts are compiled in and embedded as UTF-8 fonts, covering Latin, Greek and Cyrillic.
+	// Math is set in Go Mono, as its LaTeX source: typesetting it would need TeX.
+	pdfFont     = "Go"
+	pdfMathFont = "Go Mono"
+)
+
+type pdfWriter struct {
+	pdf *gofpdf.Fpdf
+}
+
+// PDF renders the guide with fonts compiled into the server, so it needs no font files or external tools
+func PDF(guide Guide) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "I", goitalic.TTF)
+	pdf.AddUTF8FontFromBytes(pdfMathFont, "", gomono.TTF)
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle(guide.Title, true)
+	pdf.SetAuthor(guide.Course, true)
+	pdf.SetCreator("Study guide export", true)
+
+	w := &pdfWriter{pdf: pdf}
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return // Title page
+		}
+		pdf.SetY(-pdfMargin + 5)
+		pdf.SetFont(pdfFont, "", 9)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	w.titlePage(guide)
+
+	pdf.AddPage()
+	if guide.Overview != nil {
+		w.heading(overviewTitle)
+		w.paragraph(guide.Overview.Text, "")
+		w.sources(guide.Overview.Sources)
+	}
+
+	for _, concept := range guide.Concepts {
+		w.heading(concept.Name)
+		if concept.Definition != "" {
+			w.paragraph(concept.Definition, "Definition. ")
+		}
+		if concept.Details != "" {
+			w.paragraph(concept.Details, "")
+		}
+		w.sources(concept.Sources)
+	}
+
+	if len(guide.Flashcards) > 0 {
+		pdf.AddPage()
+		w.heading(flashcardsTitle)
+		for i, card := range guide.Flashcards {
+			w.paragraph(card.Question, fmt.Sprintf("%d. ", i+1))
+			w.indented(func() { w.paragraph(card.Answer, "") })
+		}
+	}
+
+	if len(guide.Quiz) > 0 {
+		pdf.AddPage()
+		w.heading(answerKeyTitle)
+		for i, question := range guide.Quiz {
+			w.paragraph(question.Question, fmt.Sprintf("%d. ", i+1))
+			w.indented(func() {
+				for j, option := range question.Options {
+					if j == question.CorrectIndex {
+						w.paragraph(option+" (correct)", optionLabel(j)+". ")
+					} else {
+						w.line(optionLabel(j)+". ", option)
+					}
+				}
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (w *pdfWriter) titlePage(guide Guide) {
+	w.pdf.AddPage()
+	w.pdf.SetY(90)
+
+	w.pdf.SetFont(pdfFont, "B", 26)
+	w.pdf.MultiCell(0, 12, guide.Title, "", "C", false)
+	w.pdf.Ln(6)
+
+	if guide.Course != "" {
+		w.pdf.SetFont(pdfFont, "", 16)
+		w.pdf.MultiCell(0, 8, guide.Course, "", "C", false)
+		w.pdf.Ln(4)
+	}
+
+	w.pdf.SetFont(pdfFont, "", 12)
+	w.pdf.MultiCell(0, 6, "Study guide, "+guide.Date.Format(dateLayout), "", "C", false)
+}
+
+func (w *pdfWriter) heading(title string) {
+	// Keep headings off the bottom of a page
+	if _, pageHeight := w.pdf.GetPageSize(); w.pdf.GetY() > pageHeight-pdfMargin-40 {
+		w.pdf.AddPage()
+	}
+
+	w.pdf.Ln(4)
+	w.pdf.SetFont(pdfFont, "B", 15)
+	w.pdf.Bookmark(title, 0, -1)
+	w.pdf.MultiCell(0, 8, title, "", "L", false)
+	w.pdf.Ln(2)
+}
+
+// paragraph writes text with its math in the math font, after an optional bold lead-in
+func (w *pdfWriter) paragraph(text string, lead string) {
+	if lead != "" {
+		w.pdf.SetFont(pdfFont, "B", pdfFontSize)
+		w.pdf.Write(pdfLineHeight, lead)
+	}
+
+	for _, segment := range latexmath.Split(strings.TrimSpace(text)) {
+		switch {
+		case segment.Display:
+			w.pdf.Ln(pdfLineHeight)
+			w.pdf.SetFont(pdfMathFont, "", pdfFontSize)
+			w.pdf.MultiCell(0, pdfLineHeight, strings.TrimSpace(segment.Text), "", "C", false)
+		case segment.Math:
+			w.pdf.SetFont(pdfMathFont, "", pdfFontSize)
+			w.pdf.Write(pdfLineHeight, segment.Text)
+		default:
+			w.pdf.SetFont(pdfFont, "", pdfFontSize)
+			w.pdf.Write(pdfLineHeight, segment.Text)
+		}
+	}
+
+	w.pdf.Ln(pdfLineHeight * 1.5)
+}
+
+// line writes a short line without spacing after it
+func (w *pdfWriter) line(lead string, text string) {
+	w.pdf.SetFont(pdfFont, "", pdfFontSize)
+	w.pdf.Write(pdfLineHeight, lead)
+	for _, segment := range latexmath.Split(strings.TrimSpace(text)) {
+		if segment.Math {
+			w.pdf.SetFont(pdfMathFont, "", pdfFontSize)
+		} else {
+			w.pdf.SetFont(pdfFont, "", pdfFontSize)
+		}
+		w.pdf.Write(pdfLineHeight, segment.Text)
+	}
+	w.pdf.Ln(pdfLineHeight)
+}
+
+func (w *pdfWriter) sources(sources []string) {
+	if len(sources) == 0 {
+		return
+	}
+	w.pdf.SetFont(pdfFont, "I", 9)
+	w.pdf.SetTextColor(100, 100, 100)
+	w.pdf.MultiCell(0, 5, "Sources: "+strings.Join(sources, "; "), "", "L", false)
+	w.pdf.SetTextColor(0, 0, 0)
+	w.pdf.Ln(3)
+}
+
+func (w *pdfWriter) indented(write func()) {
+	w.pdf.SetLeftMargin(pdfMargin + 8)
+	w.pdf.SetX(pdfMargin + 8)
+	write()
+	w.pdf.SetLeftMargin(pdfMargin)
+	w.pdf.SetX(pdfMargin)
+}
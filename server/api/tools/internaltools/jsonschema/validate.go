@@ -0,0 +1,191 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Errors
+var (
+	ErrInvalidSchema = errors.New("invalid json schema")
+	ErrInvalidJSON   = errors.New("document is not valid json")
+)
+
+// ValidationError lists every place a document breaks its schema
+type ValidationError struct {
+	Violations []string
+}
+
+func (err *ValidationError) Error() string {
+	return "document does not match schema: " + strings.Join(err.Violations, "; ")
+}
+
+// Schema is a compiled subset of JSON Schema: type, properties, required,
+// additionalProperties, items, enum, minItems, maxItems, minLength, minimum and maximum.
+type Schema struct {
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+// Compile parses a schema document
+func Compile(schema string) (*Schema, error) {
+	var compiled Schema
+	if err := json.Unmarshal([]byte(schema), &compiled); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	return &compiled, nil
+}
+
+// Validate checks a JSON document against the schema.
+// It returns ErrInvalidJSON for unparseable input and *ValidationError for schema violations.
+func (schema *Schema) Validate(document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("%w: trailing data after document", ErrInvalidJSON)
+	}
+
+	var violations []string
+	schema.validate("$", value, &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+func (schema *Schema) validate(path string, value any, violations *[]string) {
+	report := func(format string, args ...any) {
+		*violations = append(*violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if schema.Type != "" && !matchesType(schema.Type, value) {
+		report("expected %s, got %s", schema.Type, typeName(value))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		report("value %v is not one of %v", value, schema.Enum)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+
+		// Walk properties in a stable order so violations read the same on every run
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, ok := schema.Properties[name]
+			if !ok {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					report("unexpected property %q", name)
+				}
+				continue
+			}
+			property.validate(path+"."+name, v[name], violations)
+		}
+
+	case []any:
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			report("expected at least %d items, got %d", *schema.MinItems, len(v))
+		}
+		if schema.MaxItems != nil && len(v) > *schema.MaxItems {
+			report("expected at most %d items, got %d", *schema.MaxItems, len(v))
+		}
+		if schema.Items != nil {
+			for i, item := range v {
+				schema.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+
+	case string:
+		if schema.MinLength != nil && len([]rune(v)) < *schema.MinLength {
+			report("expected at least %d characters", *schema.MinLength)
+		}
+
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			report("invalid number %s", v)
+			return
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			report("%s is less than the minimum %v", v, *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			report("%s is greater than the maximum %v", v, *schema.Maximum)
+		}
+	}
+}
+
+func matchesType(expected string, value any) bool {
+	switch expected {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return typeName(value) == expected
+	}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
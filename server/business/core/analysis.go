@@ -2,21 +2,33 @@ package core
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"server/api/tools/features/imageanalysis"
+	"server/api/tools/features/llm"
+	"server/api/tools/features/pdfextract"
+	"server/api/tools/internaltools/jsonschema"
 	"server/sqlc/sqlgen"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/openai/openai-go/v3"
-	"github.com/openai/openai-go/v3/responses"
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/sync/errgroup"
 )
 
 func (core Core) AnalyzeCollection(
 	ctx context.Context,
 	userID uuid.UUID,
 	collectionID uuid.UUID,
-	kind sqlgen.AnalysisType,
+	request AnalysisRequest,
 ) (*CollectionAnalysis, error) {
 
 	q := core.Queries
@@ -29,38 +41,48 @@ func (core Core) AnalyzeCollection(
 		return nil, err
 	}
 
-	// Ensure text exists
-	if err := core.ensureExtractions(ctx, userID, collectionID); err != nil {
+	if _, err := core.AnalysisKinds.Get(request.Kind); err != nil {
 		return nil, err
 	}
 
-	// Snapshot
-	snapshot, err := core.createSnapshot(ctx, collectionID)
+	parameters, err := encodeAnalysisParameters(request.Parameters)
 	if err != nil {
 		return nil, err
 	}
 
-	// Run AI
-	result, err := core.runAnalysis(ctx, snapshot.CombinedContent, kind)
+	if err := core.checkQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	provider, err := core.Services.LLM.Get(request.Provider)
 	if err != nil {
 		return nil, err
 	}
 
-	row, err := q.CreateCollectionAnalysis(ctx, sqlgen.CreateCollectionAnalysisParams{
-		SnapshotID: snapshot.ID,
-		Type:       sqlgen.AnalysisType(kind),
-		Result:     result,
+	// Run through the job pipeline inline so synchronous analyses are tracked like queued ones
+	job, err := q.CreateAnalysisJob(ctx, sqlgen.CreateAnalysisJobParams{
+		UserID:       userID,
+		CollectionID: collectionID,
+		Type:         request.Kind,
+		Provider:     provider.Name(),
+		Parameters:   parameters,
+		Regenerate:   request.Regenerate,
+		Status:       sqlgen.AnalysisJobStatusExtracting,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &CollectionAnalysis{
-		ID:        row.ID,
-		Type:      kind,
-		Result:    row.Result,
-		CreatedAt: row.CreatedAt,
-	}, nil
+	// Bounded like a worker's run, so the stale job sweep never requeues a run that is still going
+	jobCtx, cancel := context.WithTimeout(ctx, core.AnalysisJobTimeout)
+	defer cancel()
+
+	return core.runAnalysisJob(jobCtx, job)
+}
+
+// ListAnalysisKinds returns the analysis kinds users can request
+func (core Core) ListAnalysisKinds() []AnalysisKind {
+	return core.AnalysisKinds.List()
 }
 
 func (core Core) GetCollectionAnalyses(
@@ -85,12 +107,11 @@ func (core Core) GetCollectionAnalyses(
 
 	results := make([]CollectionAnalysis, 0, len(rows))
 	for _, r := range rows {
-		results = append(results, CollectionAnalysis{
-			ID:        r.ID,
-			Type:      r.Type,
-			Result:    r.Result,
-			CreatedAt: r.CreatedAt,
-		})
+		analysis, err := collectionAnalysisFromRow(r)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *analysis)
 	}
 
 	return results, nil
@@ -98,15 +119,184 @@ func (core Core) GetCollectionAnalyses(
 
 // INTERNAL
 
+// analyzeCollection extracts, snapshots and analyzes the job's collection, advancing the job status as it goes.
+// An earlier analysis of identical content and parameters is returned instead unless the job asks to regenerate.
+func (core Core) analyzeCollection(
+	ctx context.Context,
+	job sqlgen.AnalysisJob,
+) (*CollectionAnalysis, error) {
+
+	q := core.Queries
+
+	kind, err := core.AnalysisKinds.Get(job.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := core.Services.LLM.Get(job.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := decodeAnalysisParameters(job.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := q.GetCollection(ctx, sqlgen.GetCollectionParams{
+		UserID: job.UserID,
+		ID:     job.CollectionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := core.activePromptTemplate(ctx, job.UserID, collection.Course, job.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := analysisPrompt{
+		Kind:       kind,
+		Parameters: params,
+		Template:   template,
+		Course:     collection.Course,
+		Collection: collection.Title,
+	}
+
+	var templateID uuid.NullUUID
+	if template != nil {
+		templateID = uuid.NullUUID{UUID: template.ID, Valid: true}
+	}
+
+	// Queued jobs are checked again when they run, since other work may have used the quota up meanwhile
+	if err := core.checkQuota(ctx, job.UserID); err != nil {
+		return nil, err
+	}
+
+	emitAnalysisEvent(ctx, AnalysisEventStatus, StatusEvent{Status: sqlgen.AnalysisJobStatusExtracting})
+
+	// Ensure text exists
+	if err := core.ensureExtractions(ctx, provider, job.UserID, job.CollectionID); err != nil {
+		return nil, err
+	}
+
+	content, sources, err := core.collectionContent(ctx, job.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	contentHash := analysisContentHash(content, kind, provider, string(job.Parameters), templateHashPart(templateID))
+
+	// Identical content and parameters would only repeat an earlier analysis
+	if !job.Regenerate {
+		existing, err := q.GetAnalysisByContentHash(ctx, sqlgen.GetAnalysisByContentHashParams{
+			CollectionID: job.CollectionID,
+			ContentHash:  contentHash,
+			Type:         job.Type,
+		})
+		if err == nil {
+			analysis, err := collectionAnalysisFromRow(existing)
+			if err != nil {
+				return nil, err
+			}
+			analysis.Reused = true
+			return analysis, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	if err := q.SetAnalysisJobStatus(ctx, sqlgen.SetAnalysisJobStatusParams{
+		ID:     job.ID,
+		Status: sqlgen.AnalysisJobStatusAnalyzing,
+	}); err != nil {
+		return nil, err
+	}
+	emitAnalysisEvent(ctx, AnalysisEventStatus, StatusEvent{Status: sqlgen.AnalysisJobStatusAnalyzing})
+
+	// Snapshot
+	snapshot, err := core.createSnapshot(ctx, job.CollectionID, content, contentHash, sources)
+	if err != nil {
+		return nil, err
+	}
+	emitAnalysisEvent(ctx, AnalysisEventSnapshotCreated, SnapshotCreatedEvent{SnapshotID: snapshot.ID})
+
+	// Run AI, totalling every request it takes for the analysis' usage
+	recorder := llm.NewUsageRecorder(provider)
+	defer core.recordUsage(ctx, job.UserID, sqlgen.UsageKindAnalysis, recorder)
+
+	result, err := core.analyzeContent(ctx, recorder, snapshot.CombinedContent, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind.PostProcess != nil {
+		if result, err = kind.PostProcess(result); err != nil {
+			return nil, err
+		}
+	}
+
+	// Merged chunks can overshoot the requested count
+	if result, err = limitItems(result, params.Count); err != nil {
+		return nil, err
+	}
+
+	// Turn [S#] citations into the documents and pages a student can check
+	result, err = resolveSources(result, snapshot.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	// The analysis and its items are stored together
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := q.WithTx(tx)
+
+	usage := recorder.Usage()
+	row, err := qtx.CreateCollectionAnalysis(ctx, sqlgen.CreateCollectionAnalysisParams{
+		SnapshotID:   snapshot.ID,
+		Type:         job.Type,
+		Parameters:   job.Parameters,
+		TemplateID:   templateID,
+		Result:       result,
+		Model:        usage.Model,
+		PromptHash:   usage.PromptHash,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		LatencyMs:    usage.Latency.Milliseconds(),
+		Requests:     int32(usage.Requests),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createAnalysisItems(ctx, qtx, row.ID, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return collectionAnalysisFromRow(row)
+}
+
+// ensureExtractions extracts every document that doesn't have text yet, ExtractionWorkers at a time.
+// Each document is stored as soon as it finishes and one failure doesn't stop the others,
+// so a retry only redoes the documents that failed.
 func (core Core) ensureExtractions(
 	ctx context.Context,
+	provider llm.Provider,
 	userID uuid.UUID,
 	collectionID uuid.UUID,
 ) error {
 
-	q := core.Queries
-
-	docs, err := q.GetCollectionDocuments(ctx, sqlgen.GetCollectionDocumentsParams{
+	docs, err := core.Queries.GetCollectionDocuments(ctx, sqlgen.GetCollectionDocumentsParams{
 		UserID:       userID,
 		CollectionID: collectionID,
 	})
@@ -114,35 +304,84 @@ func (core Core) ensureExtractions(
 		return err
 	}
 
-	for _, doc := range docs {
-		exists, err := q.HasDocumentExtraction(ctx, doc.ID)
-		if err != nil {
-			return err
-		}
-		if exists {
-			continue
-		}
+	var group errgroup.Group
+	group.SetLimit(core.ExtractionWorkers)
+
+	var mu sync.Mutex
+	var failures []error
+	var completed atomic.Int32
+
+	for i, doc := range docs {
+		group.Go(func() error {
+			cached, err := core.ensureDocumentExtraction(ctx, provider, userID, doc)
+			if err != nil {
+				mu.Lock()
+				failures = append(failures, fmt.Errorf("document %s: %w", doc.ID, err))
+				mu.Unlock()
+				return nil
+			}
+
+			emitAnalysisEvent(ctx, AnalysisEventDocumentExtracted, DocumentExtractedEvent{
+				DocumentID: doc.ID,
+				Index:      i + 1,
+				Total:      len(docs),
+				Completed:  int(completed.Add(1)),
+				Cached:     cached,
+			})
+			return nil
+		})
+	}
+	_ = group.Wait()
 
-		extraction, err := core.extractDocumentContent(ctx, doc)
-		if err != nil {
-			return err
-		}
+	return errors.Join(failures...)
+}
 
-		if _, err := q.CreateDocumentExtraction(ctx, sqlgen.CreateDocumentExtractionParams{
-			DocumentID: doc.ID,
-			Content:    extraction.Content,
-		}); err != nil {
-			return err
-		}
+// ensureDocumentExtraction extracts, stores and embeds one document unless it was extracted before
+func (core Core) ensureDocumentExtraction(
+	ctx context.Context,
+	provider llm.Provider,
+	userID uuid.UUID,
+	doc sqlgen.Document,
+) (cached bool, err error) {
+
+	exists, err := core.Queries.HasDocumentExtraction(ctx, doc.ID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return true, nil
 	}
 
-	return nil
+	recorder := llm.NewUsageRecorder(provider)
+	defer core.recordUsage(ctx, userID, sqlgen.UsageKindExtraction, recorder)
+
+	pages, err := core.extractDocumentContent(ctx, recorder, doc)
+	if err != nil {
+		return false, err
+	}
+
+	stored, err := core.storeExtraction(ctx, doc.ID, pages)
+	if err != nil {
+		return false, err
+	}
+	if !stored {
+		// Another job extracted the document meanwhile, and embeds what it stored
+		return true, nil
+	}
+
+	core.embedDocument(ctx, userID, doc.ID)
+	return false, nil
 }
 
 func (core Core) extractDocumentContent(
 	ctx context.Context,
+	provider llm.Provider,
 	doc sqlgen.Document,
-) (*DocumentTextExtraction, error) {
+) ([]ExtractedPage, error) {
+
+	if doc.MimeType == pdfextract.MimeType {
+		return core.extractPDFContent(ctx, provider, doc)
+	}
 
 	url, err := core.Services.Minio.PresignedGetObject(
 		ctx,
@@ -155,37 +394,173 @@ func (core Core) extractDocumentContent(
 		return nil, err
 	}
 
+	recorder := llm.NewUsageRecorder(provider)
 	img, err := imageanalysis.NewImageAnalyzer[DocumentTextExtraction](imageanalysis.NewImageAnalyzerParams{
-		AI: core.Services.OpenAI,
+		AI: recorder,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return img.ExtractText(ctx, url)
+	extraction, err := img.ExtractText(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return []ExtractedPage{{
+		Page:    1,
+		Method:  sqlgen.ExtractionMethodVision,
+		Content: extraction.Content,
+		Usage:   recorder.Usage(),
+	}}, nil
 }
 
-func (core Core) createSnapshot(
+// extractPDFContent reads each page's embedded text, only falling back to vision OCR for pages without any
+func (core Core) extractPDFContent(
 	ctx context.Context,
-	collectionID uuid.UUID,
-) (*CollectionSnapshot, error) {
+	provider llm.Provider,
+	doc sqlgen.Document,
+) ([]ExtractedPage, error) {
 
-	q := core.Queries
+	obj, err := core.Services.Minio.GetObject(ctx, core.UploadBucket, doc.S3Location, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
 
-	extractions, err := q.GetDocumentExtractionsByCollection(ctx, collectionID)
+	document, err := io.ReadAll(obj)
 	if err != nil {
 		return nil, err
 	}
 
+	textPages, err := pdfextract.TextPages(document)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]ExtractedPage, 0, len(textPages))
+	for _, textPage := range textPages {
+		if textPage.HasText() {
+			pages = append(pages, ExtractedPage{
+				Page:    textPage.Number,
+				Method:  sqlgen.ExtractionMethodTextLayer,
+				Content: textPage.Text,
+			})
+			continue
+		}
+
+		// Scanned page
+		if core.PDFRasterizer == nil {
+			return nil, fmt.Errorf("page %d has no text layer: %w", textPage.Number, pdfextract.ErrNoRasterizer)
+		}
+
+		image, err := core.PDFRasterizer.RasterizePage(ctx, document, textPage.Number)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each page is its own request, so each records its own usage
+		recorder := llm.NewUsageRecorder(provider)
+		img, err := imageanalysis.NewImageAnalyzer[DocumentTextExtraction](imageanalysis.NewImageAnalyzerParams{
+			AI: recorder,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		extraction, err := img.ExtractTextFromImage(ctx, image.Data, image.MimeType)
+		if err != nil {
+			return nil, fmt.Errorf("extracting page %d: %w", textPage.Number, err)
+		}
+
+		pages = append(pages, ExtractedPage{
+			Page:    textPage.Number,
+			Method:  sqlgen.ExtractionMethodVision,
+			Content: extraction.Content,
+			Usage:   recorder.Usage(),
+		})
+	}
+
+	return pages, nil
+}
+
+// storeExtraction saves every page of a document together, so a failure never leaves a partial extraction behind.
+// It reports false when another job stored the document's pages first, leaving theirs in place.
+func (core Core) storeExtraction(ctx context.Context, documentID uuid.UUID, pages []ExtractedPage) (bool, error) {
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+	stored := false
+	for _, page := range pages {
+		created, err := q.CreateDocumentExtraction(ctx, sqlgen.CreateDocumentExtractionParams{
+			DocumentID:   documentID,
+			Page:         int32(page.Page),
+			Method:       page.Method,
+			Content:      page.Content,
+			Model:        page.Usage.Model,
+			PromptHash:   page.Usage.PromptHash,
+			InputTokens:  page.Usage.InputTokens,
+			OutputTokens: page.Usage.OutputTokens,
+			LatencyMs:    page.Usage.Latency.Milliseconds(),
+		})
+		if err != nil {
+			return false, err
+		}
+		stored = stored || created > 0
+	}
+
+	return stored, tx.Commit()
+}
+
+// collectionContent joins the extracted pages of every document in the collection.
+// Each page is prefixed with a [S#] label the model cites, and anchors map the labels back to their pages.
+func (core Core) collectionContent(ctx context.Context, collectionID uuid.UUID) (string, []SourceAnchor, error) {
+	extractions, err := core.Queries.GetDocumentExtractionsByCollection(ctx, collectionID)
+	if err != nil {
+		return "", nil, err
+	}
+
 	var combined strings.Builder
-	for _, e := range extractions {
-		combined.WriteString(e)
+	anchors := make([]SourceAnchor, 0, len(extractions))
+	for i, e := range extractions {
+		anchor := SourceAnchor{
+			Label:      sourceLabel(i),
+			DocumentID: e.DocumentID,
+			Title:      e.Title,
+			Page:       int(e.Page),
+		}
+		anchors = append(anchors, anchor)
+
+		combined.WriteString("[" + anchor.Label + "]\n")
+		combined.WriteString(e.Content)
 		combined.WriteString("\n\n")
 	}
 
-	row, err := q.CreateCollectionSnapshot(ctx, sqlgen.CreateCollectionSnapshotParams{
+	return combined.String(), anchors, nil
+}
+
+func (core Core) createSnapshot(
+	ctx context.Context,
+	collectionID uuid.UUID,
+	content string,
+	contentHash string,
+	sources []SourceAnchor,
+) (*CollectionSnapshot, error) {
+
+	encodedSources, err := json.Marshal(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := core.Queries.CreateCollectionSnapshot(ctx, sqlgen.CreateCollectionSnapshotParams{
 		CollectionID:    collectionID,
-		CombinedContent: combined.String(),
+		CombinedContent: content,
+		ContentHash:     contentHash,
+		Sources:         encodedSources,
 	})
 	if err != nil {
 		return nil, err
@@ -193,29 +568,161 @@ func (core Core) createSnapshot(
 
 	return &CollectionSnapshot{
 		ID:              row.ID,
-		CombinedContent: combined.String(),
+		CombinedContent: content,
+		ContentHash:     contentHash,
+		Sources:         sources,
+	}, nil
+}
+
+// analysisContentHash identifies an analysis by its input content and everything that shapes the output:
+// the kind's schema and task, the provider and its model, the parameters and the prompt template
+func analysisContentHash(
+	content string,
+	kind AnalysisKind,
+	provider llm.Provider,
+	parameters string,
+	template string,
+) string {
+
+	hash := sha256.New()
+	for _, part := range []string{
+		content,
+		kind.Name, kind.Schema, kind.Task,
+		provider.Name(), provider.Model(),
+		parameters,
+		template,
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0}) // Separator so adjacent parts can't run together
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// collectionAnalysisFromRow maps a stored analysis onto the model
+func collectionAnalysisFromRow(row sqlgen.CollectionAnalysis) (*CollectionAnalysis, error) {
+	params, err := decodeAnalysisParameters(row.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CollectionAnalysis{
+		ID:         row.ID,
+		Type:       row.Type,
+		Result:     row.Result,
+		CreatedAt:  row.CreatedAt,
+		Parameters: params,
+		TemplateID: nullableUUID(row.TemplateID),
+		Imported:   row.Imported,
+		Usage: llm.Usage{
+			Model:        row.Model,
+			PromptHash:   row.PromptHash,
+			InputTokens:  row.InputTokens,
+			OutputTokens: row.OutputTokens,
+			Latency:      time.Duration(row.LatencyMs) * time.Millisecond,
+			Requests:     int(row.Requests),
+		},
 	}, nil
 }
 
+// templateHashPart identifies the prompt template in a content hash, empty for the default instructions
+func templateHashPart(templateID uuid.NullUUID) string {
+	if !templateID.Valid {
+		return ""
+	}
+	return templateID.UUID.String()
+}
+
+func nullableUUID(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
 func (core Core) runAnalysis(
 	ctx context.Context,
+	provider llm.Provider,
 	content string,
-	kind sqlgen.AnalysisType,
+	prompt analysisPrompt,
 ) (json.RawMessage, error) {
 
-	schema := SchemaForAnalysis(kind)
-	instructions := analysisInstructions(kind, schema)
-
-	resp, err := core.Services.OpenAI.Responses.New(ctx, responses.ResponseNewParams{
-		Model:        ChatModel,
-		Instructions: openai.String(instructions),
-		Input: responses.ResponseNewParamsInputUnion{
-			OfString: openai.String(content),
-		},
-	})
+	kind := prompt.Kind
+	instructions, err := analysisInstructions(prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	return json.RawMessage(resp.OutputText()), nil
+	input := content
+	var violations []string
+
+	// The first attempt plus a bounded number of repairs, each fed the previous violations
+	attempts := core.AnalysisRepairAttempts + 1
+	for attempt := 0; attempt < attempts; attempt++ {
+		request := llm.CompletionRequest{
+			Instructions: instructions,
+			Input:        input,
+			Schema:       kind.Schema,
+		}
+
+		// Only stream when someone is listening, and not the raw output of kinds it would give the answers away for
+		if analysisObserverFrom(ctx) != nil && !kind.HidesAnswers {
+			request.OnDelta = func(delta string) {
+				emitAnalysisEvent(ctx, AnalysisEventToken, TokenEvent{
+					Attempt: attempt + 1,
+					Delta:   delta,
+				})
+			}
+		}
+
+		resp, err := provider.Complete(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		result := trimCodeFence(resp.Text)
+
+		violations = validateAnalysis(kind, json.RawMessage(result))
+		if len(violations) == 0 {
+			return json.RawMessage(result), nil
+		}
+
+		input = analysisRepairInput(content, resp.Text, violations)
+	}
+
+	return nil, &AnalysisValidationError{
+		Kind:       kind.Name,
+		Attempts:   attempts,
+		Violations: violations,
+	}
+}
+
+// validateAnalysis checks a result against the kind's schema, then its own rules
+func validateAnalysis(kind AnalysisKind, result json.RawMessage) []string {
+	err := kind.validator.Validate(result)
+
+	var validationErr *jsonschema.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.Violations
+	case err != nil:
+		return []string{err.Error()}
+	}
+
+	if kind.Validate != nil {
+		return kind.Validate(result)
+	}
+	return nil
+}
+
+// trimCodeFence strips the markdown code fence models sometimes wrap JSON in despite instructions
+func trimCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+	return strings.TrimSpace(text)
 }
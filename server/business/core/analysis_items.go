@@ -0,0 +1,420 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server/sqlc/sqlgen"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrNotItemized          error = errors.New("analysis results of this kind aren't a list of items")
+	ErrAnalysisItemNotFound error = errors.New("analysis item not found")
+	ErrInvalidAnalysisItem  error = errors.New("invalid analysis item")
+	ErrInvalidItemOrder     error = errors.New("invalid analysis item order")
+)
+
+// jsonNull marks generated or edited content an item doesn't have
+var jsonNull = json.RawMessage(`null`)
+
+// AnalysisItem is one item of a list-shaped analysis, such as a flashcard or quiz question.
+// Edits are stored beside the generated content rather than over it.
+type AnalysisItem struct {
+	ID         uuid.UUID
+	AnalysisID uuid.UUID
+	Position   int
+
+	// Content is the item as it stands: the user's edit when there is one, otherwise the generated item
+	Content json.RawMessage
+
+	// Generated is the item as generated or imported, or nil for items the user added
+	Generated json.RawMessage
+
+	// Edited is set when Content is the user's rather than the model's
+	Edited bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GetAnalysisItems lists an analysis' items in order, leaving out deleted ones
+func (core Core) GetAnalysisItems(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) ([]AnalysisItem, error) {
+	if _, err := core.userItemizedAnalysis(ctx, userID, analysisID); err != nil {
+		return nil, err
+	}
+
+	return core.analysisItems(ctx, analysisID)
+}
+
+// AddAnalysisItem appends an item the user wrote to an analysis
+func (core Core) AddAnalysisItem(
+	ctx context.Context,
+	userID uuid.UUID,
+	analysisID uuid.UUID,
+	content json.RawMessage,
+) (*AnalysisItem, error) {
+
+	kind, err := core.userItemizedAnalysis(ctx, userID, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err = normalizeAnalysisItem(kind, content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var row sqlgen.AnalysisItem
+	err = core.updateAnalysisItems(ctx, analysisID, func(q *sqlgen.Queries) error {
+		position, err := q.NextAnalysisItemPosition(ctx, analysisID)
+		if err != nil {
+			return err
+		}
+
+		row, err = q.CreateAnalysisItem(ctx, sqlgen.CreateAnalysisItemParams{
+			AnalysisID: analysisID,
+			Generated:  jsonNull,
+			Edited:     content,
+			Position:   position,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return analysisItemFromRow(row), nil
+}
+
+// EditAnalysisItem replaces an item's content. The generated original is kept, so the edit can be reverted.
+func (core Core) EditAnalysisItem(
+	ctx context.Context,
+	userID uuid.UUID,
+	itemID uuid.UUID,
+	content json.RawMessage,
+) (*AnalysisItem, error) {
+
+	item, err := core.userAnalysisItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, err := core.AnalysisKinds.Get(item.AnalysisType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edits that leave sources out keep the ones the item already cites
+	content, err = normalizeAnalysisItem(kind, content, itemSources(analysisItemContent(item.Generated, item.Edited)))
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := core.setAnalysisItemEdit(ctx, item.AnalysisID, itemID, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return analysisItemFromRow(row), nil
+}
+
+// RevertAnalysisItem discards the user's edit of a generated item
+func (core Core) RevertAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) (*AnalysisItem, error) {
+	item, err := core.userAnalysisItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if isJSONNull(item.Generated) {
+		return nil, fmt.Errorf("%w: items added by hand have nothing to revert to", ErrInvalidAnalysisItem)
+	}
+
+	row, err := core.setAnalysisItemEdit(ctx, item.AnalysisID, itemID, jsonNull)
+	if err != nil {
+		return nil, err
+	}
+
+	return analysisItemFromRow(row), nil
+}
+
+// DeleteAnalysisItem removes an item from its analysis
+func (core Core) DeleteAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error {
+	item, err := core.userAnalysisItem(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+
+	return core.updateAnalysisItems(ctx, item.AnalysisID, func(q *sqlgen.Queries) error {
+		return q.DeleteAnalysisItem(ctx, itemID)
+	})
+}
+
+// ReorderAnalysisItems puts an analysis' items in the given order, which must list every item exactly once
+func (core Core) ReorderAnalysisItems(
+	ctx context.Context,
+	userID uuid.UUID,
+	analysisID uuid.UUID,
+	order []uuid.UUID,
+) ([]AnalysisItem, error) {
+
+	if _, err := core.userItemizedAnalysis(ctx, userID, analysisID); err != nil {
+		return nil, err
+	}
+
+	items, err := core.analysisItems(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(order) != len(items) {
+		return nil, fmt.Errorf("%w: expected %d item ids, got %d", ErrInvalidItemOrder, len(items), len(order))
+	}
+
+	live := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		live[item.ID] = true
+	}
+	for _, id := range order {
+		if !live[id] {
+			return nil, fmt.Errorf("%w: %s is missing, repeated or not an item of this analysis", ErrInvalidItemOrder, id)
+		}
+		delete(live, id)
+	}
+
+	err = core.updateAnalysisItems(ctx, analysisID, func(q *sqlgen.Queries) error {
+		for position, id := range order {
+			if err := q.SetAnalysisItemPosition(ctx, sqlgen.SetAnalysisItemPositionParams{
+				ID:       id,
+				Position: int32(position),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return core.analysisItems(ctx, analysisID)
+}
+
+// INTERNAL
+
+// analysisItems lists an analysis' live items in order
+func (core Core) analysisItems(ctx context.Context, analysisID uuid.UUID) ([]AnalysisItem, error) {
+	rows, err := core.Queries.GetAnalysisItems(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]AnalysisItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, *analysisItemFromRow(row))
+	}
+
+	return items, nil
+}
+
+// updateAnalysisItems changes an analysis' items and rebuilds its result from them in one transaction,
+// so the result always lists the items as they stand
+func (core Core) updateAnalysisItems(ctx context.Context, analysisID uuid.UUID, update func(q *sqlgen.Queries) error) error {
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+	if err := update(q); err != nil {
+		return err
+	}
+	if err := q.RefreshAnalysisResult(ctx, analysisID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setAnalysisItemEdit stores an item's edit, or reverts it with a JSON null edit
+func (core Core) setAnalysisItemEdit(
+	ctx context.Context,
+	analysisID uuid.UUID,
+	itemID uuid.UUID,
+	edited json.RawMessage,
+) (sqlgen.AnalysisItem, error) {
+
+	var row sqlgen.AnalysisItem
+	err := core.updateAnalysisItems(ctx, analysisID, func(q *sqlgen.Queries) error {
+		var err error
+		row, err = q.SetAnalysisItemEdit(ctx, sqlgen.SetAnalysisItemEditParams{
+			ID:     itemID,
+			Edited: edited,
+		})
+		return err
+	})
+	return row, err
+}
+
+// userItemizedAnalysis checks the analysis is the user's and of a list-shaped kind, returning the kind
+func (core Core) userItemizedAnalysis(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) (AnalysisKind, error) {
+	analysis, err := core.Queries.GetUserAnalysis(ctx, sqlgen.GetUserAnalysisParams{
+		ID:     analysisID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return AnalysisKind{}, ErrAnalysisNotFound
+	}
+	if err != nil {
+		return AnalysisKind{}, err
+	}
+
+	kind, err := core.AnalysisKinds.Get(analysis.Type)
+	if err != nil {
+		return AnalysisKind{}, err
+	}
+	if !kind.itemized() {
+		return AnalysisKind{}, ErrNotItemized
+	}
+
+	return kind, nil
+}
+
+// userAnalysisItem finds a live item of one of the user's analyses
+func (core Core) userAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) (*sqlgen.GetUserAnalysisItemRow, error) {
+	item, err := core.Queries.GetUserAnalysisItem(ctx, sqlgen.GetUserAnalysisItemParams{
+		ID:     itemID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnalysisItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// createAnalysisItems breaks a newly stored result out into items. Results that aren't lists have none.
+func createAnalysisItems(ctx context.Context, q *sqlgen.Queries, analysisID uuid.UUID, result json.RawMessage) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(result, &items); err != nil {
+		return nil // Not a list
+	}
+
+	for i, item := range items {
+		if _, err := q.CreateAnalysisItem(ctx, sqlgen.CreateAnalysisItemParams{
+			AnalysisID:     analysisID,
+			GeneratedIndex: sql.NullInt32{Int32: int32(i), Valid: true},
+			Generated:      item,
+			Edited:         jsonNull,
+			Position:       int32(i),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// itemized reports whether the kind's results are a list of items that can be edited one by one
+func (kind AnalysisKind) itemized() bool {
+	return kind.validator != nil && kind.validator.Type == "array" && kind.validator.Items != nil
+}
+
+// normalizeAnalysisItem checks an item a user wrote against the kind's rules. Stored sources are resolved
+// Source objects rather than the [S#] labels the schema describes, so they are checked on their own.
+// An item without sources gets fallbackSources.
+func normalizeAnalysisItem(kind AnalysisKind, content json.RawMessage, fallbackSources json.RawMessage) (json.RawMessage, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidAnalysisItem, fmt.Sprintf(format, args...))
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil || fields == nil {
+		return nil, invalid("an item must be a JSON object")
+	}
+
+	_, citesSources := kind.validator.Items.Properties["sources"]
+	if citesSources {
+		sources, ok := fields["sources"]
+		if !ok {
+			sources = fallbackSources
+		}
+		if len(sources) == 0 {
+			sources = json.RawMessage(`[]`)
+		}
+
+		var resolved []Source
+		if err := json.Unmarshal(sources, &resolved); err != nil {
+			return nil, invalid("sources must be a list of {documentId, title, page}")
+		}
+		fields["sources"] = sources
+	}
+
+	// Validated as a one-item result so the kind's own rules apply too
+	check := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		check[key] = value
+	}
+	if citesSources {
+		check["sources"] = json.RawMessage(`[]`)
+	}
+
+	result, err := json.Marshal([]map[string]json.RawMessage{check})
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateAnalysis(kind, result); len(violations) > 0 {
+		return nil, invalid("%s", strings.Join(violations, "; "))
+	}
+
+	return json.Marshal(fields)
+}
+
+// analysisItemContent is an item as it stands: the edit when there is one, otherwise the generated item
+func analysisItemContent(generated json.RawMessage, edited json.RawMessage) json.RawMessage {
+	if !isJSONNull(edited) {
+		return edited
+	}
+	return generated
+}
+
+// itemSources returns an item's "sources" field, or nil when it has none
+func itemSources(content json.RawMessage) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil
+	}
+	return fields["sources"]
+}
+
+func isJSONNull(value json.RawMessage) bool {
+	return len(value) == 0 || bytes.Equal(bytes.TrimSpace(value), jsonNull)
+}
+
+func analysisItemFromRow(row sqlgen.AnalysisItem) *AnalysisItem {
+	item := &AnalysisItem{
+		ID:         row.ID,
+		AnalysisID: row.AnalysisID,
+		Position:   int(row.Position),
+		Content:    analysisItemContent(row.Generated, row.Edited),
+		Edited:     !isJSONNull(row.Edited),
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+
+	if !isJSONNull(row.Generated) {
+		item.Generated = row.Generated
+	}
+
+	return item
+}
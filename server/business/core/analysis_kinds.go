@@ -0,0 +1,141 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server/api/tools/internaltools/jsonschema"
+	"sync"
+)
+
+// AnalysisKind bundles everything needed to produce one type of analysis
+type AnalysisKind struct {
+	Name        string
+	Description string // Shown to users choosing a kind
+
+	// Schema is the JSON schema results must match. The model is given it verbatim.
+	// "sources" fields hold the [S#] labels the model cites; stored results replace them with resolved Sources.
+	Schema string
+
+	// Task tells the model what to produce
+	Task string
+
+	// Validate checks rules the schema can't express. Its violations are sent back to the model
+	// for repair like schema violations. Optional.
+	Validate func(result json.RawMessage) []string
+
+	// PostProcess tidies a validated result before it is stored. Optional.
+	PostProcess func(result json.RawMessage) (json.RawMessage, error)
+
+	// Merge combines the partial results of a map-reduce analysis.
+	// Kinds that can't be merged mechanically leave it nil and set CondenseField instead.
+	Merge func(partials []json.RawMessage) (json.RawMessage, error)
+
+	// CondenseField is the text field the model condenses across partial results when Merge is nil
+	CondenseField string
+
+	// HidesAnswers marks kinds whose output holds the answers to questions the user takes,
+	// so it isn't streamed to them as it is generated
+	HidesAnswers bool
+
+	validator *jsonschema.Schema
+}
+
+// AnalysisKindRegistry holds the analysis kinds users can request
+type AnalysisKindRegistry struct {
+	mu    sync.RWMutex
+	kinds map[string]AnalysisKind
+	order []string
+}
+
+type NewAnalysisKindRegistryParams struct {
+	Kinds []AnalysisKind
+}
+
+func NewAnalysisKindRegistry(data NewAnalysisKindRegistryParams) (*AnalysisKindRegistry, error) {
+	registry := &AnalysisKindRegistry{
+		kinds: make(map[string]AnalysisKind, len(data.Kinds)),
+	}
+
+	for _, kind := range data.Kinds {
+		if err := registry.Register(kind); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// Register adds a kind after checking that its schema compiles
+func (registry *AnalysisKindRegistry) Register(kind AnalysisKind) error {
+	if kind.Name == "" || kind.Task == "" {
+		return fmt.Errorf("%w: name and task are required", ErrInvalidAnalysisKind)
+	}
+	if kind.Merge == nil && kind.CondenseField == "" {
+		return fmt.Errorf("%w: %q needs Merge or CondenseField", ErrInvalidAnalysisKind, kind.Name)
+	}
+
+	validator, err := jsonschema.Compile(kind.Schema)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidAnalysisKind, kind.Name, err)
+	}
+	kind.validator = validator
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.kinds[kind.Name]; ok {
+		return fmt.Errorf("%w: %q is already registered", ErrInvalidAnalysisKind, kind.Name)
+	}
+
+	registry.kinds[kind.Name] = kind
+	registry.order = append(registry.order, kind.Name)
+
+	return nil
+}
+
+func (registry *AnalysisKindRegistry) Get(name string) (AnalysisKind, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	kind, ok := registry.kinds[name]
+	if !ok {
+		return AnalysisKind{}, fmt.Errorf("%w: %q", ErrUnknownAnalysisKind, name)
+	}
+
+	return kind, nil
+}
+
+// List returns every kind in registration order
+func (registry *AnalysisKindRegistry) List() []AnalysisKind {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	kinds := make([]AnalysisKind, 0, len(registry.order))
+	for _, name := range registry.order {
+		kinds = append(kinds, registry.kinds[name])
+	}
+
+	return kinds
+}
+
+var ( // Errors
+	ErrUnknownAnalysisKind error = errors.New("unknown analysis kind")
+	ErrInvalidAnalysisKind error = errors.New("invalid analysis kind")
+)
+
+// INTERNAL
+
+// mergeItemsBy merges array results, dropping items whose keyField repeats an earlier one
+func mergeItemsBy(keyField string) func(partials []json.RawMessage) (json.RawMessage, error) {
+	return func(partials []json.RawMessage) (json.RawMessage, error) {
+		return mergeItems(partials, keyField)
+	}
+}
+
+// dedupeItemsBy drops items of an array result whose keyField repeats an earlier one
+func dedupeItemsBy(keyField string) func(result json.RawMessage) (json.RawMessage, error) {
+	return func(result json.RawMessage) (json.RawMessage, error) {
+		return mergeItems([]json.RawMessage{result}, keyField)
+	}
+}
@@ -0,0 +1,286 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BuiltinAnalysisKinds returns the kinds every deployment offers
+func BuiltinAnalysisKinds() []AnalysisKind {
+	return []AnalysisKind{
+		{
+			Name:        AnalysisSummary,
+			Description: "A concise summary of the main ideas, definitions and themes",
+			Schema: `{
+  "type": "object",
+  "properties": {
+    "summary": { "type": "string", "minLength": 1 },
+    "sources": { "type": "array", "items": { "type": "string" } }
+  },
+  "required": ["summary", "sources"]
+}`,
+			Task: `
+Generate a concise summary of the material.
+Focus on the main ideas, definitions, and themes.
+Assume the reader is a student reviewing before class or an exam.
+Keep it accurate, and brief unless asked otherwise.
+`,
+			CondenseField: "summary",
+		},
+		{
+			Name:        AnalysisFlashcards,
+			Description: "Question and answer cards that each test a single concept",
+			Schema: `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "question": { "type": "string", "minLength": 1 },
+      "answer": { "type": "string", "minLength": 1 },
+      "sources": { "type": "array", "items": { "type": "string" } }
+    },
+    "required": ["question", "answer", "sources"]
+  }
+}`,
+			Task: `
+Generate a set of study flashcards.
+Each flashcard should test a single concept, definition, or fact.
+Questions should be clear and unambiguous.
+Answers should be short, correct, and directly supported by the material.
+`,
+			PostProcess: dedupeItemsBy("question"),
+			Merge:       mergeItemsBy("question"),
+		},
+		{
+			Name:        AnalysisQuiz,
+			Description: "Multiple choice questions across a range of difficulties",
+			Schema: `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "question": { "type": "string", "minLength": 1 },
+      "options": { "type": "array", "items": { "type": "string" }, "minItems": 2 },
+      "correct_index": { "type": "integer", "minimum": 0 },
+      "sources": { "type": "array", "items": { "type": "string" } }
+    },
+    "required": ["question", "options", "correct_index", "sources"]
+  }
+}`,
+			Task: `
+Generate a quiz to test understanding of the material.
+Questions should cover a range of difficulties unless a difficulty is given.
+Prefer conceptual understanding over memorization.
+Do not include trick questions.
+`,
+			Validate:     validateQuiz,
+			PostProcess:  dedupeItemsBy("question"),
+			Merge:        mergeItemsBy("question"),
+			HidesAnswers: true,
+		},
+		{
+			Name:        AnalysisFreeResponse,
+			Description: "Open questions answered in writing, each with a reference answer and a grading rubric",
+			Schema: `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "question": { "type": "string", "minLength": 1 },
+      "reference_answer": { "type": "string", "minLength": 1 },
+      "rubric": {
+        "type": "array",
+        "minItems": 1,
+        "items": {
+          "type": "object",
+          "properties": {
+            "criterion": { "type": "string", "minLength": 1 },
+            "points": { "type": "integer", "minimum": 1 }
+          },
+          "required": ["criterion", "points"]
+        }
+      },
+      "sources": { "type": "array", "items": { "type": "string" } }
+    },
+    "required": ["question", "reference_answer", "rubric", "sources"]
+  }
+}`,
+			Task: `
+Generate free-response questions that a student answers in a few sentences.
+Each question should call for explanation or reasoning, not a single word or a fact to recall.
+The reference answer is a model answer that would earn full marks.
+The rubric lists the distinct points a complete answer makes, each with the points it is worth.
+Criteria should be specific enough that two graders would agree whether an answer meets them.
+`,
+			PostProcess:  dedupeItemsBy("question"),
+			Merge:        mergeItemsBy("question"),
+			HidesAnswers: true,
+		},
+		{
+			Name:        AnalysisDeepSummary,
+			Description: "An in-depth explanation of each concept, as if teaching it for the first time",
+			Schema: `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "concept": { "type": "string", "minLength": 1 },
+      "definition": { "type": "string" },
+      "details": { "type": "string" },
+      "sources": { "type": "array", "items": { "type": "string" } }
+    },
+    "required": ["concept", "definition", "details", "sources"]
+  }
+}`,
+			Task: `
+Generate a comprehensive, in-depth explanation of the material.
+Break the content into distinct concepts or topics.
+Explain each concept clearly as if teaching it to a student encountering it for the first time.
+Include definitions, explanations, and relationships between ideas where appropriate.
+`,
+			PostProcess: dedupeItemsBy("concept"),
+			Merge:       mergeItemsBy("concept"),
+		},
+		{
+			Name:        AnalysisGlossary,
+			Description: "Key terms with short definitions, in alphabetical order",
+			Schema: `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "term": { "type": "string", "minLength": 1 },
+      "definition": { "type": "string", "minLength": 1 },
+      "sources": { "type": "array", "items": { "type": "string" } }
+    },
+    "required": ["term", "definition", "sources"]
+  }
+}`,
+			Task: `
+Generate a glossary of the key terms in the material.
+Include technical vocabulary, named theories, people, and abbreviations a student must know.
+Each definition should be one or two sentences, in the material's own sense of the term.
+`,
+			PostProcess: sortGlossary,
+			Merge:       mergeItemsBy("term"),
+		},
+		{
+			Name:        AnalysisTimeline,
+			Description: "Events or stages in chronological order, for history and process-heavy material",
+			Schema: `{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "when": { "type": "string", "minLength": 1 },
+      "event": { "type": "string", "minLength": 1 },
+      "details": { "type": "string" },
+      "sources": { "type": "array", "items": { "type": "string" } }
+    },
+    "required": ["when", "event", "details", "sources"]
+  }
+}`,
+			Task: `
+Generate a chronological timeline of the material.
+Each entry is a dated event, or for processes (e.g. in biology) a stage in the order it happens.
+"when" is the date, period or stage name exactly as precise as the material allows.
+List entries in chronological order, earliest first.
+`,
+			PostProcess: dedupeItemsBy("event"),
+			Merge:       mergeItemsBy("event"),
+		},
+		{
+			Name:        AnalysisConceptMap,
+			Description: "A graph of concepts and the relationships between them",
+			Schema: `{
+  "type": "object",
+  "properties": {
+    "nodes": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "type": "object",
+        "properties": {
+          "id": { "type": "string", "minLength": 1 },
+          "label": { "type": "string", "minLength": 1 },
+          "description": { "type": "string" },
+          "sources": { "type": "array", "items": { "type": "string" } }
+        },
+        "required": ["id", "label", "description", "sources"]
+      }
+    },
+    "edges": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "from": { "type": "string", "minLength": 1 },
+          "to": { "type": "string", "minLength": 1 },
+          "label": { "type": "string", "minLength": 1 }
+        },
+        "required": ["from", "to", "label"]
+      }
+    }
+  },
+  "required": ["nodes", "edges"]
+}`,
+			Task: `
+Generate a concept map of the material.
+Nodes are the central concepts; give each a short unique id such as "n1".
+Edges connect two node ids and are labelled with the relationship, read from -> to (e.g. "causes", "is a type of").
+Prefer a connected map with fewer, meaningful edges over linking everything.
+`,
+			Validate:    validateConceptMap,
+			PostProcess: tidyConceptMap,
+			Merge:       mergeConceptMaps,
+		},
+	}
+}
+
+// INTERNAL
+
+// sortGlossary orders terms alphabetically and drops repeated ones
+func sortGlossary(result json.RawMessage) (json.RawMessage, error) {
+	deduped, err := mergeItems([]json.RawMessage{result}, "term")
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []map[string]any
+	if err := json.Unmarshal(deduped, &terms); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool {
+		a, _ := terms[i]["term"].(string)
+		b, _ := terms[j]["term"].(string)
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+
+	return json.Marshal(terms)
+}
+
+// validateQuiz checks that every answer index points at one of the question's options
+func validateQuiz(result json.RawMessage) []string {
+	var questions []struct {
+		Options      []string `json:"options"`
+		CorrectIndex int      `json:"correct_index"`
+	}
+	if err := json.Unmarshal(result, &questions); err != nil {
+		return []string{err.Error()}
+	}
+
+	var violations []string
+	for i, question := range questions {
+		if question.CorrectIndex >= len(question.Options) {
+			violations = append(violations, fmt.Sprintf(
+				"$[%d].correct_index: %d is out of range for %d options",
+				i, question.CorrectIndex, len(question.Options),
+			))
+		}
+	}
+
+	return violations
+}
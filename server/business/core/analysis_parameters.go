@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const ( // Difficulty levels
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+const ( // Target audiences
+	AudienceIntro         = "intro"
+	AudienceUndergraduate = "undergraduate"
+	AudienceGraduate      = "graduate"
+)
+
+const ( // Lengths of written text such as summaries and explanations
+	LengthShort  = "short"
+	LengthMedium = "medium"
+	LengthLong   = "long"
+)
+
+const (
+	MaxAnalysisItems   = 100
+	MaxFocusTopics     = 10
+	MaxFocusTopicRunes = 200
+)
+
+// Errors
+var (
+	ErrInvalidAnalysisParameters error = errors.New("invalid analysis parameters")
+)
+
+var difficultyInstructions = map[string]string{
+	DifficultyEasy:   "Easy. Check recall of key facts and definitions.",
+	DifficultyMedium: "Medium. Mix recall with applying ideas to familiar situations.",
+	DifficultyHard:   "Hard. Require applying and connecting ideas, including multi-step reasoning.",
+}
+
+var audienceInstructions = map[string]string{
+	AudienceIntro:         "Students in an introductory course. Avoid jargon and explain terms when first used.",
+	AudienceUndergraduate: "Upper-year undergraduates who know the basics of the field.",
+	AudienceGraduate:      "Graduate students. Assume fluency with the field's terminology and go into nuance.",
+}
+
+var lengthInstructions = map[string]string{
+	LengthShort:  "Short. A few sentences per summary or explanation.",
+	LengthMedium: "Medium. A few paragraphs per summary, a paragraph per explanation.",
+	LengthLong:   "Long. Detailed, several paragraphs wherever the material supports it.",
+}
+
+// normalized trims and lowercases the parameters and drops blank or repeated focus topics,
+// so equivalent requests hash the same
+func (params AnalysisParameters) normalized() AnalysisParameters {
+	normalized := AnalysisParameters{
+		Count:      params.Count,
+		Difficulty: strings.ToLower(strings.TrimSpace(params.Difficulty)),
+		Audience:   strings.ToLower(strings.TrimSpace(params.Audience)),
+		Length:     strings.ToLower(strings.TrimSpace(params.Length)),
+	}
+
+	seen := map[string]bool{}
+	for _, topic := range params.FocusTopics {
+		topic = strings.TrimSpace(topic)
+		key := normalizeKey(topic)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized.FocusTopics = append(normalized.FocusTopics, topic)
+	}
+
+	return normalized
+}
+
+// validate reports the first parameter outside its allowed values
+func (params AnalysisParameters) validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidAnalysisParameters, fmt.Sprintf(format, args...))
+	}
+
+	if params.Count < 0 || params.Count > MaxAnalysisItems {
+		return invalid("count must be between 1 and %d", MaxAnalysisItems)
+	}
+	if params.Difficulty != "" && difficultyInstructions[params.Difficulty] == "" {
+		return invalid("unknown difficulty %q", params.Difficulty)
+	}
+	if params.Audience != "" && audienceInstructions[params.Audience] == "" {
+		return invalid("unknown audience %q", params.Audience)
+	}
+	if params.Length != "" && lengthInstructions[params.Length] == "" {
+		return invalid("unknown length %q", params.Length)
+	}
+	if len(params.FocusTopics) > MaxFocusTopics {
+		return invalid("at most %d focus topics are allowed", MaxFocusTopics)
+	}
+	if slices.ContainsFunc(params.FocusTopics, func(topic string) bool {
+		return len([]rune(topic)) > MaxFocusTopicRunes
+	}) {
+		return invalid("focus topics must be at most %d characters", MaxFocusTopicRunes)
+	}
+
+	return nil
+}
+
+// forChunk shares the requested item count out between the chunks of a map-reduce analysis
+func (params AnalysisParameters) forChunk(chunks int) AnalysisParameters {
+	if params.Count > 0 && chunks > 1 {
+		params.Count = (params.Count + chunks - 1) / chunks
+	}
+	return params
+}
+
+// instructions describes the parameters to the model, or returns "" when none are set
+func (params AnalysisParameters) instructions() string {
+	var lines []string
+
+	if params.Count > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"Count: produce %d items (cards, questions, terms, entries or nodes, as the schema defines them)",
+			params.Count,
+		))
+	}
+	if params.Difficulty != "" {
+		lines = append(lines, "Difficulty: "+difficultyInstructions[params.Difficulty])
+	}
+	if params.Audience != "" {
+		lines = append(lines, "Audience: "+audienceInstructions[params.Audience])
+	}
+	if params.Length != "" {
+		lines = append(lines, "Length: "+lengthInstructions[params.Length])
+	}
+	if len(params.FocusTopics) > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"Focus topics: %s. Cover other material only where it is needed to explain them.",
+			strings.Join(params.FocusTopics, "; "),
+		))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "- " + strings.Join(lines, "\n- ")
+}
+
+// encodeAnalysisParameters normalizes and validates requested parameters for storage
+func encodeAnalysisParameters(params AnalysisParameters) (json.RawMessage, error) {
+	params = params.normalized()
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(params)
+}
+
+// decodeAnalysisParameters reads parameters stored as JSONB
+func decodeAnalysisParameters(encoded json.RawMessage) (AnalysisParameters, error) {
+	var params AnalysisParameters
+	if len(encoded) == 0 {
+		return params, nil
+	}
+	err := json.Unmarshal(encoded, &params)
+	return params, err
+}
+
+// limitItems trims an array result to at most count items. Other results are returned unchanged.
+func limitItems(result json.RawMessage, count int) (json.RawMessage, error) {
+	if count <= 0 {
+		return result, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(result, &items); err != nil {
+		return result, nil // Not an array
+	}
+	if len(items) <= count {
+		return result, nil
+	}
+
+	return json.Marshal(items[:count])
+}
@@ -2,11 +2,50 @@ package core
 
 import (
 	"fmt"
-	"server/sqlc/sqlgen"
+	"strings"
 )
 
-// analysisInstructions generates AI instructions for a given analysis type
-func analysisInstructions(kind sqlgen.AnalysisType, schema string) string {
+// analysisPrompt is everything that shapes the instructions for an analysis
+type analysisPrompt struct {
+	Kind       AnalysisKind
+	Parameters AnalysisParameters
+	Template   *PromptTemplate // The course's template, nil for the default instructions
+	Course     string
+	Collection string
+}
+
+// analysisInstructions generates AI instructions for an analysis from the course's template, if it has one,
+// followed by the requested parameters
+func analysisInstructions(prompt analysisPrompt) (string, error) {
+	instructions := defaultAnalysisInstructions(prompt.Kind.Task, prompt.Kind.Schema)
+
+	if prompt.Template != nil {
+		rendered, err := renderPromptTemplate(prompt.Template.Body, PromptTemplateData{
+			Course:     prompt.Course,
+			Collection: prompt.Collection,
+			Kind:       prompt.Kind.Name,
+			Task:       prompt.Kind.Task,
+			Schema:     prompt.Kind.Schema,
+			Default:    instructions,
+		})
+		if err != nil {
+			return "", err
+		}
+		instructions = rendered
+	}
+
+	if parameters := prompt.Parameters.instructions(); parameters != "" {
+		instructions += fmt.Sprintf(`
+Parameters (these take precedence over the task where they differ):
+%s
+`, parameters)
+	}
+
+	return instructions, nil
+}
+
+// defaultAnalysisInstructions are the instructions for a task when the course has no template
+func defaultAnalysisInstructions(task string, schema string) string {
 	return fmt.Sprintf(`
 You are analyzing study materials from a university course.
 
@@ -17,51 +56,47 @@ Rules:
 - Do not invent topics
 - Follow the JSON schema EXACTLY
 - Do not include markdown or newlines
+- The content is divided into sources, each starting with a label like [S1].
+  Where the schema has a "sources" field, list the labels (e.g. "S1") of the sources that support it
 
 Schema:
 %s
-`, taskDescription(kind), schema)
+`, task, schema)
 }
 
-// taskDescription returns the task description for a given analysis type
-func taskDescription(kind sqlgen.AnalysisType) string {
-	switch kind {
-
-	case sqlgen.AnalysisTypeSummary:
-		return `
-Generate a concise summary of the material.
-Focus on the main ideas, definitions, and themes.
-Assume the reader is a student reviewing before class or an exam.
-Keep it brief but accurate.
-`
-
-	case sqlgen.AnalysisTypeFlashcards:
-		return `
-Generate a set of study flashcards.
-Each flashcard should test a single concept, definition, or fact.
-Questions should be clear and unambiguous.
-Answers should be short, correct, and directly supported by the material.
-`
-
-	case sqlgen.AnalysisTypeQuiz:
-		return `
-Generate a short quiz to test understanding of the material.
-Questions should cover a range of difficulties.
-Prefer conceptual understanding over memorization.
-Do not include trick questions.
-`
-
-	case sqlgen.AnalysisTypeDeepSummary:
-		return `
-Generate a comprehensive, in-depth explanation of the material.
-Break the content into distinct concepts or topics.
-Explain each concept clearly as if teaching it to a student encountering it for the first time.
-Include definitions, explanations, and relationships between ideas where appropriate.
-`
-
-	default:
-		return `
-Analyze the provided material and extract its key educational content.
-`
-	}
+// analysisRepairInput asks the model to correct a response that failed schema validation
+func analysisRepairInput(content string, previous string, violations []string) string {
+	return fmt.Sprintf(`%s
+
+---
+
+Your previous response did not match the JSON schema.
+
+Previous response:
+%s
+
+Validation errors:
+- %s
+
+Return a corrected response that follows the schema EXACTLY.
+Respond with the JSON document only.
+`, content, previous, strings.Join(violations, "\n- "))
+}
+
+// analysisChunkInput labels one chunk of material that was too large to analyze in a single call
+func analysisChunkInput(index int, total int, chunk string) string {
+	return fmt.Sprintf(`The material was too long to analyze at once.
+This is part %d of %d. Analyze only this part.
+
+%s
+`, index+1, total, chunk)
+}
+
+// analysisReduceInput asks the model to condense the results for consecutive parts into one
+func analysisReduceInput(parts []string) string {
+	return fmt.Sprintf(`The following were written about consecutive parts of the same material.
+Treat them as the material and combine them into one result without repeating points.
+
+%s
+`, strings.Join(parts, "\n\n"))
 }
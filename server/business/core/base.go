@@ -4,7 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"server/api/logging"
 	"server/api/serviceaccess"
+	"server/api/tools/features/anki"
+	"server/api/tools/features/llm"
+	"server/api/tools/features/pdfextract"
+	"server/api/tools/features/studyguide"
 	"server/api/tools/features/thumbnails"
 	"server/environment"
 	"server/sqlc/sqlgen"
@@ -12,7 +17,6 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
-	"github.com/openai/openai-go/v3"
 )
 
 type core_interface interface {
@@ -27,13 +31,96 @@ type core_interface interface {
 	PresignedGetDocument(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*url.URL, error)
 
 	// Analysis operations
-	AnalyzeCollection(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, kind sqlgen.AnalysisType) (*CollectionAnalysis, error)
+	AnalyzeCollection(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, request AnalysisRequest) (*CollectionAnalysis, error)
 	GetCollectionAnalyses(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID) ([]CollectionAnalysis, error)
+	ListAnalysisKinds() []AnalysisKind
+
+	// Prompt template operations
+	SavePromptTemplate(ctx context.Context, userID uuid.UUID, course string, kind string, body string) (*PromptTemplate, error)
+	GetPromptTemplates(ctx context.Context, userID uuid.UUID, course string) ([]PromptTemplate, error)
+	RetirePromptTemplates(ctx context.Context, userID uuid.UUID, course string, kind string) error
+
+	// Analysis item operations
+	GetAnalysisItems(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) ([]AnalysisItem, error)
+	AddAnalysisItem(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID, content json.RawMessage) (*AnalysisItem, error)
+	EditAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID, content json.RawMessage) (*AnalysisItem, error)
+	RevertAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) (*AnalysisItem, error)
+	DeleteAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error
+	ReorderAnalysisItems(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID, order []uuid.UUID) ([]AnalysisItem, error)
+
+	// Export operations
+	ExportFlashcards(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID, format ExportFormat) (*Export, error)
+	ExportCourseFlashcards(ctx context.Context, userID uuid.UUID, course string, format ExportFormat) (*Export, error)
+	ExportStudyGuide(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, format StudyGuideFormat) (*Export, error)
+
+	// Import operations
+	ImportItems(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, kind string, format ImportFormat, data []byte) (*ImportResult, error)
+
+	// Review operations
+	EnrollFlashcards(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) ([]ReviewCard, error)
+	GetDueReviews(ctx context.Context, userID uuid.UUID, filter ReviewQueueFilter) (*ReviewQueue, error)
+	GradeReviewCard(ctx context.Context, userID uuid.UUID, cardID uuid.UUID, grade int) (*ReviewCard, error)
+
+	// Quiz attempt operations
+	StartQuizAttempt(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) (*QuizAttempt, error)
+	SubmitQuizAttempt(ctx context.Context, userID uuid.UUID, attemptID uuid.UUID, answers []QuizAnswer) (*QuizAttempt, error)
+	GetQuizAttempt(ctx context.Context, userID uuid.UUID, attemptID uuid.UUID) (*QuizAttempt, error)
+	GetQuizAttemptsByAnalysis(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) ([]QuizAttempt, error)
+	GetQuizAttemptsByCourse(ctx context.Context, userID uuid.UUID, course string) ([]QuizAttempt, error)
+
+	// Chat operations
+	CreateCollectionChat(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID) (*Chat, error)
+	CreateCourseChat(ctx context.Context, userID uuid.UUID, course string) (*Chat, error)
+	GetCollectionChats(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID) ([]Chat, error)
+	GetCourseChats(ctx context.Context, userID uuid.UUID, course string) ([]Chat, error)
+	GetChat(ctx context.Context, userID uuid.UUID, chatID uuid.UUID) (*Chat, error)
+	DeleteChat(ctx context.Context, userID uuid.UUID, chatID uuid.UUID) error
+	SendChatMessage(ctx context.Context, userID uuid.UUID, chatID uuid.UUID, request ChatRequest) (*ChatMessage, error)
+
+	// Free-response operations
+	GradeFreeResponse(ctx context.Context, userID uuid.UUID, itemID uuid.UUID, answer string, providerName string) (*FreeResponseGrade, error)
+
+	// Search operations
+	Search(ctx context.Context, userID uuid.UUID, query string, filter SearchFilter) (*SearchResults, error)
+	StartEmbeddingBackfill(ctx context.Context)
+
+	// Usage operations
+	GetUsage(ctx context.Context, userID uuid.UUID) (*UsageSummary, error)
+
+	// Analysis job operations
+	EnqueueAnalysis(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, request AnalysisRequest) (*AnalysisJob, error)
+	GetAnalysisJob(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*AnalysisJob, error)
+	StartAnalysisWorkers(ctx context.Context)
 
 	// Internal
-	extractDocumentContent(ctx context.Context, doc sqlgen.Document) (*DocumentTextExtraction, error)
-	createSnapshot(ctx context.Context, collectionID uuid.UUID) (*CollectionSnapshot, error)
-	runAnalysis(ctx context.Context, content string, kind sqlgen.AnalysisType) (json.RawMessage, error)
+	analysisItems(ctx context.Context, analysisID uuid.UUID) ([]AnalysisItem, error)
+	userItemizedAnalysis(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) (AnalysisKind, error)
+	userAnalysisItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) (*sqlgen.GetUserAnalysisItemRow, error)
+	quizItems(ctx context.Context, analysisID uuid.UUID) ([]quizItem, error)
+	flashcardDeck(ctx context.Context, analysis sqlgen.GetLatestCourseAnalysesRow) (*anki.Deck, error)
+	studyGuide(ctx context.Context, analyses []sqlgen.CollectionAnalysis) (*studyguide.Guide, error)
+	chats(ctx context.Context, params sqlgen.GetChatsParams) ([]Chat, error)
+	chatMaterial(ctx context.Context, userID uuid.UUID, chat *Chat) ([]sqlgen.GetDocumentExtractionsByCollectionRow, error)
+	activePromptTemplate(ctx context.Context, userID uuid.UUID, course string, kind string) (*PromptTemplate, error)
+	ensureDocumentExtraction(ctx context.Context, provider llm.Provider, userID uuid.UUID, doc sqlgen.Document) (bool, error)
+	extractDocumentContent(ctx context.Context, provider llm.Provider, doc sqlgen.Document) ([]ExtractedPage, error)
+	extractPDFContent(ctx context.Context, provider llm.Provider, doc sqlgen.Document) ([]ExtractedPage, error)
+	storeExtraction(ctx context.Context, documentID uuid.UUID, pages []ExtractedPage) (bool, error)
+	embedDocument(ctx context.Context, userID uuid.UUID, documentID uuid.UUID)
+	embedExtractions(ctx context.Context, embedder llm.Embedder, extractions []sqlgen.GetUnembeddedExtractionsRow) int
+	embedExtraction(ctx context.Context, embedder llm.Embedder, extraction sqlgen.GetUnembeddedExtractionsRow) error
+	backfillEmbeddings(ctx context.Context, after *embeddingCursor) (*embeddingCursor, error)
+	collectionContent(ctx context.Context, collectionID uuid.UUID) (string, []SourceAnchor, error)
+	createSnapshot(ctx context.Context, collectionID uuid.UUID, content string, contentHash string, sources []SourceAnchor) (*CollectionSnapshot, error)
+	analyzeContent(ctx context.Context, provider llm.Provider, content string, prompt analysisPrompt) (json.RawMessage, error)
+	reduceAnalyses(ctx context.Context, provider llm.Provider, prompt analysisPrompt, partials []json.RawMessage) (json.RawMessage, error)
+	condenseAnalyses(ctx context.Context, provider llm.Provider, prompt analysisPrompt, partials []json.RawMessage) (json.RawMessage, error)
+	runAnalysis(ctx context.Context, provider llm.Provider, content string, prompt analysisPrompt) (json.RawMessage, error)
+	runAnalysisJob(ctx context.Context, job sqlgen.AnalysisJob) (*CollectionAnalysis, error)
+	checkQuota(ctx context.Context, userID uuid.UUID) error
+	recordUsage(ctx context.Context, userID uuid.UUID, kind sqlgen.UsageKind, recorder llm.UsageTracker)
+	quotaLimits(ctx context.Context, userID uuid.UUID) (QuotaLimits, error)
+	usageSince(ctx context.Context, userID uuid.UUID, since time.Time) (*UsagePeriod, error)
 }
 
 type Core struct {
@@ -42,6 +129,25 @@ type Core struct {
 	UploadBucket       string
 	PresignedExpiry    time.Duration
 	ThumbnailGenerator *thumbnails.Generator
+	PDFRasterizer      pdfextract.Rasterizer // nil when pdftoppm isn't installed
+
+	ExtractionWorkers int
+
+	AnalysisKinds *AnalysisKindRegistry
+
+	AnalysisWorkers         int
+	AnalysisJobPollInterval time.Duration
+	AnalysisJobTimeout      time.Duration
+	AnalysisJobMaxAttempts  int
+	analysisJobWake         chan struct{}
+
+	AnalysisRepairAttempts int
+	AnalysisContextTokens  int
+	AnalysisChunkTokens    int
+
+	EmbeddingBackfillInterval time.Duration // 0 when the backfill is disabled
+
+	Quotas QuotaLimits
 }
 
 func NewCore(services *serviceaccess.Access, env *environment.Vars) (*Core, error) {
@@ -64,17 +170,58 @@ func NewCore(services *serviceaccess.Access, env *environment.Vars) (*Core, erro
 
 	thumbGen := thumbnails.NewGenerator(services.Minio, bucketName, presignedExpiry)
 
+	// Scanned PDFs can't be read without a rasterizer, but text PDFs and images still can
+	var rasterizer pdfextract.Rasterizer
+	poppler, err := pdfextract.NewPopplerRasterizer(pdfextract.NewPopplerRasterizerParams{
+		Binary: env.PDFRasterizerBin,
+		DPI:    env.PDFRasterDPI,
+	})
+	if err != nil {
+		logging.Warn("scanned pdf pages will fail to extract", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		rasterizer = poppler
+	}
+
+	analysisKinds, err := NewAnalysisKindRegistry(NewAnalysisKindRegistryParams{
+		Kinds: BuiltinAnalysisKinds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var intf core_interface = &Core{
 		Services:           services,
 		Queries:            sqlgen.New(services.Postgres),
 		UploadBucket:       bucketName,
 		PresignedExpiry:    presignedExpiry,
 		ThumbnailGenerator: thumbGen,
+		PDFRasterizer:      rasterizer,
+
+		ExtractionWorkers: max(env.ExtractionWorkers, 1),
+
+		AnalysisKinds: analysisKinds,
+
+		AnalysisWorkers:         env.AnalysisWorkers,
+		AnalysisJobPollInterval: time.Second * time.Duration(env.AnalysisJobPollSecs),
+		AnalysisJobTimeout:      time.Minute * time.Duration(env.AnalysisJobTimeoutMins),
+		AnalysisJobMaxAttempts:  max(env.AnalysisJobMaxAttempts, 1),
+		analysisJobWake:         make(chan struct{}, 1),
+
+		AnalysisRepairAttempts: env.AnalysisRepairAttempts,
+		AnalysisContextTokens:  env.AnalysisContextTokens,
+		AnalysisChunkTokens:    env.AnalysisChunkTokens,
+
+		EmbeddingBackfillInterval: time.Second * time.Duration(env.EmbeddingBackfillSecs),
+
+		Quotas: QuotaLimits{
+			DailyTokens:     env.QuotaDailyTokens,
+			MonthlyTokens:   env.QuotaMonthlyTokens,
+			DailyRequests:   env.QuotaDailyRequests,
+			MonthlyRequests: env.QuotaMonthlyRequests,
+		},
 	}
 
 	return intf.(*Core), nil
 }
-
-const ( // Consts
-	ChatModel = openai.ChatModelGPT5ChatLatest
-)
@@ -0,0 +1,471 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"server/api/tools/features/llm"
+	"server/sqlc/sqlgen"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxChatMessageLength = 4000 // Characters
+
+	chatHistoryMessages = 20 // Earlier messages sent with a question, for follow-ups
+	chatTitleLength     = 80
+)
+
+const ( // Chat message roles
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+)
+
+// Errors
+var (
+	ErrChatNotFound       error = errors.New("chat not found")
+	ErrInvalidChatMessage error = errors.New("invalid chat message")
+	ErrNoChatMaterial     error = errors.New("there is no extracted content to answer from")
+)
+
+// Chat is a conversation with the notes of a collection, or of every collection in a course
+type Chat struct {
+	ID           uuid.UUID
+	CollectionID *uuid.UUID // Set for chats over a collection
+	Course       string     // Set for chats over a course
+	Title        string     // The first question, empty until one is asked
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Messages []ChatMessage // Set when a chat is fetched on its own
+}
+
+type ChatMessage struct {
+	ID        uuid.UUID
+	Role      string // One of the ChatRole constants
+	Content   string
+	CreatedAt time.Time
+
+	// Sources are the pages an answer cites. Its [1], [2], ... markers number them.
+	Sources []Source
+
+	// Usage is what an answer cost
+	Usage llm.Usage
+}
+
+// ChatRequest is a question asked in a chat
+type ChatRequest struct {
+	Content  string
+	Provider string // Empty for the deployment default
+
+	// OnDelta, when set, receives the answer as it is generated. Streamed text cites [S#] labels;
+	// the returned message has them replaced with numbered markers.
+	OnDelta func(delta string)
+}
+
+var chatCitationPattern = regexp.MustCompile(`\[\s*S\d+(?:\s*,\s*S\d+)*\s*\]`)
+
+// CreateCollectionChat starts a chat over one of the user's collections
+func (core Core) CreateCollectionChat(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID) (*Chat, error) {
+	if _, err := core.Queries.GetCollection(ctx, sqlgen.GetCollectionParams{
+		UserID: userID,
+		ID:     collectionID,
+	}); errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCollectionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	row, err := core.Queries.CreateChat(ctx, sqlgen.CreateChatParams{
+		UserID:       userID,
+		CollectionID: uuid.NullUUID{UUID: collectionID, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chatFromRow(row), nil
+}
+
+// CreateCourseChat starts a chat over every collection in one of the user's courses
+func (core Core) CreateCourseChat(ctx context.Context, userID uuid.UUID, course string) (*Chat, error) {
+	exists, err := core.Queries.CourseExists(ctx, sqlgen.CourseExistsParams{
+		Name:   course,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCourseNotFound
+	}
+
+	row, err := core.Queries.CreateChat(ctx, sqlgen.CreateChatParams{
+		UserID: userID,
+		Course: sql.NullString{String: course, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chatFromRow(row), nil
+}
+
+// GetCollectionChats lists the user's chats over a collection, most recently active first
+func (core Core) GetCollectionChats(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID) ([]Chat, error) {
+	return core.chats(ctx, sqlgen.GetChatsParams{
+		UserID:       userID,
+		CollectionID: uuid.NullUUID{UUID: collectionID, Valid: true},
+	})
+}
+
+// GetCourseChats lists the user's chats over a course, most recently active first
+func (core Core) GetCourseChats(ctx context.Context, userID uuid.UUID, course string) ([]Chat, error) {
+	return core.chats(ctx, sqlgen.GetChatsParams{
+		UserID: userID,
+		Course: sql.NullString{String: course, Valid: true},
+	})
+}
+
+// GetChat returns a chat with its messages, oldest first
+func (core Core) GetChat(ctx context.Context, userID uuid.UUID, chatID uuid.UUID) (*Chat, error) {
+	row, err := core.Queries.GetChat(ctx, sqlgen.GetChatParams{
+		ID:     chatID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChatNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := core.Queries.GetChatMessages(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	chat := chatFromRow(row)
+	chat.Messages = make([]ChatMessage, 0, len(messages))
+	for _, message := range messages {
+		result, err := chatMessageFromRow(message)
+		if err != nil {
+			return nil, err
+		}
+		chat.Messages = append(chat.Messages, *result)
+	}
+
+	return chat, nil
+}
+
+func (core Core) DeleteChat(ctx context.Context, userID uuid.UUID, chatID uuid.UUID) error {
+	deleted, err := core.Queries.DeleteChat(ctx, sqlgen.DeleteChatParams{
+		ID:     chatID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrChatNotFound
+	}
+	return nil
+}
+
+// SendChatMessage answers a question from the chat's notes and stores both. The answer is drawn only from
+// the documents' existing extractions, picking the pages closest to the question and the conversation, and
+// cites the pages it used. Documents that haven't been extracted yet aren't read. Answers count towards the
+// user's quota like analyses.
+func (core Core) SendChatMessage(
+	ctx context.Context,
+	userID uuid.UUID,
+	chatID uuid.UUID,
+	request ChatRequest,
+) (*ChatMessage, error) {
+
+	question := strings.TrimSpace(request.Content)
+	if question == "" {
+		return nil, fmt.Errorf("%w: the message is empty", ErrInvalidChatMessage)
+	}
+	if utf8.RuneCountInString(question) > MaxChatMessageLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidChatMessage, MaxChatMessageLength)
+	}
+
+	askedAt := time.Now()
+
+	chat, err := core.GetChat(ctx, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := core.Services.LLM.Get(request.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := core.checkQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	extractions, err := core.chatMaterial(ctx, userID, chat)
+	if err != nil {
+		return nil, err
+	}
+	if len(extractions) == 0 {
+		return nil, ErrNoChatMaterial
+	}
+
+	history := chat.Messages[max(len(chat.Messages)-chatHistoryMessages, 0):]
+
+	// Follow-ups ("why?") are found through the question before them and the pages its answer cited
+	query := []string{question}
+	var cited []Source
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == ChatRoleAssistant && cited == nil {
+			cited = history[i].Sources
+		}
+		if history[i].Role == ChatRoleUser {
+			query = append(query, history[i].Content)
+			break
+		}
+	}
+
+	content, anchors := relevantMaterial(extractions, strings.Join(query, " "), cited, core.AnalysisContextTokens)
+
+	recorder := llm.NewUsageRecorder(provider)
+	defer core.recordUsage(ctx, userID, sqlgen.UsageKindAnalysis, recorder)
+
+	completion, err := recorder.Complete(ctx, llm.CompletionRequest{
+		Instructions: chatInstructions(),
+		Input:        chatInput(content, history, question),
+		OnDelta:      request.OnDelta,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	answer, sources := resolveChatCitations(strings.TrimSpace(completion.Text), anchors)
+	encodedSources, err := json.Marshal(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	// The question is only kept with its answer, so a failed answer can simply be asked again
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+
+	if _, err := q.CreateChatMessage(ctx, sqlgen.CreateChatMessageParams{
+		ChatID:    chatID,
+		Role:      ChatRoleUser,
+		Content:   question,
+		Sources:   json.RawMessage(`[]`),
+		CreatedAt: askedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	usage := recorder.Usage()
+	row, err := q.CreateChatMessage(ctx, sqlgen.CreateChatMessageParams{
+		ChatID:       chatID,
+		Role:         ChatRoleAssistant,
+		Content:      answer,
+		Sources:      encodedSources,
+		Model:        usage.Model,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		LatencyMs:    usage.Latency.Milliseconds(),
+		Requests:     int32(usage.Requests),
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := q.TouchChat(ctx, sqlgen.TouchChatParams{
+		ID:    chatID,
+		Title: chatTitle(question),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return chatMessageFromRow(row)
+}
+
+// INTERNAL
+
+func (core Core) chats(ctx context.Context, params sqlgen.GetChatsParams) ([]Chat, error) {
+	rows, err := core.Queries.GetChats(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	chats := make([]Chat, 0, len(rows))
+	for _, row := range rows {
+		chats = append(chats, *chatFromRow(row))
+	}
+
+	return chats, nil
+}
+
+// chatMaterial reads the extracted pages a chat answers from. Course rows have the same columns as
+// collection rows.
+func (core Core) chatMaterial(ctx context.Context, userID uuid.UUID, chat *Chat) ([]sqlgen.GetDocumentExtractionsByCollectionRow, error) {
+	if chat.CollectionID != nil {
+		return core.Queries.GetDocumentExtractionsByCollection(ctx, *chat.CollectionID)
+	}
+
+	rows, err := core.Queries.GetDocumentExtractionsByCourse(ctx, sqlgen.GetDocumentExtractionsByCourseParams{
+		UserID: userID,
+		Course: chat.Course,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extractions := make([]sqlgen.GetDocumentExtractionsByCollectionRow, 0, len(rows))
+	for _, row := range rows {
+		extractions = append(extractions, sqlgen.GetDocumentExtractionsByCollectionRow(row))
+	}
+
+	return extractions, nil
+}
+
+func chatInstructions() string {
+	return `
+You answer a student's questions about the study materials of a university course.
+
+Rules:
+- Answer ONLY from the provided content. If it doesn't answer the question, say the notes don't cover it
+  instead of answering from general knowledge
+- The content is divided into sources, each starting with a label like [S1].
+  Cite the labels that support each statement in square brackets right after it, e.g. [S2] or [S1, S3]
+- Be concise and write plain text. Write math as LaTeX between $ signs
+- The conversation so far is there for follow-up questions: answer only the latest question
+`
+}
+
+// chatInput puts the conversation so far and the question after the material they are answered from
+func chatInput(content string, history []ChatMessage, question string) string {
+	var conversation strings.Builder
+	for _, message := range history {
+		speaker := "Student"
+		if message.Role == ChatRoleAssistant {
+			speaker = "Assistant"
+		}
+		fmt.Fprintf(&conversation, "%s: %s\n\n", speaker, message.Content)
+	}
+	if conversation.Len() == 0 {
+		conversation.WriteString("(none)\n\n")
+	}
+
+	return fmt.Sprintf(`%s
+
+---
+
+Conversation so far:
+
+%s---
+
+Question:
+%s
+`, content, conversation.String(), question)
+}
+
+// resolveChatCitations replaces the [S#] labels an answer cites with [1], [2], ... numbering the sources
+// in the order they are first cited. Labels the material doesn't know are dropped.
+func resolveChatCitations(answer string, anchors []SourceAnchor) (string, []Source) {
+	byLabel := make(map[string]SourceAnchor, len(anchors))
+	for _, anchor := range anchors {
+		byLabel[anchor.Label] = anchor
+	}
+
+	sources := []Source{}
+	numbers := map[string]int{}
+
+	answer = chatCitationPattern.ReplaceAllStringFunc(answer, func(citation string) string {
+		var cited []string
+		for _, label := range strings.Split(strings.Trim(citation, "[] "), ",") {
+			anchor, ok := byLabel[strings.TrimSpace(label)]
+			if !ok {
+				continue
+			}
+			number, ok := numbers[anchor.Label]
+			if !ok {
+				sources = append(sources, Source{
+					DocumentID: anchor.DocumentID,
+					Title:      anchor.Title,
+					Page:       anchor.Page,
+				})
+				number = len(sources)
+				numbers[anchor.Label] = number
+			}
+			cited = append(cited, strconv.Itoa(number))
+		}
+		if len(cited) == 0 {
+			return ""
+		}
+		return "[" + strings.Join(cited, ", ") + "]"
+	})
+
+	return answer, sources
+}
+
+// chatTitle shortens a question to name a chat after it
+func chatTitle(question string) string {
+	title := strings.Join(strings.Fields(question), " ")
+	if utf8.RuneCountInString(title) <= chatTitleLength {
+		return title
+	}
+	runes := []rune(title)
+	return strings.TrimSpace(string(runes[:chatTitleLength-1])) + "…"
+}
+
+func chatFromRow(row sqlgen.Chat) *Chat {
+	return &Chat{
+		ID:           row.ID,
+		CollectionID: nullableUUID(row.CollectionID),
+		Course:       row.Course.String,
+		Title:        row.Title,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
+
+func chatMessageFromRow(row sqlgen.ChatMessage) (*ChatMessage, error) {
+	message := &ChatMessage{
+		ID:        row.ID,
+		Role:      row.Role,
+		Content:   row.Content,
+		CreatedAt: row.CreatedAt,
+		Usage: llm.Usage{
+			Model:        row.Model,
+			InputTokens:  row.InputTokens,
+			OutputTokens: row.OutputTokens,
+			Latency:      time.Duration(row.LatencyMs) * time.Millisecond,
+			Requests:     int(row.Requests),
+		},
+	}
+
+	if err := json.Unmarshal(row.Sources, &message.Sources); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
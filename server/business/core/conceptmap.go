@@ -0,0 +1,149 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// conceptMap is the result of the concept_map analysis kind
+type conceptMap struct {
+	Nodes []conceptNode `json:"nodes"`
+	Edges []conceptEdge `json:"edges"`
+}
+
+type conceptNode struct {
+	ID          string   `json:"id"`
+	Label       string   `json:"label"`
+	Description string   `json:"description"`
+	Sources     []string `json:"sources"`
+}
+
+type conceptEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+// validateConceptMap checks that node ids are unique and every edge joins two existing nodes
+func validateConceptMap(result json.RawMessage) []string {
+	var graph conceptMap
+	if err := json.Unmarshal(result, &graph); err != nil {
+		return []string{err.Error()}
+	}
+
+	var violations []string
+
+	ids := map[string]bool{}
+	for i, node := range graph.Nodes {
+		if ids[node.ID] {
+			violations = append(violations, fmt.Sprintf("$.nodes[%d].id: %q is used by another node", i, node.ID))
+		}
+		ids[node.ID] = true
+	}
+
+	for i, edge := range graph.Edges {
+		if !ids[edge.From] {
+			violations = append(violations, fmt.Sprintf("$.edges[%d].from: no node has id %q", i, edge.From))
+		}
+		if !ids[edge.To] {
+			violations = append(violations, fmt.Sprintf("$.edges[%d].to: no node has id %q", i, edge.To))
+		}
+	}
+
+	return violations
+}
+
+// tidyConceptMap drops self-loops and repeated edges
+func tidyConceptMap(result json.RawMessage) (json.RawMessage, error) {
+	var graph conceptMap
+	if err := json.Unmarshal(result, &graph); err != nil {
+		return nil, err
+	}
+
+	graph.Edges = dedupeEdges(graph.Edges)
+
+	return json.Marshal(graph)
+}
+
+// mergeConceptMaps unions per-chunk maps. Nodes with the same label become one node,
+// so ids are reassigned and edges rewritten to match.
+func mergeConceptMaps(partials []json.RawMessage) (json.RawMessage, error) {
+	merged := conceptMap{Nodes: []conceptNode{}, Edges: []conceptEdge{}}
+	byLabel := map[string]int{}
+
+	for _, partial := range partials {
+		var graph conceptMap
+		if err := json.Unmarshal(partial, &graph); err != nil {
+			return nil, err
+		}
+
+		remap := map[string]string{}
+		for _, node := range graph.Nodes {
+			key := normalizeKey(node.Label)
+
+			if i, ok := byLabel[key]; ok {
+				existing := &merged.Nodes[i]
+				existing.Sources = appendUnique(existing.Sources, node.Sources...)
+				if existing.Description == "" {
+					existing.Description = node.Description
+				}
+				remap[node.ID] = existing.ID
+				continue
+			}
+
+			id := fmt.Sprintf("n%d", len(merged.Nodes)+1)
+			remap[node.ID] = id
+			byLabel[key] = len(merged.Nodes)
+
+			node.ID = id
+			node.Sources = appendUnique(nil, node.Sources...)
+			merged.Nodes = append(merged.Nodes, node)
+		}
+
+		for _, edge := range graph.Edges {
+			from, fromOK := remap[edge.From]
+			to, toOK := remap[edge.To]
+			if !fromOK || !toOK {
+				continue
+			}
+			merged.Edges = append(merged.Edges, conceptEdge{From: from, To: to, Label: edge.Label})
+		}
+	}
+
+	merged.Edges = dedupeEdges(merged.Edges)
+
+	return json.Marshal(merged)
+}
+
+// dedupeEdges drops self-loops and edges repeating an earlier one's endpoints and relationship
+func dedupeEdges(edges []conceptEdge) []conceptEdge {
+	seen := map[conceptEdge]bool{}
+	deduped := []conceptEdge{}
+
+	for _, edge := range edges {
+		if edge.From == edge.To {
+			continue
+		}
+		key := conceptEdge{From: edge.From, To: edge.To, Label: normalizeKey(edge.Label)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, edge)
+	}
+
+	return deduped
+}
+
+func appendUnique(values []string, additions ...string) []string {
+	if values == nil {
+		values = []string{}
+	}
+	for _, addition := range additions {
+		if !slices.Contains(values, addition) {
+			values = append(values, addition)
+		}
+	}
+	return values
+}
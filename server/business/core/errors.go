@@ -0,0 +1,23 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnalysisValidationError is returned when the model's output still breaks
+// the analysis schema after every repair attempt
+type AnalysisValidationError struct {
+	Kind       string
+	Attempts   int
+	Violations []string
+}
+
+func (err *AnalysisValidationError) Error() string {
+	return fmt.Sprintf(
+		"%s analysis failed schema validation after %d attempts: %s",
+		err.Kind,
+		err.Attempts,
+		strings.Join(err.Violations, "; "),
+	)
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"server/sqlc/sqlgen"
+
+	"github.com/google/uuid"
+)
+
+// AnalysisEvent reports the progress of a running analysis
+type AnalysisEvent struct {
+	Type string
+	Data any
+}
+
+// AnalysisObserver receives events as an analysis runs. It may be called from several goroutines at once.
+type AnalysisObserver func(event AnalysisEvent)
+
+const ( // Analysis event types
+	AnalysisEventStatus            = "status"
+	AnalysisEventDocumentExtracted = "document_extracted"
+	AnalysisEventSnapshotCreated   = "snapshot_created"
+	AnalysisEventToken             = "token"
+	AnalysisEventPartialResult     = "partial_result"
+)
+
+type StatusEvent struct {
+	Status sqlgen.AnalysisJobStatus `json:"status"`
+}
+
+// DocumentExtractedEvent reports a finished document. Documents are extracted concurrently,
+// so they finish out of order: Index is the document's position and Completed counts finished documents.
+type DocumentExtractedEvent struct {
+	DocumentID uuid.UUID `json:"documentId"`
+	Index      int       `json:"index"`
+	Total      int       `json:"total"`
+	Completed  int       `json:"completed"`
+	Cached     bool      `json:"cached"` // The document already had an extraction
+}
+
+type SnapshotCreatedEvent struct {
+	SnapshotID uuid.UUID `json:"snapshotId"`
+}
+
+// TokenEvent carries text as the model generates it. A new attempt restarts the output. Quizzes send none,
+// since their raw output holds the answers.
+type TokenEvent struct {
+	Attempt int    `json:"attempt"`
+	Delta   string `json:"delta"`
+}
+
+// PartialResultEvent carries the validated result of one chunk of a map-reduce analysis
+type PartialResultEvent struct {
+	Chunk  int             `json:"chunk"`
+	Total  int             `json:"total"`
+	Result json.RawMessage `json:"result"`
+}
+
+// WithAnalysisObserver returns a context that reports analysis progress to observer
+func WithAnalysisObserver(ctx context.Context, observer AnalysisObserver) context.Context {
+	return context.WithValue(ctx, analysisObserverKey{}, observer)
+}
+
+// INTERNAL
+
+type analysisObserverKey struct{}
+
+func analysisObserverFrom(ctx context.Context) AnalysisObserver {
+	observer, _ := ctx.Value(analysisObserverKey{}).(AnalysisObserver)
+	return observer
+}
+
+// emitAnalysisEvent reports an event to the context's observer, if any
+func emitAnalysisEvent(ctx context.Context, eventType string, data any) {
+	if observer := analysisObserverFrom(ctx); observer != nil {
+		observer(AnalysisEvent{
+			Type: eventType,
+			Data: data,
+		})
+	}
+}
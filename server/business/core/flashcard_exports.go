@@ -0,0 +1,236 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"server/api/tools/features/anki"
+	"server/sqlc/sqlgen"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat is a file format flashcards can be exported to
+type ExportFormat string
+
+const (
+	ExportApkg ExportFormat = "apkg" // Anki package with a deck per collection
+	ExportCSV  ExportFormat = "csv"
+	ExportTSV  ExportFormat = "tsv"
+)
+
+// Errors
+var (
+	ErrInvalidExportFormat error = errors.New("invalid export format")
+	ErrNoFlashcards        error = errors.New("no flashcards to export")
+)
+
+// Export is a generated file, ready to download
+type Export struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ExportFlashcards exports the cards of a flashcard analysis, with the user's edits, as a single deck
+func (core Core) ExportFlashcards(
+	ctx context.Context,
+	userID uuid.UUID,
+	analysisID uuid.UUID,
+	format ExportFormat,
+) (*Export, error) {
+
+	if !format.valid() {
+		return nil, ErrInvalidExportFormat
+	}
+
+	analysis, err := core.Queries.GetUserAnalysisCollection(ctx, sqlgen.GetUserAnalysisCollectionParams{
+		ID:     analysisID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnalysisNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if analysis.Type != AnalysisFlashcards {
+		return nil, ErrNotFlashcards
+	}
+
+	deck, err := core.flashcardDeck(ctx, sqlgen.GetLatestCourseAnalysesRow(analysis))
+	if err != nil {
+		return nil, err
+	}
+
+	return exportFlashcards(ctx, []anki.Deck{*deck}, format, analysis.CollectionTitle)
+}
+
+// ExportCourseFlashcards exports the newest flashcard analysis of every collection in a course, a deck per collection
+func (core Core) ExportCourseFlashcards(
+	ctx context.Context,
+	userID uuid.UUID,
+	course string,
+	format ExportFormat,
+) (*Export, error) {
+
+	if !format.valid() {
+		return nil, ErrInvalidExportFormat
+	}
+
+	analyses, err := core.Queries.GetLatestCourseAnalyses(ctx, sqlgen.GetLatestCourseAnalysesParams{
+		UserID: userID,
+		Course: course,
+		Type:   AnalysisFlashcards,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(analyses) == 0 {
+		return nil, ErrNoFlashcards
+	}
+
+	decks := make([]anki.Deck, 0, len(analyses))
+	for _, analysis := range analyses {
+		deck, err := core.flashcardDeck(ctx, analysis)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, *deck)
+	}
+
+	return exportFlashcards(ctx, decks, format, course)
+}
+
+// INTERNAL
+
+// flashcardDeck reads a flashcard analysis into a deck named after its course and collection.
+// Notes are keyed by item, so exporting again after edits updates the cards already imported.
+func (core Core) flashcardDeck(ctx context.Context, analysis sqlgen.GetLatestCourseAnalysesRow) (*anki.Deck, error) {
+	items, err := core.analysisItems(ctx, analysis.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := &anki.Deck{
+		Key:   analysis.CollectionID.String(),
+		Name:  deckName(analysis.Course) + "::" + deckName(analysis.CollectionTitle),
+		Notes: make([]anki.Note, 0, len(items)),
+	}
+
+	var tags []string
+	if tag := anki.Tag(analysis.Course); tag != "" {
+		tags = []string{tag}
+	}
+
+	for _, item := range items {
+		var card struct {
+			Question string   `json:"question"`
+			Answer   string   `json:"answer"`
+			Sources  []Source `json:"sources"`
+		}
+		if err := json.Unmarshal(item.Content, &card); err != nil {
+			return nil, err
+		}
+
+		deck.Notes = append(deck.Notes, anki.Note{
+			GUID:    item.ID.String(),
+			Front:   anki.Field(card.Question),
+			Back:    anki.Field(card.Answer),
+			Sources: html.EscapeString(strings.Join(citations(card.Sources), "; ")),
+			Tags:    tags,
+		})
+	}
+
+	return deck, nil
+}
+
+func (format ExportFormat) valid() bool {
+	return format == ExportApkg || format == ExportCSV || format == ExportTSV
+}
+
+func exportFlashcards(ctx context.Context, decks []anki.Deck, format ExportFormat, name string) (*Export, error) {
+	export := &Export{
+		Filename: exportFilename(name) + "." + string(format),
+	}
+
+	var err error
+	switch format {
+	case ExportApkg:
+		export.ContentType = anki.MimeType
+		export.Data, err = anki.WritePackage(ctx, decks)
+	case ExportCSV:
+		export.ContentType = "text/csv; charset=utf-8"
+		export.Data, err = writeDelimitedFlashcards(decks, ',')
+	case ExportTSV:
+		export.ContentType = "text/tab-separated-values; charset=utf-8"
+		export.Data, err = writeDelimitedFlashcards(decks, '\t')
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// writeDelimitedFlashcards writes one note per row, headed by the file headers Anki reads on import
+// so the deck, tags and note ids are picked up without setting anything by hand
+func writeDelimitedFlashcards(decks []anki.Deck, separator rune) ([]byte, error) {
+	separatorName := "Comma"
+	if separator == '\t' {
+		separatorName = "Tab"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "#separator:%s\n", separatorName)
+	buf.WriteString("#html:true\n")
+	buf.WriteString("#notetype:Basic\n")
+	buf.WriteString("#guid column:1\n")
+	buf.WriteString("#deck column:4\n")
+	buf.WriteString("#tags column:5\n")
+	fmt.Fprintf(&buf, "#columns:%s\n", strings.Join([]string{"GUID", "Front", "Back", "Deck", "Tags"}, string(separator)))
+
+	w := csv.NewWriter(&buf)
+	w.Comma = separator
+	for _, deck := range decks {
+		for _, note := range deck.Notes {
+			if err := w.Write([]string{note.GUID, note.Front, note.Back, deck.Name, strings.Join(note.Tags, " ")}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// deckName keeps a course or collection name from nesting decks of its own
+func deckName(name string) string {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "::", ":"))
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+func exportFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		return "flashcards"
+	}
+	return name
+}
@@ -0,0 +1,614 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server/api/tools/features/anki"
+	"server/sqlc/sqlgen"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ImportFormat is a file format flashcards or quiz questions can be imported from
+type ImportFormat string
+
+const (
+	ImportApkg    ImportFormat = "apkg" // Anki package, flashcards only
+	ImportCSV     ImportFormat = "csv"
+	ImportTSV     ImportFormat = "tsv"
+	ImportQuizlet ImportFormat = "quizlet" // Quizlet's export: a term and its definition per line, flashcards only
+
+	MaxImportSize     = 64 << 20
+	MaxImportItems    = 5000
+	MaxImportProblems = 20 // Rows skipped beyond this are counted but not described
+)
+
+// Errors
+var (
+	ErrInvalidImportFormat error = errors.New("invalid import format")
+	ErrNotImportable       error = errors.New("only flashcards and quizzes can be imported")
+	ErrInvalidImport       error = errors.New("invalid import file")
+)
+
+// ImportResult tells what an import changed
+type ImportResult struct {
+	AnalysisID uuid.UUID // The collection's imported analysis of the kind
+
+	Created   int
+	Updated   int // Imported before, and changed in the file since
+	Unchanged int // Imported before as they are, or deleted by the user since
+	Skipped   int
+
+	Problems []string // Why rows were skipped, up to MaxImportProblems
+}
+
+// importedItem is an item read from a file, keyed by the id it has there
+type importedItem struct {
+	ExternalID string
+	Content    json.RawMessage
+}
+
+// importedFile is what an import file holds once read
+type importedFile struct {
+	Items    []importedItem
+	Skipped  int
+	Problems []string
+}
+
+// ImportItems adds the flashcards or quiz questions of a file to the collection's imported analysis of that kind,
+// where they are reviewed and quizzed on like generated ones. Importing the same file again changes nothing, and
+// importing an updated one updates the items it had before and adds the new ones. User edits are kept.
+func (core Core) ImportItems(
+	ctx context.Context,
+	userID uuid.UUID,
+	collectionID uuid.UUID,
+	kindName string,
+	format ImportFormat,
+	data []byte,
+) (*ImportResult, error) {
+
+	if kindName != AnalysisFlashcards && kindName != AnalysisQuiz {
+		return nil, ErrNotImportable
+	}
+	switch format {
+	case ImportApkg, ImportQuizlet:
+		if kindName != AnalysisFlashcards {
+			return nil, fmt.Errorf("%w: %s files hold flashcards only", ErrInvalidImportFormat, format)
+		}
+	case ImportCSV, ImportTSV:
+	default:
+		return nil, ErrInvalidImportFormat
+	}
+
+	kind, err := core.AnalysisKinds.Get(kindName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := core.Queries.GetCollection(ctx, sqlgen.GetCollectionParams{
+		UserID: userID,
+		ID:     collectionID,
+	}); errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCollectionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	file, err := readImportFile(ctx, kind, format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Items) > MaxImportItems {
+		return nil, fmt.Errorf("%w: more than %d items", ErrInvalidImport, MaxImportItems)
+	}
+
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+
+	// Two imports creating the collection's imported analysis at once would make two
+	if _, err := q.LockUserCollection(ctx, sqlgen.LockUserCollectionParams{
+		ID:     collectionID,
+		UserID: userID,
+	}); errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCollectionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	analysis, err := q.GetImportedAnalysis(ctx, sqlgen.GetImportedAnalysisParams{
+		CollectionID: collectionID,
+		Type:         kind.Name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Analyses hang off a snapshot. Imports have no content of their own, so theirs is empty.
+		snapshot, err := q.CreateCollectionSnapshot(ctx, sqlgen.CreateCollectionSnapshotParams{
+			CollectionID: collectionID,
+			Sources:      json.RawMessage(`[]`),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		analysis, err = q.CreateImportedAnalysis(ctx, sqlgen.CreateImportedAnalysisParams{
+			SnapshotID: snapshot.ID,
+			Type:       kind.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	position, err := q.NextAnalysisItemPosition(ctx, analysis.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		AnalysisID: analysis.ID,
+		Skipped:    file.Skipped,
+		Problems:   file.Problems,
+	}
+
+	for _, item := range file.Items {
+		created, err := q.UpsertImportedAnalysisItem(ctx, sqlgen.UpsertImportedAnalysisItemParams{
+			AnalysisID: analysis.ID,
+			ExternalID: sql.NullString{String: item.ExternalID, Valid: true},
+			Generated:  item.Content,
+			Position:   position,
+		})
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			result.Unchanged++
+		case err != nil:
+			return nil, err
+		case created:
+			result.Created++
+			position++
+		default:
+			result.Updated++
+		}
+	}
+
+	if err := q.RefreshAnalysisResult(ctx, analysis.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// INTERNAL
+
+// readImportFile reads the items of a file, skipping the rows that don't make a valid item of the kind
+func readImportFile(ctx context.Context, kind AnalysisKind, format ImportFormat, data []byte) (*importedFile, error) {
+	var rows []importRow
+	var err error
+
+	switch format {
+	case ImportApkg:
+		rows, err = apkgRows(ctx, data)
+	case ImportCSV:
+		rows, err = delimitedRows(data, ',')
+	case ImportTSV:
+		rows, err = delimitedRows(data, '\t')
+	case ImportQuizlet:
+		rows, err = quizletRows(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := &importedFile{}
+	skip := func(row importRow, reason string) {
+		file.Skipped++
+		if len(file.Problems) < MaxImportProblems {
+			file.Problems = append(file.Problems, fmt.Sprintf("%s: %s", row.Name, reason))
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, row := range rows {
+		var fields map[string]any
+		if kind.Name == AnalysisQuiz {
+			fields, err = row.quizQuestion()
+		} else {
+			fields, err = row.flashcard()
+		}
+		if err != nil {
+			skip(row, err.Error())
+			continue
+		}
+
+		externalID := row.ExternalID
+		if externalID == "" {
+			// Without an id in the file, a row is known by its question
+			sum := sha256.Sum256([]byte(strings.ToLower(strings.Join(strings.Fields(row.question()), " "))))
+			externalID = "text:" + hex.EncodeToString(sum[:16])
+		}
+		if seen[externalID] {
+			skip(row, "repeats an earlier row")
+			continue
+		}
+		seen[externalID] = true
+
+		content, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		content, err = normalizeAnalysisItem(kind, content, nil)
+		if err != nil {
+			skip(row, strings.TrimPrefix(err.Error(), ErrInvalidAnalysisItem.Error()+": "))
+			continue
+		}
+
+		file.Items = append(file.Items, importedItem{
+			ExternalID: externalID,
+			Content:    content,
+		})
+	}
+
+	return file, nil
+}
+
+// importRow is one row or note of an import file, with its cells named by column where the file names them
+type importRow struct {
+	Name       string // "row 3", "note 12"
+	ExternalID string // "guid:..." when the file carries ids
+	Cells      []string
+	Columns    []string // Lower case column names, when known
+}
+
+// Column names understood in CSV/TSV headers, in order of preference
+var (
+	questionColumns = []string{"question", "front", "term", "prompt", "q"}
+	answerColumns   = []string{"answer", "back", "definition", "correct", "correct answer", "correct_answer", "correct_index", "key", "a"}
+	optionsColumns  = []string{"options", "choices"}
+	metadataColumns = []string{"guid", "deck", "tags", "notetype", "sources"}
+)
+
+func (row importRow) column(names []string) (int, bool) {
+	for _, name := range names {
+		for i, column := range row.Columns {
+			if column == name && i < len(row.Cells) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// content returns the cells that aren't metadata such as the deck or tags
+func (row importRow) content() []int {
+	var indexes []int
+	for i := range row.Cells {
+		metadata := false
+		if i < len(row.Columns) {
+			for _, name := range metadataColumns {
+				metadata = metadata || row.Columns[i] == name
+			}
+		}
+		if !metadata {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (row importRow) question() string {
+	if i, ok := row.column(questionColumns); ok {
+		return row.Cells[i]
+	}
+	if content := row.content(); len(content) > 0 {
+		return row.Cells[content[0]]
+	}
+	return ""
+}
+
+func (row importRow) flashcard() (map[string]any, error) {
+	question := strings.TrimSpace(row.question())
+
+	var answer string
+	if i, ok := row.column(answerColumns); ok {
+		answer = row.Cells[i]
+	} else if content := row.content(); len(content) > 1 {
+		answer = row.Cells[content[1]]
+	}
+	answer = strings.TrimSpace(answer)
+
+	if question == "" || answer == "" {
+		return nil, errors.New("a flashcard needs a question and an answer")
+	}
+
+	return map[string]any{"question": question, "answer": answer}, nil
+}
+
+// quizQuestion reads a question, its options and the correct one. Options come from an "options" column split
+// on "|", or from columns named "option ..." or a single letter. Without a header the first cell is the question,
+// the last the answer and those between the options. The answer is a letter, a number counting from 1, or the
+// text of an option.
+func (row importRow) quizQuestion() (map[string]any, error) {
+	question := strings.TrimSpace(row.question())
+
+	var options []string
+	answer := ""
+	if len(row.Columns) > 0 {
+		if i, ok := row.column(optionsColumns); ok {
+			options = strings.Split(row.Cells[i], "|")
+		} else {
+			// Letter columns are options when there are at least "a" and "b", otherwise "a" is the answer
+			letters := row.hasColumn("a") && row.hasColumn("b")
+			for i, column := range row.Columns {
+				isLetter := letters && len(column) == 1 && column >= "a" && column <= "z"
+				if (strings.HasPrefix(column, "option") || isLetter) && i < len(row.Cells) {
+					options = append(options, row.Cells[i])
+				}
+			}
+		}
+		if i, ok := row.column(answerColumns); ok && !(row.Columns[i] == "a" && len(options) > 0) {
+			answer = row.Cells[i]
+		}
+	} else if content := row.content(); len(content) >= 4 {
+		for _, i := range content[1 : len(content)-1] {
+			options = append(options, row.Cells[i])
+		}
+		answer = row.Cells[content[len(content)-1]]
+	}
+
+	trimmed := options[:0]
+	for _, option := range options {
+		if option = strings.TrimSpace(option); option != "" {
+			trimmed = append(trimmed, option)
+		}
+	}
+	options = trimmed
+
+	if question == "" {
+		return nil, errors.New("a question is missing")
+	}
+	if len(options) < 2 {
+		return nil, errors.New("a quiz question needs at least two options")
+	}
+
+	correct, ok := correctOption(strings.TrimSpace(answer), options)
+	if !ok {
+		return nil, fmt.Errorf("the answer %q isn't one of the options", answer)
+	}
+
+	return map[string]any{"question": question, "options": options, "correct_index": correct}, nil
+}
+
+func (row importRow) hasColumn(name string) bool {
+	_, ok := row.column([]string{name})
+	return ok
+}
+
+func correctOption(answer string, options []string) (int, bool) {
+	for i, option := range options {
+		if strings.EqualFold(option, answer) {
+			return i, true
+		}
+	}
+
+	letter := strings.ToUpper(strings.TrimRight(answer, ".)"))
+	if len(letter) == 1 && letter[0] >= 'A' && int(letter[0]-'A') < len(options) {
+		return int(letter[0] - 'A'), true
+	}
+
+	if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(options) {
+		return number - 1, true
+	}
+
+	return 0, false
+}
+
+func apkgRows(ctx context.Context, data []byte) ([]importRow, error) {
+	notes, err := anki.ReadPackage(ctx, data)
+	if errors.Is(err, anki.ErrInvalidPackage) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, 0, len(notes))
+	for i, note := range notes {
+		rows = append(rows, importRow{
+			Name:       fmt.Sprintf("note %d", i+1),
+			ExternalID: "guid:" + note.GUID,
+			Cells:      []string{note.Front, note.Back},
+			Columns:    []string{"front", "back"},
+		})
+	}
+
+	return rows, nil
+}
+
+// delimitedRows reads a CSV or TSV file. The file headers Anki writes and reads ("#separator:Tab", "#columns:...",
+// "#guid column:1", ...) are understood, so Anki's exports and ours import with their ids. Otherwise a first row
+// that names the question and answer columns is taken as the header.
+func delimitedRows(data []byte, separator rune) ([]importRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 byte order mark
+
+	headers := map[string]string{}
+	for bytes.HasPrefix(data, []byte("#")) {
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+		data = rest
+		if key, value, ok := strings.Cut(strings.TrimSpace(string(line[1:])), ":"); ok {
+			headers[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+
+	if value, ok := headers["separator"]; ok {
+		separator = ankiSeparator(value, separator)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if separator != ',' {
+		reader.TrimLeadingSpace = false
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+	}
+
+	var columns []string
+	if value, ok := headers["columns"]; ok {
+		for _, column := range strings.Split(value, string(separator)) {
+			columns = append(columns, strings.ToLower(strings.TrimSpace(column)))
+		}
+	} else if len(records) > 0 && isHeaderRow(records[0]) {
+		for _, column := range records[0] {
+			columns = append(columns, strings.ToLower(strings.TrimSpace(column)))
+		}
+		records = records[1:]
+	}
+
+	// Anki numbers columns from 1
+	guidColumn := -1
+	if value, ok := headers["guid column"]; ok {
+		if number, err := strconv.Atoi(value); err == nil {
+			guidColumn = number - 1
+		}
+	} else {
+		for i, column := range columns {
+			if column == "guid" {
+				guidColumn = i
+			}
+		}
+	}
+	for _, name := range []string{"deck column", "tags column", "notetype column"} {
+		if number, err := strconv.Atoi(headers[name]); err == nil && number >= 1 {
+			for len(columns) < number {
+				columns = append(columns, "")
+			}
+			columns[number-1] = strings.TrimSuffix(name, " column")
+		}
+	}
+	if guidColumn >= 0 {
+		for len(columns) <= guidColumn {
+			columns = append(columns, "")
+		}
+		columns[guidColumn] = "guid"
+	}
+
+	html := strings.EqualFold(headers["html"], "true")
+
+	rows := make([]importRow, 0, len(records))
+	for i, record := range records {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // Blank line
+		}
+
+		row := importRow{
+			Name:    fmt.Sprintf("row %d", i+1),
+			Cells:   record,
+			Columns: columns,
+		}
+		if html {
+			for j, cell := range row.Cells {
+				row.Cells[j] = anki.PlainText(cell)
+			}
+		}
+		if guidColumn >= 0 && guidColumn < len(record) && strings.TrimSpace(record[guidColumn]) != "" {
+			row.ExternalID = "guid:" + strings.TrimSpace(record[guidColumn])
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// quizletRows reads Quizlet's export: a term, a tab (or a comma) and its definition on each line
+func quizletRows(data []byte) ([]importRow, error) {
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n")
+	if !utf8.ValidString(text) {
+		return nil, fmt.Errorf("%w: not UTF-8 text", ErrInvalidImport)
+	}
+
+	separator := "\t"
+	if !strings.Contains(text, "\t") {
+		separator = ","
+	}
+
+	var rows []importRow
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		term, definition, _ := strings.Cut(line, separator)
+		rows = append(rows, importRow{
+			Name:    fmt.Sprintf("line %d", i+1),
+			Cells:   []string{term, definition},
+			Columns: []string{"term", "definition"},
+		})
+	}
+
+	return rows, nil
+}
+
+// isHeaderRow reports whether a first row names the columns rather than holding a card: it names a question
+// column, or is made of column names only ("Q,A")
+func isHeaderRow(record []string) bool {
+	names := 0
+	for _, cell := range record {
+		cell = strings.ToLower(strings.TrimSpace(cell))
+		known := slices.Contains(questionColumns, cell) || slices.Contains(answerColumns, cell) ||
+			slices.Contains(optionsColumns, cell) || slices.Contains(metadataColumns, cell) ||
+			strings.HasPrefix(cell, "option") || len(cell) == 1 && cell >= "a" && cell <= "z"
+		if known && len(cell) > 1 && slices.Contains(questionColumns, cell) {
+			return true
+		}
+		if known {
+			names++
+		}
+	}
+	return names == len(record)
+}
+
+// ankiSeparator reads the value of Anki's #separator header
+func ankiSeparator(value string, fallback rune) rune {
+	switch strings.ToLower(value) {
+	case "comma":
+		return ','
+	case "semicolon":
+		return ';'
+	case "tab":
+		return '\t'
+	case "space":
+		return ' '
+	case "pipe":
+		return '|'
+	case "colon":
+		return ':'
+	}
+	if r, size := utf8.DecodeRuneInString(value); size == len(value) && r != utf8.RuneError {
+		return r
+	}
+	return fallback
+}
@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"server/api/logging"
+	"server/sqlc/sqlgen"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// How long past its timeout a running job waits before it counts as abandoned, so the worker running it
+// has time to record the timeout itself
+const staleAnalysisJobGrace = time.Minute
+
+// EnqueueAnalysis queues an analysis of a collection and wakes an idle worker to pick it up
+func (core Core) EnqueueAnalysis(
+	ctx context.Context,
+	userID uuid.UUID,
+	collectionID uuid.UUID,
+	request AnalysisRequest,
+) (*AnalysisJob, error) {
+
+	q := core.Queries
+
+	// Auth check
+	if _, err := q.GetCollection(ctx, sqlgen.GetCollectionParams{
+		UserID: userID,
+		ID:     collectionID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := core.AnalysisKinds.Get(request.Kind); err != nil {
+		return nil, err
+	}
+
+	parameters, err := encodeAnalysisParameters(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := core.checkQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Resolve the provider now so the job records which vendor it will use
+	provider, err := core.Services.LLM.Get(request.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := q.CreateAnalysisJob(ctx, sqlgen.CreateAnalysisJobParams{
+		UserID:       userID,
+		CollectionID: collectionID,
+		Type:         request.Kind,
+		Provider:     provider.Name(),
+		Parameters:   parameters,
+		Regenerate:   request.Regenerate,
+		Status:       sqlgen.AnalysisJobStatusQueued,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	core.wakeAnalysisWorker()
+
+	return analysisJobFromRow(row), nil
+}
+
+// GetAnalysisJob retrieves an analysis job by ID for a user
+func (core Core) GetAnalysisJob(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*AnalysisJob, error) {
+	row, err := core.Queries.GetAnalysisJob(ctx, sqlgen.GetAnalysisJobParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return analysisJobFromRow(row), nil
+}
+
+// StartAnalysisWorkers starts the worker pool, along with a sweeper that requeues jobs abandoned by a
+// crashed or restarted server, or fails them once they have used up their attempts. Workers stop when
+// ctx is cancelled.
+func (core Core) StartAnalysisWorkers(ctx context.Context) {
+	go core.staleAnalysisJobSweeper(ctx)
+
+	for range core.AnalysisWorkers {
+		go core.analysisWorker(ctx)
+	}
+}
+
+// INTERNAL
+
+// analysisWorker drains the queue, then sleeps until woken by an enqueue or the poll interval elapses
+func (core Core) analysisWorker(ctx context.Context) {
+	ticker := time.NewTicker(core.AnalysisJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			job, err := core.Queries.ClaimNextAnalysisJob(ctx)
+			if errors.Is(err, sql.ErrNoRows) {
+				break
+			}
+			if err != nil {
+				logging.Error(err, "failed to claim analysis job", nil)
+				break
+			}
+
+			jobCtx, cancel := context.WithTimeout(ctx, core.AnalysisJobTimeout)
+			if _, err := core.runAnalysisJob(jobCtx, job); err != nil {
+				logging.Error(err, "analysis job failed", map[string]interface{}{
+					"job_id":        job.ID,
+					"collection_id": job.CollectionID,
+				})
+			}
+			cancel()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-core.analysisJobWake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// staleAnalysisJobSweeper sweeps stale jobs at startup, then once per job timeout
+func (core Core) staleAnalysisJobSweeper(ctx context.Context) {
+	ticker := time.NewTicker(core.AnalysisJobTimeout)
+	defer ticker.Stop()
+
+	for {
+		core.sweepStaleAnalysisJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweepStaleAnalysisJobs fails the stale jobs out of attempts and requeues the rest. A running job's context
+// expires after the job timeout, so a job still running a grace period past it has been abandoned.
+func (core Core) sweepStaleAnalysisJobs(ctx context.Context) {
+	staleBefore := time.Now().Add(-core.AnalysisJobTimeout - staleAnalysisJobGrace)
+
+	failed, err := core.Queries.FailAbandonedAnalysisJobs(ctx, sqlgen.FailAbandonedAnalysisJobsParams{
+		StaleBefore: staleBefore,
+		MaxAttempts: int32(core.AnalysisJobMaxAttempts),
+	})
+	if err != nil {
+		logging.Error(err, "failed to fail abandoned analysis jobs", nil)
+	} else if failed > 0 {
+		logging.Warn("failed analysis jobs out of attempts", map[string]interface{}{
+			"count":        failed,
+			"max_attempts": core.AnalysisJobMaxAttempts,
+		})
+	}
+
+	requeued, err := core.Queries.RequeueStaleAnalysisJobs(ctx, sqlgen.RequeueStaleAnalysisJobsParams{
+		StaleBefore: staleBefore,
+		MaxAttempts: int32(core.AnalysisJobMaxAttempts),
+	})
+	if err != nil {
+		logging.Error(err, "failed to requeue stale analysis jobs", nil)
+	} else if requeued > 0 {
+		logging.Info("requeued stale analysis jobs", map[string]interface{}{
+			"count": requeued,
+		})
+		core.wakeAnalysisWorker()
+	}
+}
+
+// runAnalysisJob runs a claimed job to completion and records its outcome
+func (core Core) runAnalysisJob(ctx context.Context, job sqlgen.AnalysisJob) (*CollectionAnalysis, error) {
+	// Outcomes are recorded even if the request that started the job has gone away
+	recordCtx := context.WithoutCancel(ctx)
+
+	analysis, err := core.analyzeCollection(ctx, job)
+	if err != nil {
+		if ferr := core.Queries.FailAnalysisJob(recordCtx, sqlgen.FailAnalysisJobParams{
+			ID:    job.ID,
+			Error: err.Error(),
+		}); ferr != nil {
+			logging.Error(ferr, "failed to record analysis job failure", map[string]interface{}{
+				"job_id": job.ID,
+			})
+		}
+		return nil, err
+	}
+
+	if err := core.Queries.CompleteAnalysisJob(recordCtx, sqlgen.CompleteAnalysisJobParams{
+		ID:         job.ID,
+		AnalysisID: analysis.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return analysis, nil
+}
+
+// wakeAnalysisWorker nudges one idle worker without blocking when all of them are busy
+func (core Core) wakeAnalysisWorker() {
+	select {
+	case core.analysisJobWake <- struct{}{}:
+	default:
+	}
+}
+
+func analysisJobFromRow(row sqlgen.AnalysisJob) *AnalysisJob {
+	job := &AnalysisJob{
+		ID:           row.ID,
+		CollectionID: row.CollectionID,
+		Type:         row.Type,
+		Status:       row.Status,
+		Provider:     row.Provider,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+
+	if row.AnalysisID.Valid {
+		job.AnalysisID = &row.AnalysisID.UUID
+	}
+	if row.Error.Valid {
+		job.Error = &row.Error.String
+	}
+	if row.StartedAt.Valid {
+		job.StartedAt = &row.StartedAt.Time
+	}
+	if row.FinishedAt.Valid {
+		job.FinishedAt = &row.FinishedAt.Time
+	}
+
+	return job
+}
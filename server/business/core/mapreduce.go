@@ -0,0 +1,275 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"server/api/tools/features/llm"
+	"server/api/tools/internaltools/jsonschema"
+	"strings"
+	"unicode"
+)
+
+// analyzeContent runs the analysis in a single call when the content fits the model's context.
+// Larger content is split into chunks that are analyzed separately, then merged into one result.
+func (core Core) analyzeContent(
+	ctx context.Context,
+	provider llm.Provider,
+	content string,
+	prompt analysisPrompt,
+) (json.RawMessage, error) {
+
+	if estimateTokens(content) <= core.AnalysisContextTokens {
+		return core.runAnalysis(ctx, provider, content, prompt)
+	}
+
+	// Map
+	chunks := chunkContent(content, core.AnalysisChunkTokens)
+	chunkPrompt := prompt
+	chunkPrompt.Parameters = prompt.Parameters.forChunk(len(chunks))
+	partials := make([]json.RawMessage, 0, len(chunks))
+	for i, chunk := range chunks {
+		result, err := core.runAnalysis(ctx, provider, analysisChunkInput(i, len(chunks), chunk), chunkPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		partials = append(partials, result)
+
+		partial, err := HideQuizAnswers(prompt.Kind.Name, result)
+		if err != nil {
+			return nil, err
+		}
+		emitAnalysisEvent(ctx, AnalysisEventPartialResult, PartialResultEvent{
+			Chunk:  i + 1,
+			Total:  len(chunks),
+			Result: partial,
+		})
+	}
+
+	// Reduce
+	return core.reduceAnalyses(ctx, provider, prompt, partials)
+}
+
+// reduceAnalyses merges per-chunk results into a single result matching the kind's schema
+func (core Core) reduceAnalyses(
+	ctx context.Context,
+	provider llm.Provider,
+	prompt analysisPrompt,
+	partials []json.RawMessage,
+) (json.RawMessage, error) {
+
+	kind := prompt.Kind
+	if kind.Merge == nil {
+		return core.condenseAnalyses(ctx, provider, prompt, partials)
+	}
+
+	merged, err := kind.Merge(partials)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateAnalysis(kind, merged); len(violations) > 0 {
+		return nil, &jsonschema.ValidationError{Violations: violations}
+	}
+
+	return merged, nil
+}
+
+// condenseAnalyses has the model rewrite the kind's CondenseField across every partial result,
+// for results like summaries that can't be concatenated
+func (core Core) condenseAnalyses(
+	ctx context.Context,
+	provider llm.Provider,
+	prompt analysisPrompt,
+	partials []json.RawMessage,
+) (json.RawMessage, error) {
+
+	texts := make([]string, 0, len(partials))
+	for _, partial := range partials {
+		var part map[string]any
+		if err := json.Unmarshal(partial, &part); err != nil {
+			return nil, err
+		}
+		text, _ := part[prompt.Kind.CondenseField].(string)
+		texts = append(texts, text)
+	}
+
+	condensed, err := core.analyzeContent(ctx, provider, analysisReduceInput(texts), prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The condensing call never saw the [S#] labels, so the result cites everything its parts cited
+	labels, err := unionSources(partials)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(condensed, &result); err != nil {
+		return nil, err
+	}
+	result["sources"] = labels
+
+	return json.Marshal(result)
+}
+
+// mergeItems concatenates array results, dropping items whose key field repeats an earlier one
+func mergeItems(partials []json.RawMessage, keyField string) (json.RawMessage, error) {
+	seen := map[string]bool{}
+	merged := []map[string]any{}
+
+	for _, partial := range partials {
+		var items []map[string]any
+		if err := json.Unmarshal(partial, &items); err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			key, _ := item[keyField].(string)
+			key = normalizeKey(key)
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, item)
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// normalizeKey folds case, punctuation and whitespace so near-identical items compare equal
+func normalizeKey(key string) string {
+	var normalized strings.Builder
+	for _, word := range strings.Fields(strings.ToLower(key)) {
+		word = strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if word == "" {
+			continue
+		}
+		if normalized.Len() > 0 {
+			normalized.WriteByte(' ')
+		}
+		normalized.WriteString(word)
+	}
+	return normalized.String()
+}
+
+// CHUNKING
+
+// estimateTokens approximates a token count at roughly four characters per token
+func estimateTokens(text string) int {
+	return (len([]rune(text)) + 3) / 4
+}
+
+// sourceLabelPattern matches the [S#] line collectionContent opens each page with
+var sourceLabelPattern = regexp.MustCompile(`(?:^|\n\n)(\[S\d+\]\n)`)
+
+// chunkContent splits content into pieces of at most maxTokens, breaking between
+// pages first, then on paragraphs, then lines, and only cutting mid-line as a last
+// resort. A page split across chunks has its [S#] label repeated at the head of
+// every chunk it continues into, so passages from anywhere in it can still be cited.
+func chunkContent(content string, maxTokens int) []string {
+	maxTokens = max(maxTokens, 1)
+
+	var chunks []string
+	var current strings.Builder
+	label := "" // Label line of the page being added
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			chunks = append(chunks, text)
+		}
+		current.Reset()
+	}
+
+	add := func(piece string, separator string) {
+		if current.Len() > 0 && estimateTokens(current.String()+separator+piece) > maxTokens {
+			flush()
+		}
+		if current.Len() == 0 && !strings.HasPrefix(piece, label) {
+			current.WriteString(label)
+		} else if current.Len() > 0 {
+			current.WriteString(separator)
+		}
+		current.WriteString(piece)
+	}
+
+	for _, page := range splitLabeledPages(content) {
+		label = page.label
+		if estimateTokens(label+page.text) <= maxTokens {
+			add(label+page.text, "\n\n")
+			continue
+		}
+
+		// The page is split, so every piece leaves room for its label
+		pieceTokens := max(maxTokens-estimateTokens(label), 1)
+		first := true
+		addPiece := func(piece string, separator string) {
+			if first {
+				piece, separator, first = label+piece, "\n\n", false
+			}
+			add(piece, separator)
+		}
+
+		for _, paragraph := range strings.Split(page.text, "\n\n") {
+			if estimateTokens(paragraph) <= pieceTokens {
+				addPiece(paragraph, "\n\n")
+				continue
+			}
+
+			for _, line := range strings.Split(paragraph, "\n") {
+				if estimateTokens(line) <= pieceTokens {
+					addPiece(line, "\n")
+					continue
+				}
+
+				runes := []rune(line)
+				window := pieceTokens * 4
+				for start := 0; start < len(runes); start += window {
+					end := min(start+window, len(runes))
+					addPiece(string(runes[start:end]), "")
+				}
+			}
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+type labeledPage struct {
+	label string // The page's label line, empty for text before the first label
+	text  string
+}
+
+// splitLabeledPages splits content at the label lines opening each page. Content
+// without labels, like the partial results being condensed, is a single page.
+func splitLabeledPages(content string) []labeledPage {
+	var pages []labeledPage
+
+	matches := sourceLabelPattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 || matches[0][0] > 0 {
+		end := len(content)
+		if len(matches) > 0 {
+			end = matches[0][0]
+		}
+		pages = append(pages, labeledPage{text: content[:end]})
+	}
+
+	for i, match := range matches {
+		end := len(content)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		pages = append(pages, labeledPage{
+			label: content[match[2]:match[3]],
+			text:  strings.TrimRight(content[match[3]:end], "\n"),
+		})
+	}
+
+	return pages
+}
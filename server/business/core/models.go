@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/json"
+	"server/api/tools/features/llm"
 	"server/sqlc/sqlgen"
 	"time"
 
@@ -23,25 +24,89 @@ type Document struct {
 	S3Location   string
 }
 
-type AnalysisType string
-
-const (
-	AnalysisSummary     AnalysisType = "summary"
-	AnalysisFlashcards  AnalysisType = "flashcards"
-	AnalysisQuiz        AnalysisType = "quiz"
-	AnalysisDeepSummary AnalysisType = "deep_summary"
+const ( // Built-in analysis kinds
+	AnalysisSummary      = "summary"
+	AnalysisFlashcards   = "flashcards"
+	AnalysisQuiz         = "quiz"
+	AnalysisDeepSummary  = "deep_summary"
+	AnalysisGlossary     = "glossary"
+	AnalysisTimeline     = "timeline"
+	AnalysisConceptMap   = "concept_map"
+	AnalysisFreeResponse = "free_response"
 )
 
+// AnalysisRequest describes an analysis a user asked for
+type AnalysisRequest struct {
+	Kind string // Name of a registered AnalysisKind
+
+	// Provider overrides the deployment's default LLM provider when set
+	Provider string
+
+	// Parameters tune the result. Zero values leave the choice to the model.
+	Parameters AnalysisParameters
+
+	// Regenerate always calls the model, even when an identical analysis already exists
+	Regenerate bool
+}
+
+// AnalysisParameters tune what an analysis produces
+type AnalysisParameters struct {
+	Count       int      `json:"count,omitempty"`      // Number of items (flashcards, questions, ...)
+	Difficulty  string   `json:"difficulty,omitempty"` // One of the Difficulty constants
+	Audience    string   `json:"audience,omitempty"`   // One of the Audience constants
+	Length      string   `json:"length,omitempty"`     // One of the Length constants
+	FocusTopics []string `json:"focusTopics,omitempty"`
+}
+
 type CollectionAnalysis struct {
-	ID        uuid.UUID           `json:"id"`
-	Type      sqlgen.AnalysisType `json:"type"`
-	Result    json.RawMessage     `json:"result"`
-	CreatedAt time.Time           `json:"createdAt"`
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	Result    json.RawMessage `json:"result"`
+	CreatedAt time.Time       `json:"createdAt"`
+
+	// Parameters the analysis was requested with, so results can be reproduced and compared
+	Parameters AnalysisParameters `json:"parameters"`
+
+	// TemplateID is the course prompt template version that produced the analysis, nil for the default
+	TemplateID *uuid.UUID `json:"templateId,omitempty"`
+
+	// Usage is what producing the analysis cost. A reused analysis reports its original cost.
+	Usage llm.Usage `json:"-"`
+
+	// Reused is set when an earlier analysis of identical content was returned instead of a new one
+	Reused bool `json:"reused"`
+
+	// Imported is set for the analysis holding a collection's imported flashcards or quiz questions
+	Imported bool `json:"imported"`
+}
+
+type AnalysisJob struct {
+	ID           uuid.UUID
+	CollectionID uuid.UUID
+	Type         string
+	Status       sqlgen.AnalysisJobStatus
+	Provider     string
+	AnalysisID   *uuid.UUID
+	Error        *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
 }
 
 type CollectionSnapshot struct {
 	ID              uuid.UUID
 	CombinedContent string
+	ContentHash     string
+	Sources         []SourceAnchor
+}
+
+// ExtractedPage is the text of one page of a document. Images are a single page.
+type ExtractedPage struct {
+	Page    int
+	Method  sqlgen.ExtractionMethod
+	Content string
+	Usage   llm.Usage // Zero for pages read without a model
 }
 
 // Analysis Types
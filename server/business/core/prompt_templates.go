@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/sqlc/sqlgen"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MaxPromptTemplateRunes = 20000
+
+// Errors
+var (
+	ErrInvalidPromptTemplate error = errors.New("invalid prompt template")
+	ErrCourseNotFound        error = errors.New("course not found")
+)
+
+// PromptTemplate is one version of a course's instructions for an analysis kind
+type PromptTemplate struct {
+	ID        uuid.UUID
+	Course    string
+	Kind      string
+	Version   int
+	Body      string
+	CreatedAt time.Time
+	RetiredAt *time.Time
+
+	// Active is set on the version analyses of this course and kind currently use
+	Active bool
+}
+
+// PromptTemplateData is what a template can reference, e.g. {{.Course}} or {{.Schema}}
+type PromptTemplateData struct {
+	Course     string // Course name
+	Collection string // Title of the collection being analyzed
+	Kind       string // Analysis kind name
+	Task       string // The kind's built-in task description
+	Schema     string // JSON schema the result must follow. Templates must include it.
+	Default    string // The complete default instructions, for templates that only add to them
+}
+
+// SavePromptTemplate validates a template and stores it as the newest version for the course and kind
+func (core Core) SavePromptTemplate(
+	ctx context.Context,
+	userID uuid.UUID,
+	course string,
+	kind string,
+	body string,
+) (*PromptTemplate, error) {
+
+	q := core.Queries
+
+	exists, err := q.CourseExists(ctx, sqlgen.CourseExistsParams{
+		Name:   course,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCourseNotFound
+	}
+
+	analysisKind, err := core.AnalysisKinds.Get(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePromptTemplate(body, analysisKind); err != nil {
+		return nil, err
+	}
+
+	row, err := q.CreatePromptTemplate(ctx, sqlgen.CreatePromptTemplateParams{
+		CreatorID: userID,
+		Course:    course,
+		Kind:      kind,
+		Body:      body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := promptTemplateFromRow(row)
+	prompt.Active = true
+	return prompt, nil
+}
+
+// GetPromptTemplates lists every version of a course's templates, newest first within each kind
+func (core Core) GetPromptTemplates(ctx context.Context, userID uuid.UUID, course string) ([]PromptTemplate, error) {
+	rows, err := core.Queries.GetPromptTemplates(ctx, sqlgen.GetPromptTemplatesParams{
+		CreatorID: userID,
+		Course:    course,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]PromptTemplate, 0, len(rows))
+	activeKinds := map[string]bool{}
+	for _, row := range rows {
+		prompt := promptTemplateFromRow(row)
+
+		// Rows are newest first, so the first unretired version of each kind is the active one
+		if prompt.RetiredAt == nil && !activeKinds[prompt.Kind] {
+			prompt.Active = true
+			activeKinds[prompt.Kind] = true
+		}
+
+		templates = append(templates, *prompt)
+	}
+
+	return templates, nil
+}
+
+// RetirePromptTemplates returns a course's analyses of a kind to the default instructions.
+// Earlier versions are kept so the analyses they produced still show where they came from.
+func (core Core) RetirePromptTemplates(ctx context.Context, userID uuid.UUID, course string, kind string) error {
+	_, err := core.Queries.RetirePromptTemplates(ctx, sqlgen.RetirePromptTemplatesParams{
+		CreatorID: userID,
+		Course:    course,
+		Kind:      kind,
+	})
+	return err
+}
+
+// INTERNAL
+
+// activePromptTemplate finds the template analyses of a kind in the course should use, or nil for the default
+func (core Core) activePromptTemplate(
+	ctx context.Context,
+	userID uuid.UUID,
+	course string,
+	kind string,
+) (*PromptTemplate, error) {
+
+	row, err := core.Queries.GetActivePromptTemplate(ctx, sqlgen.GetActivePromptTemplateParams{
+		CreatorID: userID,
+		Course:    course,
+		Kind:      kind,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := promptTemplateFromRow(row)
+	prompt.Active = true
+	return prompt, nil
+}
+
+// parsePromptTemplate compiles a template body
+func parsePromptTemplate(body string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPromptTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// renderPromptTemplate fills a template in. Unknown fields are an error rather than silently blank.
+func renderPromptTemplate(body string, data PromptTemplateData) (string, error) {
+	tmpl, err := parsePromptTemplate(body)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPromptTemplate, err)
+	}
+
+	return rendered.String(), nil
+}
+
+// validatePromptTemplate renders a template with sample data and checks the schema made it into the output,
+// either through {{.Schema}} or {{.Default}}
+func validatePromptTemplate(body string, kind AnalysisKind) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%w: template is empty", ErrInvalidPromptTemplate)
+	}
+	if len([]rune(body)) > MaxPromptTemplateRunes {
+		return fmt.Errorf("%w: template is longer than %d characters", ErrInvalidPromptTemplate, MaxPromptTemplateRunes)
+	}
+
+	// Stands in for the schema so it can be found in the output
+	const schemaMarker = "\x00schema\x00"
+
+	rendered, err := renderPromptTemplate(body, PromptTemplateData{
+		Course:     "Course",
+		Collection: "Collection",
+		Kind:       kind.Name,
+		Task:       kind.Task,
+		Schema:     schemaMarker,
+		Default:    defaultAnalysisInstructions(kind.Task, schemaMarker),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(rendered, schemaMarker) {
+		return fmt.Errorf("%w: template must include {{.Schema}} or {{.Default}}", ErrInvalidPromptTemplate)
+	}
+
+	return nil
+}
+
+func promptTemplateFromRow(row sqlgen.PromptTemplate) *PromptTemplate {
+	prompt := &PromptTemplate{
+		ID:        row.ID,
+		Course:    row.Course,
+		Kind:      row.Kind,
+		Version:   int(row.Version),
+		Body:      row.Body,
+		CreatedAt: row.CreatedAt,
+	}
+
+	if row.RetiredAt.Valid {
+		prompt.RetiredAt = &row.RetiredAt.Time
+	}
+
+	return prompt
+}
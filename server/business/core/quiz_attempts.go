@@ -0,0 +1,403 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server/sqlc/sqlgen"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrNotQuiz              error = errors.New("only quiz analyses can be attempted")
+	ErrQuizAttemptNotFound  error = errors.New("quiz attempt not found")
+	ErrQuizAttemptSubmitted error = errors.New("quiz attempt already submitted")
+	ErrInvalidQuizAnswers   error = errors.New("invalid quiz answers")
+)
+
+// QuizQuestion is a quiz question as served to the user taking it, without the answer
+type QuizQuestion struct {
+	Index    int
+	Question string
+	Options  []string
+}
+
+// QuizAnswer is the user's answer to one question
+type QuizAnswer struct {
+	Index    int
+	Selected *int // nil when skipped
+	Time     time.Duration
+}
+
+// QuizAnswerResult is how one question of a submitted attempt was graded
+type QuizAnswerResult struct {
+	Index        int
+	Question     string
+	Selected     *int
+	CorrectIndex int
+	Correct      bool
+	Time         time.Duration
+}
+
+type QuizAttempt struct {
+	ID         uuid.UUID
+	AnalysisID uuid.UUID
+
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+
+	StartedAt   time.Time
+	SubmittedAt *time.Time // nil while in progress
+
+	// Set once submitted
+	Score    int
+	Total    int
+	Duration time.Duration
+
+	// Questions are set while an attempt is in progress, Results once it has been submitted
+	Questions []QuizQuestion
+	Results   []QuizAnswerResult
+}
+
+// quizItem is one question of a quiz, as stored in analysis items and in the attempts it was served in
+type quizItem struct {
+	Question     string   `json:"question"`
+	Options      []string `json:"options"`
+	CorrectIndex int      `json:"correct_index"`
+}
+
+// StartQuizAttempt opens an attempt at a quiz analysis and returns its questions without the answers.
+// The questions are stored with the attempt, which is graded against them even if the quiz is edited meanwhile.
+func (core Core) StartQuizAttempt(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) (*QuizAttempt, error) {
+	analysis, err := core.Queries.GetUserAnalysis(ctx, sqlgen.GetUserAnalysisParams{
+		ID:     analysisID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnalysisNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if analysis.Type != AnalysisQuiz {
+		return nil, ErrNotQuiz
+	}
+
+	items, err := core.quizItems(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := core.Queries.CreateQuizAttempt(ctx, sqlgen.CreateQuizAttemptParams{
+		UserID:     userID,
+		AnalysisID: analysisID,
+		Questions:  questions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GetQuizAttempt(ctx, userID, created.ID)
+}
+
+// SubmitQuizAttempt grades an attempt and stores the result. Questions without an answer count as wrong.
+func (core Core) SubmitQuizAttempt(
+	ctx context.Context,
+	userID uuid.UUID,
+	attemptID uuid.UUID,
+	answers []QuizAnswer,
+) (*QuizAttempt, error) {
+
+	row, err := core.Queries.GetQuizAttempt(ctx, sqlgen.GetQuizAttemptParams{
+		ID:     attemptID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrQuizAttemptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if row.SubmittedAt.Valid {
+		return nil, ErrQuizAttemptSubmitted
+	}
+
+	var items []quizItem
+	if err := json.Unmarshal(row.Questions, &items); err != nil {
+		return nil, err
+	}
+
+	results, err := gradeQuiz(items, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	score := 0
+	for _, result := range results {
+		if result.Correct {
+			score++
+		}
+	}
+
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+
+	// Guards against the same attempt being submitted twice at once
+	now := time.Now()
+	completed, err := q.CompleteQuizAttempt(ctx, sqlgen.CompleteQuizAttemptParams{
+		ID:          attemptID,
+		SubmittedAt: sql.NullTime{Time: now, Valid: true},
+		Score:       sql.NullInt32{Int32: int32(score), Valid: true},
+		Total:       sql.NullInt32{Int32: int32(len(items)), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if completed == 0 {
+		return nil, ErrQuizAttemptSubmitted
+	}
+
+	for _, result := range results {
+		var selected sql.NullInt32
+		if result.Selected != nil {
+			selected = sql.NullInt32{Int32: int32(*result.Selected), Valid: true}
+		}
+
+		if err := q.CreateQuizAttemptAnswer(ctx, sqlgen.CreateQuizAttemptAnswerParams{
+			AttemptID:     attemptID,
+			QuestionIndex: int32(result.Index),
+			Question:      result.Question,
+			SelectedIndex: selected,
+			CorrectIndex:  int32(result.CorrectIndex),
+			Correct:       result.Correct,
+			TimeMs:        result.Time.Milliseconds(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	row.SubmittedAt = sql.NullTime{Time: now, Valid: true}
+	row.Score = sql.NullInt32{Int32: int32(score), Valid: true}
+	row.Total = sql.NullInt32{Int32: int32(len(items)), Valid: true}
+
+	attempt := quizAttemptFromRow(row)
+	attempt.Results = results
+	return attempt, nil
+}
+
+// GetQuizAttempt returns an attempt: its questions, without the answers, while in progress, and how each
+// question was graded once submitted
+func (core Core) GetQuizAttempt(ctx context.Context, userID uuid.UUID, attemptID uuid.UUID) (*QuizAttempt, error) {
+	row, err := core.Queries.GetQuizAttempt(ctx, sqlgen.GetQuizAttemptParams{
+		ID:     attemptID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrQuizAttemptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := quizAttemptFromRow(row)
+	if attempt.SubmittedAt == nil {
+		var items []quizItem
+		if err := json.Unmarshal(row.Questions, &items); err != nil {
+			return nil, err
+		}
+		attempt.Questions = quizQuestions(items)
+		return attempt, nil
+	}
+
+	answers, err := core.Queries.GetQuizAttemptAnswers(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt.Results = make([]QuizAnswerResult, 0, len(answers))
+	for _, answer := range answers {
+		result := QuizAnswerResult{
+			Index:        int(answer.QuestionIndex),
+			Question:     answer.Question,
+			CorrectIndex: int(answer.CorrectIndex),
+			Correct:      answer.Correct,
+			Time:         time.Duration(answer.TimeMs) * time.Millisecond,
+		}
+		if answer.SelectedIndex.Valid {
+			selected := int(answer.SelectedIndex.Int32)
+			result.Selected = &selected
+		}
+		attempt.Results = append(attempt.Results, result)
+	}
+
+	return attempt, nil
+}
+
+// GetQuizAttemptsByAnalysis lists the user's attempts at a quiz, newest first
+func (core Core) GetQuizAttemptsByAnalysis(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) ([]QuizAttempt, error) {
+	rows, err := core.Queries.GetQuizAttemptsByAnalysis(ctx, sqlgen.GetQuizAttemptsByAnalysisParams{
+		UserID:     userID,
+		AnalysisID: analysisID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]QuizAttempt, 0, len(rows))
+	for _, row := range rows {
+		attempts = append(attempts, *quizAttemptFromRow(sqlgen.GetQuizAttemptRow(row)))
+	}
+
+	return attempts, nil
+}
+
+// GetQuizAttemptsByCourse lists the user's attempts at every quiz in a course, newest first
+func (core Core) GetQuizAttemptsByCourse(ctx context.Context, userID uuid.UUID, course string) ([]QuizAttempt, error) {
+	rows, err := core.Queries.GetQuizAttemptsByCourse(ctx, sqlgen.GetQuizAttemptsByCourseParams{
+		UserID: userID,
+		Course: course,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]QuizAttempt, 0, len(rows))
+	for _, row := range rows {
+		attempts = append(attempts, *quizAttemptFromRow(sqlgen.GetQuizAttemptRow(row)))
+	}
+
+	return attempts, nil
+}
+
+// HideQuizAnswers removes the correct answers from a quiz analysis' result where it is served for taking,
+// as they are only revealed in the results of a submitted attempt. Content of other kinds is returned as is.
+func HideQuizAnswers(analysisType string, content json.RawMessage) (json.RawMessage, error) {
+	if analysisType != AnalysisQuiz || isJSONNull(content) {
+		return content, nil
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		delete(item, "correct_index")
+	}
+	return json.Marshal(items)
+}
+
+// INTERNAL
+
+// quizQuestions are a quiz's questions as served to the user taking it
+func quizQuestions(items []quizItem) []QuizQuestion {
+	questions := make([]QuizQuestion, 0, len(items))
+	for i, item := range items {
+		questions = append(questions, QuizQuestion{
+			Index:    i,
+			Question: item.Question,
+			Options:  item.Options,
+		})
+	}
+	return questions
+}
+
+// quizItems reads the current questions of a quiz analysis, edits included
+func (core Core) quizItems(ctx context.Context, analysisID uuid.UUID) ([]quizItem, error) {
+	analysisItems, err := core.analysisItems(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]quizItem, 0, len(analysisItems))
+	for _, analysisItem := range analysisItems {
+		var item quizItem
+		if err := json.Unmarshal(analysisItem.Content, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// gradeQuiz marks every question against the submitted answers
+func gradeQuiz(items []quizItem, answers []QuizAnswer) ([]QuizAnswerResult, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidQuizAnswers, fmt.Sprintf(format, args...))
+	}
+
+	byIndex := make(map[int]QuizAnswer, len(answers))
+	for _, answer := range answers {
+		if answer.Index < 0 || answer.Index >= len(items) {
+			return nil, invalid("question %d doesn't exist", answer.Index)
+		}
+		if _, ok := byIndex[answer.Index]; ok {
+			return nil, invalid("question %d is answered more than once", answer.Index)
+		}
+		if answer.Selected != nil && (*answer.Selected < 0 || *answer.Selected >= len(items[answer.Index].Options)) {
+			return nil, invalid("question %d has no option %d", answer.Index, *answer.Selected)
+		}
+		if answer.Time < 0 {
+			return nil, invalid("question %d has a negative time", answer.Index)
+		}
+		byIndex[answer.Index] = answer
+	}
+
+	results := make([]QuizAnswerResult, 0, len(items))
+	for i, item := range items {
+		answer := byIndex[i]
+		results = append(results, QuizAnswerResult{
+			Index:        i,
+			Question:     item.Question,
+			Selected:     answer.Selected,
+			CorrectIndex: item.CorrectIndex,
+			Correct:      answer.Selected != nil && *answer.Selected == item.CorrectIndex,
+			Time:         answer.Time,
+		})
+	}
+
+	return results, nil
+}
+
+// quizAttemptFromRow reads an attempt joined with its collection. The attempt queries select the same
+// columns, so their rows convert to GetQuizAttemptRow.
+func quizAttemptFromRow(row sqlgen.GetQuizAttemptRow) *QuizAttempt {
+	attempt := &QuizAttempt{
+		ID:              row.ID,
+		AnalysisID:      row.AnalysisID,
+		CollectionID:    row.CollectionID,
+		CollectionTitle: row.CollectionTitle,
+		Course:          row.Course,
+		StartedAt:       row.StartedAt,
+		Score:           int(row.Score.Int32),
+		Total:           int(row.Total.Int32),
+	}
+
+	if row.SubmittedAt.Valid {
+		attempt.SubmittedAt = &row.SubmittedAt.Time
+		attempt.Duration = row.SubmittedAt.Time.Sub(row.StartedAt)
+	}
+
+	return attempt
+}
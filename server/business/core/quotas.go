@@ -0,0 +1,236 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/api/logging"
+	"server/api/tools/features/llm"
+	"server/sqlc/sqlgen"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const ( // Quota periods
+	QuotaPeriodDay   = "day"
+	QuotaPeriodMonth = "month"
+)
+
+const ( // Quota metrics
+	QuotaMetricTokens   = "tokens"
+	QuotaMetricRequests = "requests"
+)
+
+// QuotaLimits caps a user's model consumption per UTC day and calendar month. Zero leaves a limit off.
+type QuotaLimits struct {
+	DailyTokens     int64
+	MonthlyTokens   int64
+	DailyRequests   int64
+	MonthlyRequests int64
+}
+
+// QuotaExceededError is returned when a user has used up a quota and must wait for it to reset
+type QuotaExceededError struct {
+	Period   string // One of the QuotaPeriod constants
+	Metric   string // One of the QuotaMetric constants
+	Limit    int64
+	Used     int64
+	ResetsAt time.Time
+}
+
+func (err *QuotaExceededError) Error() string {
+	return fmt.Sprintf(
+		"%s quota exceeded: %d of %d %s used, resets at %s",
+		err.Period,
+		err.Used,
+		err.Limit,
+		err.Metric,
+		err.ResetsAt.Format(time.RFC3339),
+	)
+}
+
+// UsageTotals is consumption over some period. Tokens count input and output together.
+type UsageTotals struct {
+	Requests int64
+	Tokens   int64
+}
+
+// UsagePeriod is a user's consumption and limits over one quota period
+type UsagePeriod struct {
+	Period   string
+	Start    time.Time
+	ResetsAt time.Time
+
+	Used       UsageTotals
+	Extraction UsageTotals
+	Analysis   UsageTotals
+	Embedding  UsageTotals
+
+	TokenLimit   int64 // 0 when unlimited
+	RequestLimit int64 // 0 when unlimited
+}
+
+type UsageSummary struct {
+	Day   UsagePeriod
+	Month UsagePeriod
+}
+
+// GetUsage reports a user's consumption against their quotas for the current day and month
+func (core Core) GetUsage(ctx context.Context, userID uuid.UUID) (*UsageSummary, error) {
+	limits, err := core.quotaLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	day, err := core.usageSince(ctx, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	day.Period = QuotaPeriodDay
+	day.ResetsAt = dayStart.AddDate(0, 0, 1)
+	day.TokenLimit = limits.DailyTokens
+	day.RequestLimit = limits.DailyRequests
+
+	month, err := core.usageSince(ctx, userID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+	month.Period = QuotaPeriodMonth
+	month.ResetsAt = monthStart.AddDate(0, 1, 0)
+	month.TokenLimit = limits.MonthlyTokens
+	month.RequestLimit = limits.MonthlyRequests
+
+	return &UsageSummary{
+		Day:   *day,
+		Month: *month,
+	}, nil
+}
+
+// INTERNAL
+
+// checkQuota fails with *QuotaExceededError once the user has used up any of their quotas.
+// Costs aren't known until the model answers, so the request that crosses a limit still completes.
+func (core Core) checkQuota(ctx context.Context, userID uuid.UUID) error {
+	usage, err := core.GetUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, period := range []UsagePeriod{usage.Day, usage.Month} {
+		if period.TokenLimit > 0 && period.Used.Tokens >= period.TokenLimit {
+			return &QuotaExceededError{
+				Period:   period.Period,
+				Metric:   QuotaMetricTokens,
+				Limit:    period.TokenLimit,
+				Used:     period.Used.Tokens,
+				ResetsAt: period.ResetsAt,
+			}
+		}
+		if period.RequestLimit > 0 && period.Used.Requests >= period.RequestLimit {
+			return &QuotaExceededError{
+				Period:   period.Period,
+				Metric:   QuotaMetricRequests,
+				Limit:    period.RequestLimit,
+				Used:     period.Used.Requests,
+				ResetsAt: period.ResetsAt,
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordUsage stores what a unit of work consumed. It runs even when the work failed, since the
+// requests were still billed, and a failure to record is logged rather than failing the work.
+func (core Core) recordUsage(
+	ctx context.Context,
+	userID uuid.UUID,
+	kind sqlgen.UsageKind,
+	recorder llm.UsageTracker,
+) {
+
+	usage := recorder.Usage()
+	if usage.Requests == 0 {
+		return
+	}
+
+	if err := core.Queries.CreateUsageEvent(context.WithoutCancel(ctx), sqlgen.CreateUsageEventParams{
+		UserID:       userID,
+		Kind:         kind,
+		Requests:     int32(usage.Requests),
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+	}); err != nil {
+		logging.Error(err, "failed to record usage", map[string]interface{}{
+			"user_id": userID,
+			"kind":    kind,
+		})
+	}
+}
+
+// quotaLimits returns the deployment's default limits with the user's overrides applied
+func (core Core) quotaLimits(ctx context.Context, userID uuid.UUID) (QuotaLimits, error) {
+	limits := core.Quotas
+
+	override, err := core.Queries.GetUsageLimitOverride(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return limits, nil
+	}
+	if err != nil {
+		return limits, err
+	}
+
+	if override.DailyTokens.Valid {
+		limits.DailyTokens = override.DailyTokens.Int64
+	}
+	if override.MonthlyTokens.Valid {
+		limits.MonthlyTokens = override.MonthlyTokens.Int64
+	}
+	if override.DailyRequests.Valid {
+		limits.DailyRequests = int64(override.DailyRequests.Int32)
+	}
+	if override.MonthlyRequests.Valid {
+		limits.MonthlyRequests = int64(override.MonthlyRequests.Int32)
+	}
+
+	return limits, nil
+}
+
+// usageSince totals a user's consumption from a point in time
+func (core Core) usageSince(ctx context.Context, userID uuid.UUID, since time.Time) (*UsagePeriod, error) {
+	rows, err := core.Queries.GetUsageTotals(ctx, sqlgen.GetUsageTotalsParams{
+		UserID: userID,
+		Since:  since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	period := &UsagePeriod{Start: since}
+	for _, row := range rows {
+		totals := UsageTotals{
+			Requests: row.Requests,
+			Tokens:   row.Tokens,
+		}
+
+		switch row.Kind {
+		case sqlgen.UsageKindExtraction:
+			period.Extraction = totals
+		case sqlgen.UsageKindAnalysis:
+			period.Analysis = totals
+		case sqlgen.UsageKindEmbedding:
+			period.Embedding = totals
+		}
+
+		period.Used.Requests += totals.Requests
+		period.Used.Tokens += totals.Tokens
+	}
+
+	return period, nil
+}
@@ -0,0 +1,330 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"server/sqlc/sqlgen"
+	"time"
+	_ "time/tzdata" // Timezones load on hosts without a zoneinfo database
+
+	"github.com/google/uuid"
+)
+
+const ( // SM-2 scheduling
+	MinReviewGrade     = 0 // Complete blackout
+	MaxReviewGrade     = 5 // Perfect recall
+	PassingReviewGrade = 3 // Lowest grade that counts as remembered
+
+	MinEaseFactor = 1.3 // New cards start at 2.5
+
+	DefaultReviewQueueSize = 50
+	MaxReviewQueueSize     = 500
+)
+
+// Errors
+var (
+	ErrAnalysisNotFound    error = errors.New("analysis not found")
+	ErrNotFlashcards       error = errors.New("only flashcard analyses can be reviewed")
+	ErrReviewCardNotFound  error = errors.New("review card not found")
+	ErrInvalidReviewGrade  error = errors.New("invalid review grade")
+	ErrInvalidReviewFilter error = errors.New("invalid review queue filter")
+)
+
+// ReviewCard is a flashcard a user has enrolled for spaced repetition, with its SM-2 schedule
+type ReviewCard struct {
+	ID         uuid.UUID
+	AnalysisID uuid.UUID
+	ItemID     uuid.UUID
+	Position   int // Position of the card's item in the analysis
+
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+
+	// Read from the card's item, so they follow the user's edits
+	Question string
+	Answer   string
+	Sources  []Source
+
+	ReviewSchedule
+	LastReviewedAt *time.Time
+	CreatedAt      time.Time
+}
+
+// ReviewSchedule is a card's SM-2 state
+type ReviewSchedule struct {
+	Repetitions  int // Passing reviews in a row
+	IntervalDays int
+	EaseFactor   float64
+	DueAt        time.Time
+}
+
+// ReviewQueueFilter narrows the due queue. Zero values leave it unfiltered.
+type ReviewQueueFilter struct {
+	Course       string
+	CollectionID *uuid.UUID
+	Limit        int    // Defaults to DefaultReviewQueueSize
+	Timezone     string // IANA name of the user's timezone, whose day the queue covers. Defaults to UTC.
+}
+
+// ReviewQueue is the cards due for review, most overdue first
+type ReviewQueue struct {
+	Cards []ReviewCard
+	Due   int64 // Every due card matching the filter, which may be more than Cards holds
+}
+
+// EnrollFlashcards schedules every card of a flashcard analysis for review, starting today.
+// Enrolling again is harmless: cards already enrolled keep their schedule, and cards added since are picked up.
+func (core Core) EnrollFlashcards(ctx context.Context, userID uuid.UUID, analysisID uuid.UUID) ([]ReviewCard, error) {
+	analysis, err := core.Queries.GetUserAnalysis(ctx, sqlgen.GetUserAnalysisParams{
+		ID:     analysisID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnalysisNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if analysis.Type != AnalysisFlashcards {
+		return nil, ErrNotFlashcards
+	}
+
+	items, err := core.analysisItems(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+	for _, item := range items {
+		if err := q.EnrollReviewCard(ctx, sqlgen.EnrollReviewCardParams{
+			UserID:     userID,
+			AnalysisID: analysisID,
+			ItemID:     item.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	rows, err := core.Queries.GetReviewCardsByAnalysis(ctx, sqlgen.GetReviewCardsByAnalysisParams{
+		UserID:     userID,
+		AnalysisID: analysisID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled := make([]ReviewCard, 0, len(rows))
+	for _, row := range rows {
+		card, err := reviewCardFromRow(sqlgen.GetDueReviewCardsRow(row))
+		if err != nil {
+			return nil, err
+		}
+		enrolled = append(enrolled, *card)
+	}
+
+	return enrolled, nil
+}
+
+// GetDueReviews returns the cards due by the end of the user's day across all of their collections and
+// courses, so a card scheduled for later today can be reviewed with the rest of the day's cards
+func (core Core) GetDueReviews(ctx context.Context, userID uuid.UUID, filter ReviewQueueFilter) (*ReviewQueue, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultReviewQueueSize
+	}
+	if filter.Limit < 0 || filter.Limit > MaxReviewQueueSize {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidReviewFilter, MaxReviewQueueSize)
+	}
+
+	course := sql.NullString{String: filter.Course, Valid: filter.Course != ""}
+	var collectionID uuid.NullUUID
+	if filter.CollectionID != nil {
+		collectionID = uuid.NullUUID{UUID: *filter.CollectionID, Valid: true}
+	}
+	location := time.UTC
+	if filter.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(filter.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidReviewFilter, filter.Timezone)
+		}
+	}
+	now := time.Now().In(location)
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, location)
+
+	rows, err := core.Queries.GetDueReviewCards(ctx, sqlgen.GetDueReviewCardsParams{
+		UserID:       userID,
+		DueBy:        endOfDay,
+		Course:       course,
+		CollectionID: collectionID,
+		MaxCards:     int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := core.Queries.CountDueReviewCards(ctx, sqlgen.CountDueReviewCardsParams{
+		UserID:       userID,
+		DueBy:        endOfDay,
+		Course:       course,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &ReviewQueue{
+		Cards: make([]ReviewCard, 0, len(rows)),
+		Due:   due,
+	}
+	for _, row := range rows {
+		card, err := reviewCardFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		queue.Cards = append(queue.Cards, *card)
+	}
+
+	return queue, nil
+}
+
+// GradeReviewCard records how well the user recalled a card (0-5) and schedules its next review
+func (core Core) GradeReviewCard(ctx context.Context, userID uuid.UUID, cardID uuid.UUID, grade int) (*ReviewCard, error) {
+	if grade < MinReviewGrade || grade > MaxReviewGrade {
+		return nil, fmt.Errorf("%w: grade must be between %d and %d", ErrInvalidReviewGrade, MinReviewGrade, MaxReviewGrade)
+	}
+
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+
+	row, err := q.GetReviewCardForUpdate(ctx, sqlgen.GetReviewCardForUpdateParams{
+		ID:     cardID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReviewCardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := reviewCardFromRow(sqlgen.GetDueReviewCardsRow(row))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	card.ReviewSchedule = scheduleReview(card.ReviewSchedule, grade, now)
+	card.LastReviewedAt = &now
+
+	if err := q.UpdateReviewSchedule(ctx, sqlgen.UpdateReviewScheduleParams{
+		ID:           card.ID,
+		Repetitions:  int32(card.Repetitions),
+		IntervalDays: int32(card.IntervalDays),
+		EaseFactor:   card.EaseFactor,
+		DueAt:        card.DueAt,
+		ReviewedAt:   sql.NullTime{Time: now, Valid: true},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := q.CreateReviewLog(ctx, sqlgen.CreateReviewLogParams{
+		CardID:       card.ID,
+		Grade:        int16(grade),
+		IntervalDays: int32(card.IntervalDays),
+		EaseFactor:   card.EaseFactor,
+		ReviewedAt:   now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// INTERNAL
+
+// scheduleReview applies SM-2. A passing grade grows the interval (1 day, 6 days, then by the ease factor);
+// a failing one starts the card over tomorrow. The ease factor moves with every grade but never drops below MinEaseFactor.
+func scheduleReview(schedule ReviewSchedule, grade int, now time.Time) ReviewSchedule {
+	if grade >= PassingReviewGrade {
+		switch schedule.Repetitions {
+		case 0:
+			schedule.IntervalDays = 1
+		case 1:
+			schedule.IntervalDays = 6
+		default:
+			schedule.IntervalDays = int(math.Round(float64(schedule.IntervalDays) * schedule.EaseFactor))
+		}
+		schedule.Repetitions++
+	} else {
+		schedule.Repetitions = 0
+		schedule.IntervalDays = 1
+	}
+
+	miss := float64(MaxReviewGrade - grade)
+	schedule.EaseFactor = max(MinEaseFactor, schedule.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	schedule.DueAt = now.AddDate(0, 0, schedule.IntervalDays)
+
+	return schedule
+}
+
+// reviewCardFromRow reads a card joined with its item and collection. The card queries select the same
+// columns, so their rows convert to GetDueReviewCardsRow.
+func reviewCardFromRow(row sqlgen.GetDueReviewCardsRow) (*ReviewCard, error) {
+	var content struct {
+		Question string   `json:"question"`
+		Answer   string   `json:"answer"`
+		Sources  []Source `json:"sources"`
+	}
+	if err := json.Unmarshal(row.Content, &content); err != nil {
+		return nil, err
+	}
+
+	card := &ReviewCard{
+		ID:              row.ID,
+		AnalysisID:      row.AnalysisID,
+		ItemID:          row.ItemID,
+		Position:        int(row.Position),
+		CollectionID:    row.CollectionID,
+		CollectionTitle: row.CollectionTitle,
+		Course:          row.Course,
+		Question:        content.Question,
+		Answer:          content.Answer,
+		Sources:         content.Sources,
+		ReviewSchedule: ReviewSchedule{
+			Repetitions:  int(row.Repetitions),
+			IntervalDays: int(row.IntervalDays),
+			EaseFactor:   row.EaseFactor,
+			DueAt:        row.DueAt,
+		},
+		CreatedAt: row.CreatedAt,
+	}
+
+	if row.LastReviewedAt.Valid {
+		card.LastReviewedAt = &row.LastReviewedAt.Time
+	}
+
+	return card, nil
+}
@@ -0,0 +1,385 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/api/logging"
+	"server/api/tools/features/llm"
+	"server/sqlc/sqlgen"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+)
+
+const (
+	MaxSearchQueryLength = 1000 // Characters
+
+	DefaultSearchResults = 10
+	MaxSearchResults     = 50
+
+	segmentLength          = 1200 // Characters a segment holds at most, so each embeds a focused passage
+	embeddingBatchSize     = 64   // Segments embedded per request
+	embeddingBackfillBatch = 100  // Pages the backfill embeds at a time
+)
+
+// Errors
+var (
+	ErrInvalidSearch error = errors.New("invalid search")
+)
+
+// SearchFilter narrows a search. Zero values leave it unfiltered.
+type SearchFilter struct {
+	Course       string
+	CollectionID *uuid.UUID
+	Limit        int // Defaults to DefaultSearchResults
+}
+
+// SearchResult is a passage of a user's material that matches a search
+type SearchResult struct {
+	SegmentID uuid.UUID
+	Content   string
+	Score     float64 // Cosine similarity to the query, higher is closer
+
+	DocumentID      uuid.UUID
+	DocumentTitle   string
+	Page            int
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+// SearchResults are the passages closest in meaning to a query, best first
+type SearchResults struct {
+	Query   string
+	Results []SearchResult
+	Usage   llm.Usage
+}
+
+// Search finds the passages of the user's extracted documents closest in meaning to a query, across
+// everything they own unless filtered. Pages are found once they are embedded, which happens as they are
+// extracted, or by the backfill for pages extracted before. Embedding the query counts towards the quota.
+func (core Core) Search(ctx context.Context, userID uuid.UUID, query string, filter SearchFilter) (*SearchResults, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: the query is empty", ErrInvalidSearch)
+	}
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidSearch, MaxSearchQueryLength)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultSearchResults
+	}
+	if filter.Limit < 0 || filter.Limit > MaxSearchResults {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, MaxSearchResults)
+	}
+
+	if err := core.checkQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	recorder := llm.NewEmbeddingRecorder(core.Services.Embedder)
+	defer core.recordUsage(ctx, userID, sqlgen.UsageKindEmbedding, recorder)
+
+	embeddings, err := recorder.Embed(ctx, llm.EmbeddingRequest{
+		Texts: []string{query},
+		Query: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var collectionID uuid.NullUUID
+	if filter.CollectionID != nil {
+		collectionID = uuid.NullUUID{UUID: *filter.CollectionID, Valid: true}
+	}
+
+	rows, err := core.Queries.SearchExtractionSegments(ctx, sqlgen.SearchExtractionSegmentsParams{
+		Embedding:    pgvector.NewVector(embeddings.Vectors[0]),
+		UserID:       userID,
+		Model:        recorder.Model(),
+		CollectionID: collectionID,
+		Course:       sql.NullString{String: filter.Course, Valid: filter.Course != ""},
+		MaxResults:   int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := &SearchResults{
+		Query:   query,
+		Results: make([]SearchResult, 0, len(rows)),
+		Usage:   recorder.Usage(),
+	}
+	for _, row := range rows {
+		results.Results = append(results.Results, SearchResult{
+			SegmentID:       row.ID,
+			Content:         row.Content,
+			Score:           row.Score,
+			DocumentID:      row.DocumentID,
+			DocumentTitle:   row.DocumentTitle,
+			Page:            int(row.Page),
+			CollectionID:    row.CollectionID,
+			CollectionTitle: row.CollectionTitle,
+			Course:          row.Course,
+		})
+	}
+
+	return results, nil
+}
+
+// StartEmbeddingBackfill embeds, in the background, every extracted page without embeddings from the
+// current model: pages extracted before search existed, pages whose embedding failed, and every page after
+// the embedding model changes. It stops when ctx is cancelled.
+func (core Core) StartEmbeddingBackfill(ctx context.Context) {
+	if core.EmbeddingBackfillInterval <= 0 {
+		return
+	}
+
+	go core.embeddingBackfiller(ctx)
+}
+
+// INTERNAL
+
+// embeddingBackfiller works through the backlog of pages to embed, then sleeps until the interval elapses.
+// Each pass moves on past pages that fail, so they are only retried once per interval and never hold up
+// the pages after them.
+func (core Core) embeddingBackfiller(ctx context.Context) {
+	ticker := time.NewTicker(core.EmbeddingBackfillInterval)
+	defer ticker.Stop()
+
+	for {
+		var after *embeddingCursor
+		for ctx.Err() == nil {
+			next, err := core.backfillEmbeddings(ctx, after)
+			if err != nil {
+				logging.Error(err, "failed to backfill embeddings", nil)
+				break
+			}
+			if next == nil {
+				break
+			}
+			after = next
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// embeddingCursor is the last page a pass over the backlog has reached
+type embeddingCursor struct {
+	documentID uuid.UUID
+	page       int32
+}
+
+func (cursor *embeddingCursor) params(params sqlgen.GetUnembeddedExtractionsParams) sqlgen.GetUnembeddedExtractionsParams {
+	if cursor != nil {
+		params.AfterDocumentID = uuid.NullUUID{UUID: cursor.documentID, Valid: true}
+		params.AfterPage = sql.NullInt32{Int32: cursor.page, Valid: true}
+	}
+	return params
+}
+
+// backfillEmbeddings embeds the next batch of pages without embeddings after the cursor and returns the
+// cursor to continue from, or nil once the backlog is drained. The backfill is upkeep of the deployment
+// rather than something a user asked for, so its usage is logged instead of counting towards anyone's quota.
+func (core Core) backfillEmbeddings(ctx context.Context, after *embeddingCursor) (*embeddingCursor, error) {
+	rows, err := core.Queries.GetUnembeddedExtractions(ctx, after.params(sqlgen.GetUnembeddedExtractionsParams{
+		Model:          core.Services.Embedder.Model(),
+		MaxExtractions: embeddingBackfillBatch,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	recorder := llm.NewEmbeddingRecorder(core.Services.Embedder)
+	embedded := core.embedExtractions(ctx, recorder, rows)
+
+	usage := recorder.Usage()
+	logging.Info("backfilled embeddings", map[string]interface{}{
+		"pages":        embedded,
+		"failed_pages": len(rows) - embedded,
+		"model":        recorder.Model(),
+		"requests":     usage.Requests,
+		"input_tokens": usage.InputTokens,
+	})
+
+	if len(rows) < embeddingBackfillBatch {
+		return nil, nil
+	}
+	last := rows[len(rows)-1]
+	return &embeddingCursor{documentID: last.DocumentID, page: last.Page}, nil
+}
+
+// embedDocument embeds the pages of a newly extracted document. Failures are logged rather than failing
+// the extraction, since the text is stored either way and the backfill embeds the pages later.
+func (core Core) embedDocument(ctx context.Context, userID uuid.UUID, documentID uuid.UUID) {
+	recorder := llm.NewEmbeddingRecorder(core.Services.Embedder)
+	defer core.recordUsage(ctx, userID, sqlgen.UsageKindEmbedding, recorder)
+
+	var after *embeddingCursor
+	for ctx.Err() == nil {
+		rows, err := core.Queries.GetUnembeddedExtractions(ctx, after.params(sqlgen.GetUnembeddedExtractionsParams{
+			DocumentID:     uuid.NullUUID{UUID: documentID, Valid: true},
+			Model:          recorder.Model(),
+			MaxExtractions: embeddingBackfillBatch,
+		}))
+		if err != nil {
+			logging.Error(err, "failed to embed document, the backfill will retry", map[string]interface{}{
+				"document_id": documentID,
+			})
+			return
+		}
+
+		core.embedExtractions(ctx, recorder, rows)
+
+		if len(rows) < embeddingBackfillBatch {
+			return
+		}
+		last := rows[len(rows)-1]
+		after = &embeddingCursor{documentID: last.DocumentID, page: last.Page}
+	}
+}
+
+// embedExtractions embeds and stores pages one at a time and returns how many succeeded. A page that
+// fails is logged and left for the backfill to retry, without holding up the others.
+func (core Core) embedExtractions(
+	ctx context.Context,
+	embedder llm.Embedder,
+	extractions []sqlgen.GetUnembeddedExtractionsRow,
+) int {
+
+	embedded := 0
+	for _, extraction := range extractions {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := core.embedExtraction(ctx, embedder, extraction); err != nil {
+			logging.Error(err, "failed to embed page, the backfill will retry", map[string]interface{}{
+				"extraction_id": extraction.ID,
+				"document_id":   extraction.DocumentID,
+				"page":          extraction.Page,
+			})
+			continue
+		}
+		embedded++
+	}
+
+	return embedded
+}
+
+// embedExtraction splits a page into segments, embeds them and stores them, replacing the page's earlier
+// segments. The segments are stored together once all of them are embedded, so the page is never left
+// half done.
+func (core Core) embedExtraction(
+	ctx context.Context,
+	embedder llm.Embedder,
+	extraction sqlgen.GetUnembeddedExtractionsRow,
+) error {
+
+	segments := segmentText(extraction.Content)
+
+	vectors := make([][]float32, 0, len(segments))
+	for start := 0; start < len(segments); start += embeddingBatchSize {
+		batch := segments[start:min(start+embeddingBatchSize, len(segments))]
+
+		embeddings, err := embedder.Embed(ctx, llm.EmbeddingRequest{Texts: batch})
+		if err != nil {
+			return err
+		}
+		vectors = append(vectors, embeddings.Vectors...)
+	}
+
+	tx, err := core.Services.Postgres.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := core.Queries.WithTx(tx)
+	if err := q.DeleteExtractionSegments(ctx, extraction.ID); err != nil {
+		return err
+	}
+	for i, content := range segments {
+		if err := q.CreateExtractionSegment(ctx, sqlgen.CreateExtractionSegmentParams{
+			ExtractionID: extraction.ID,
+			Segment:      int32(i),
+			Content:      content,
+			Model:        embedder.Model(),
+			Embedding:    pgvector.NewVector(vectors[i]),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// segmentText splits a page into passages of up to segmentLength characters. Paragraphs are kept together
+// where they fit, short ones share a passage, and longer ones are split between words.
+func segmentText(content string) []string {
+	var segments []string
+	var current strings.Builder
+	currentLength := 0
+
+	flush := func() {
+		if currentLength > 0 {
+			segments = append(segments, current.String())
+		}
+		current.Reset()
+		currentLength = 0
+	}
+
+	add := func(text string, separator string) {
+		length := utf8.RuneCountInString(text)
+		if currentLength > 0 && currentLength+len(separator)+length > segmentLength {
+			flush()
+		}
+		if currentLength > 0 {
+			current.WriteString(separator)
+			currentLength += len(separator)
+		}
+		current.WriteString(text)
+		currentLength += length
+	}
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(paragraph) <= segmentLength {
+			add(paragraph, "\n\n")
+			continue
+		}
+
+		// Too long to keep whole, so it starts its own passages and fills them word by word
+		flush()
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > segmentLength {
+				runes := []rune(word)
+				add(string(runes[:segmentLength]), " ")
+				word = string(runes[segmentLength:])
+			}
+			add(word, " ")
+		}
+		flush()
+	}
+	flush()
+
+	return segments
+}
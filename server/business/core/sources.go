@@ -0,0 +1,242 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"server/sqlc/sqlgen"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// SourceAnchor ties a [S#] label in a snapshot's content back to the page it was extracted from
+type SourceAnchor struct {
+	Label      string    `json:"label"`
+	DocumentID uuid.UUID `json:"documentId"`
+	Title      string    `json:"title"`
+	Page       int       `json:"page"`
+}
+
+// Source is a resolved citation as stored in analysis results
+type Source struct {
+	DocumentID uuid.UUID `json:"documentId"`
+	Title      string    `json:"title"`
+	Page       int       `json:"page"`
+}
+
+// String cites the source for people, as "Title, p. 3"
+func (source Source) String() string {
+	if source.Page <= 0 {
+		return source.Title
+	}
+	return fmt.Sprintf("%s, p. %d", source.Title, source.Page)
+}
+
+// INTERNAL
+
+func sourceLabel(index int) string {
+	return fmt.Sprintf("S%d", index+1)
+}
+
+// resolveSources replaces the [S#] labels in every "sources" field of a result with the documents and pages
+// they stand for. Labels the snapshot doesn't know are dropped.
+func resolveSources(result json.RawMessage, anchors []SourceAnchor) (json.RawMessage, error) {
+	byLabel := make(map[string]SourceAnchor, len(anchors))
+	for _, anchor := range anchors {
+		byLabel[anchor.Label] = anchor
+	}
+
+	var value any
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(resolveSourcesIn(value, byLabel))
+}
+
+func resolveSourcesIn(value any, byLabel map[string]SourceAnchor) any {
+	switch v := value.(type) {
+	case []any:
+		for i, item := range v {
+			v[i] = resolveSourcesIn(item, byLabel)
+		}
+
+	case map[string]any:
+		for key, field := range v {
+			if key != "sources" {
+				v[key] = resolveSourcesIn(field, byLabel)
+				continue
+			}
+
+			labels, _ := field.([]any)
+			seen := map[string]bool{}
+			resolved := []Source{}
+			for _, label := range labels {
+				name, _ := label.(string)
+				anchor, ok := byLabel[normalizeLabel(name)]
+				if !ok || seen[anchor.Label] {
+					continue
+				}
+				seen[anchor.Label] = true
+				resolved = append(resolved, Source{
+					DocumentID: anchor.DocumentID,
+					Title:      anchor.Title,
+					Page:       anchor.Page,
+				})
+			}
+			v[key] = resolved
+		}
+	}
+
+	return value
+}
+
+// normalizeLabel accepts the bracketed form models sometimes copy from the content, e.g. "[S3]"
+func normalizeLabel(label string) string {
+	if len(label) >= 2 && label[0] == '[' && label[len(label)-1] == ']' {
+		return label[1 : len(label)-1]
+	}
+	return label
+}
+
+// unionSources collects the distinct labels cited anywhere in a set of partial results, in first-seen order
+func unionSources(partials []json.RawMessage) ([]string, error) {
+	seen := map[string]bool{}
+	labels := []string{}
+
+	var collect func(value any)
+	collect = func(value any) {
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]any:
+			for key, field := range v {
+				if key != "sources" {
+					collect(field)
+					continue
+				}
+				items, _ := field.([]any)
+				for _, item := range items {
+					if label, ok := item.(string); ok && !seen[label] {
+						seen[label] = true
+						labels = append(labels, label)
+					}
+				}
+			}
+		}
+	}
+
+	for _, partial := range partials {
+		var value any
+		if err := json.Unmarshal(partial, &value); err != nil {
+			return nil, err
+		}
+		collect(value)
+	}
+
+	return labels, nil
+}
+
+// relevantMaterial picks the pages a question is best answered from, labelled as in the collection's content
+// so citations resolve the same way. The pages in cited come first, then the pages sharing the most words
+// with the query, while they fit in maxTokens. Pages keep the collection's order.
+func relevantMaterial(
+	extractions []sqlgen.GetDocumentExtractionsByCollectionRow,
+	query string,
+	cited []Source,
+	maxTokens int,
+) (string, []SourceAnchor) {
+
+	isCited := make(map[Source]bool, len(cited))
+	for _, source := range cited {
+		isCited[Source{DocumentID: source.DocumentID, Page: source.Page}] = true
+	}
+
+	words := map[string]bool{}
+	for _, word := range significantWords(query) {
+		words[word] = true
+	}
+
+	type candidate struct {
+		index int
+		cited bool
+		score int
+	}
+	candidates := make([]candidate, 0, len(extractions))
+	for i, e := range extractions {
+		counted := map[string]bool{}
+		score := 0
+		for _, word := range significantWords(e.Content) {
+			if words[word] && !counted[word] {
+				counted[word] = true
+				score++
+			}
+		}
+
+		candidates = append(candidates, candidate{
+			index: i,
+			cited: isCited[Source{DocumentID: e.DocumentID, Page: int(e.Page)}],
+			score: score,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].cited != candidates[j].cited {
+			return candidates[i].cited
+		}
+		return candidates[i].score > candidates[j].score
+	})
+
+	// Always include the best page, however long. Pages with nothing in common with the query are left out.
+	chosen := map[int]bool{}
+	tokens := 0
+	for _, c := range candidates {
+		cost := estimateTokens(extractions[c.index].Content)
+		if len(chosen) > 0 && (tokens+cost > maxTokens || !c.cited && c.score == 0) {
+			continue
+		}
+		chosen[c.index] = true
+		tokens += cost
+	}
+
+	var content strings.Builder
+	anchors := make([]SourceAnchor, 0, len(chosen))
+	for i, e := range extractions {
+		if !chosen[i] {
+			continue
+		}
+		anchor := SourceAnchor{
+			Label:      sourceLabel(i),
+			DocumentID: e.DocumentID,
+			Title:      e.Title,
+			Page:       int(e.Page),
+		}
+		anchors = append(anchors, anchor)
+
+		content.WriteString("[" + anchor.Label + "]\n")
+		content.WriteString(e.Content)
+		content.WriteString("\n\n")
+	}
+
+	return content.String(), anchors
+}
+
+// significantWords returns the lower-cased words of a text long enough to say something about its topic
+func significantWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := fields[:0]
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) >= 4 {
+			words = append(words, field)
+		}
+	}
+	return words
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"server/api/tools/features/studyguide"
+	"server/sqlc/sqlgen"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudyGuideFormat is a document format a study guide can be exported to
+type StudyGuideFormat string
+
+const (
+	StudyGuidePDF      StudyGuideFormat = "pdf"
+	StudyGuideMarkdown StudyGuideFormat = "markdown"
+	StudyGuideLaTeX    StudyGuideFormat = "latex" // Standalone .tex document
+)
+
+// Errors
+var (
+	ErrCollectionNotFound error = errors.New("collection not found")
+	ErrNoStudyGuide       error = errors.New("the collection has no summary or deep summary to build a study guide from")
+)
+
+// ExportStudyGuide renders a collection's newest summary and deep summary into a study guide, with its newest
+// flashcards and quiz answer key as appendices. Edits to items are included.
+func (core Core) ExportStudyGuide(
+	ctx context.Context,
+	userID uuid.UUID,
+	collectionID uuid.UUID,
+	format StudyGuideFormat,
+) (*Export, error) {
+
+	if format != StudyGuidePDF && format != StudyGuideMarkdown && format != StudyGuideLaTeX {
+		return nil, ErrInvalidExportFormat
+	}
+
+	collection, err := core.Queries.GetCollection(ctx, sqlgen.GetCollectionParams{
+		UserID: userID,
+		ID:     collectionID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCollectionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := core.Queries.GetLatestCollectionAnalyses(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	guide, err := core.studyGuide(ctx, analyses)
+	if err != nil {
+		return nil, err
+	}
+	if guide.Overview == nil && len(guide.Concepts) == 0 {
+		return nil, ErrNoStudyGuide
+	}
+
+	guide.Title = collection.Title
+	guide.Course = collection.Course
+	guide.Date = time.Now()
+
+	export := &Export{}
+	name := exportFilename(collection.Title)
+
+	switch format {
+	case StudyGuidePDF:
+		export.Filename = name + ".pdf"
+		export.ContentType = studyguide.PDFMimeType
+		export.Data, err = studyguide.PDF(*guide)
+	case StudyGuideMarkdown:
+		export.Filename = name + ".md"
+		export.ContentType = studyguide.MarkdownMimeType
+		export.Data = studyguide.Markdown(*guide)
+	case StudyGuideLaTeX:
+		export.Filename = name + ".tex"
+		export.ContentType = studyguide.LaTeXMimeType
+		export.Data = studyguide.LaTeX(*guide)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// INTERNAL
+
+// studyGuide gathers the content of a guide from the newest analysis of each kind
+func (core Core) studyGuide(ctx context.Context, analyses []sqlgen.CollectionAnalysis) (*studyguide.Guide, error) {
+	guide := &studyguide.Guide{}
+
+	for _, analysis := range analyses {
+		switch analysis.Type {
+		case AnalysisSummary:
+			var summary struct {
+				Summary string   `json:"summary"`
+				Sources []Source `json:"sources"`
+			}
+			if err := json.Unmarshal(analysis.Result, &summary); err != nil {
+				return nil, err
+			}
+			guide.Overview = &studyguide.Summary{
+				Text:    summary.Summary,
+				Sources: citations(summary.Sources),
+			}
+
+		case AnalysisDeepSummary:
+			items, err := core.analysisItems(ctx, analysis.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				var concept struct {
+					Concept    string   `json:"concept"`
+					Definition string   `json:"definition"`
+					Details    string   `json:"details"`
+					Sources    []Source `json:"sources"`
+				}
+				if err := json.Unmarshal(item.Content, &concept); err != nil {
+					return nil, err
+				}
+				guide.Concepts = append(guide.Concepts, studyguide.Concept{
+					Name:       concept.Concept,
+					Definition: concept.Definition,
+					Details:    concept.Details,
+					Sources:    citations(concept.Sources),
+				})
+			}
+
+		case AnalysisFlashcards:
+			items, err := core.analysisItems(ctx, analysis.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				var card struct {
+					Question string `json:"question"`
+					Answer   string `json:"answer"`
+				}
+				if err := json.Unmarshal(item.Content, &card); err != nil {
+					return nil, err
+				}
+				guide.Flashcards = append(guide.Flashcards, studyguide.Flashcard{
+					Question: card.Question,
+					Answer:   card.Answer,
+				})
+			}
+
+		case AnalysisQuiz:
+			items, err := core.quizItems(ctx, analysis.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				guide.Quiz = append(guide.Quiz, studyguide.QuizQuestion{
+					Question:     item.Question,
+					Options:      item.Options,
+					CorrectIndex: item.CorrectIndex,
+				})
+			}
+		}
+	}
+
+	return guide, nil
+}
+
+// citations formats sources for people to read
+func citations(sources []Source) []string {
+	result := make([]string, 0, len(sources))
+	for _, source := range sources {
+		result = append(result, source.String())
+	}
+	return result
+}
@@ -30,16 +30,62 @@ type Vars struct {
 	MinioDefaultBucket string `env:"MINIO_DEFAULT_BUCKET,notEmpty"`
 	MinioUseSSL        bool   `env:"MINIO_USE_SSL,notEmpty"`
 
-	// Gemini
-	GeminiAIKey string `env:"GEMINI_API_KEY,notEmpty"`
+	// Gemini. Leave the key empty to disable the provider.
+	GeminiAIKey string `env:"GEMINI_API_KEY"`
+	GeminiModel string `env:"GEMINI_MODEL" envDefault:"gemini-2.5-flash"`
 
-	// OpenAI
-	OpenAIKey string `env:"OPENAI_API_KEY,notEmpty"`
+	// OpenAI. Leave the key empty to disable the provider.
+	OpenAIKey string `env:"OPENAI_API_KEY"`
+
+	// Requests per minute allowed to each provider across the whole server. 0 disables the limit.
+	GeminiRequestsPerMin int `env:"GEMINI_REQUESTS_PER_MIN" envDefault:"60"`
+	OpenAIRequestsPerMin int `env:"OPENAI_REQUESTS_PER_MIN" envDefault:"60"`
+
+	// LLM provider used when a request doesn't ask for one ("openai", "gemini", or "fake" for
+	// deterministic offline output that needs no API key)
+	LLMProvider string `env:"LLM_PROVIDER" envDefault:"openai"`
+
+	// Provider that embeds extracted pages for semantic search ("openai", "gemini" or "fake"). Empty uses
+	// LLM_PROVIDER. Changing the provider or model re-embeds every page in the background.
+	EmbeddingProvider    string `env:"EMBEDDING_PROVIDER"`
+	OpenAIEmbeddingModel string `env:"OPENAI_EMBEDDING_MODEL" envDefault:"text-embedding-3-small"`
+	GeminiEmbeddingModel string `env:"GEMINI_EMBEDDING_MODEL" envDefault:"gemini-embedding-001"`
+
+	// Seconds between checks for extracted pages without embeddings, which covers pages extracted before
+	// search existed and pages whose embedding failed. 0 disables the backfill.
+	EmbeddingBackfillSecs int `env:"EMBEDDING_BACKFILL_SECS" envDefault:"60"`
 
 	// Application Configuration
 	UploadBucketName    string `env:"UPLOAD_BUCKET_NAME" envDefault:"image-analysis-images"`
 	PresignedExpiryMins int    `env:"PRESIGNED_EXPIRY_MINS" envDefault:"5"`
-	OpenAIModel         string `env:"OPENAI_MODEL" envDefault:"gpt-4o"`
+	OpenAIModel         string `env:"OPENAI_MODEL" envDefault:"gpt-5-chat-latest"`
+
+	// PDF pages without a text layer are rendered with pdftoppm (poppler-utils) for vision OCR
+	PDFRasterizerBin string `env:"PDF_RASTERIZER_BIN" envDefault:"pdftoppm"`
+	PDFRasterDPI     int    `env:"PDF_RASTER_DPI" envDefault:"150"`
+
+	// Documents extracted at once per analysis
+	ExtractionWorkers int `env:"EXTRACTION_WORKERS" envDefault:"4"`
+
+	// Analysis Jobs
+	AnalysisWorkers        int `env:"ANALYSIS_WORKERS" envDefault:"2"`
+	AnalysisJobPollSecs    int `env:"ANALYSIS_JOB_POLL_SECS" envDefault:"5"`
+	AnalysisJobTimeoutMins int `env:"ANALYSIS_JOB_TIMEOUT_MINS" envDefault:"30"`
+	AnalysisJobMaxAttempts int `env:"ANALYSIS_JOB_MAX_ATTEMPTS" envDefault:"3"`
+
+	// Analysis Output
+	AnalysisRepairAttempts int `env:"ANALYSIS_REPAIR_ATTEMPTS" envDefault:"2"`
+
+	// Collections estimated above the context budget are analyzed in chunks and merged
+	AnalysisContextTokens int `env:"ANALYSIS_CONTEXT_TOKENS" envDefault:"100000"`
+	AnalysisChunkTokens   int `env:"ANALYSIS_CHUNK_TOKENS" envDefault:"30000"`
+
+	// Default per-user quotas for model usage (extraction and analysis), per UTC day and calendar month.
+	// 0 disables a limit. Individual users can be given other limits in usage_limit_overrides.
+	QuotaDailyTokens     int64 `env:"QUOTA_DAILY_TOKENS" envDefault:"1000000"`
+	QuotaMonthlyTokens   int64 `env:"QUOTA_MONTHLY_TOKENS" envDefault:"10000000"`
+	QuotaDailyRequests   int64 `env:"QUOTA_DAILY_REQUESTS" envDefault:"500"`
+	QuotaMonthlyRequests int64 `env:"QUOTA_MONTHLY_REQUESTS" envDefault:"5000"`
 }
 
 func Get() (*Vars, error) {
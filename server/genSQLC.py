@@ -9,6 +9,12 @@ SQLC_YAML_ENTRY = '''
     gen:
       go:
         out: "{output}"
+        overrides:
+          - db_type: "vector"
+            go_type:
+              import: "github.com/pgvector/pgvector-go"
+              package: "pgvector"
+              type: "Vector"
 '''
 
 SQLC_DEFAULT_OUTPUT = 'sqlc/sqlgen'
@@ -0,0 +1,227 @@
+package corehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (GET /core/analysis/{analysisID}/items)
+func (handler Handler) GetAnalysisItems(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	items, err := handler.Core.GetAnalysisItems(r.Context(), *userID, analysisID)
+	if err != nil {
+		analysisItemError(w, err)
+		return
+	}
+
+	result, err := analysisItemsResponse(items)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (POST /core/analysis/{analysisID}/items)
+func (handler Handler) AddAnalysisItem(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	request, err := apirequests.Request[gencore.AnalysisItemRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	content, err := json.Marshal(request.Content)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	item, err := handler.Core.AddAnalysisItem(r.Context(), *userID, analysisID, content)
+	if err != nil {
+		analysisItemError(w, err)
+		return
+	}
+
+	result, err := analysisItemResponse(item)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (PUT /core/analysis/{analysisID}/items/order)
+func (handler Handler) ReorderAnalysisItems(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	request, err := apirequests.Request[gencore.ReorderAnalysisItemsRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	items, err := handler.Core.ReorderAnalysisItems(r.Context(), *userID, analysisID, request.ItemIds)
+	if err != nil {
+		analysisItemError(w, err)
+		return
+	}
+
+	result, err := analysisItemsResponse(items)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (PUT /core/analysis-item/{itemID})
+func (handler Handler) EditAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	request, err := apirequests.Request[gencore.AnalysisItemRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	content, err := json.Marshal(request.Content)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	item, err := handler.Core.EditAnalysisItem(r.Context(), *userID, itemID, content)
+	if err != nil {
+		analysisItemError(w, err)
+		return
+	}
+
+	result, err := analysisItemResponse(item)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (DELETE /core/analysis-item/{itemID})
+func (handler Handler) DeleteAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	if err := handler.Core.DeleteAnalysisItem(r.Context(), *userID, itemID); err != nil {
+		analysisItemError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// (DELETE /core/analysis-item/{itemID}/edit)
+func (handler Handler) RevertAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	item, err := handler.Core.RevertAnalysisItem(r.Context(), *userID, itemID)
+	if err != nil {
+		analysisItemError(w, err)
+		return
+	}
+
+	result, err := analysisItemResponse(item)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// analysisItemError responds with the status an analysis item operation's error maps to
+func analysisItemError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, core.ErrAnalysisNotFound):
+		apiresponses.Error(w, "Analysis not found", http.StatusNotFound)
+	case errors.Is(err, core.ErrAnalysisItemNotFound):
+		apiresponses.Error(w, "Item not found", http.StatusNotFound)
+	case errors.Is(err, core.ErrNotItemized),
+		errors.Is(err, core.ErrInvalidAnalysisItem),
+		errors.Is(err, core.ErrInvalidItemOrder):
+		apiresponses.BadRequest(w, err.Error(), err)
+	default:
+		apiresponses.InternalError(w, "Internal Error", err)
+	}
+}
+
+func analysisItemsResponse(items []core.AnalysisItem) (gencore.AnalysisItems, error) {
+	result := make(gencore.AnalysisItems, 0, len(items))
+	for _, item := range items {
+		response, err := analysisItemResponse(&item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, response)
+	}
+	return result, nil
+}
+
+func analysisItemResponse(item *core.AnalysisItem) (gencore.AnalysisItem, error) {
+	result := gencore.AnalysisItem{
+		Id:         item.ID,
+		AnalysisId: item.AnalysisID,
+		Position:   item.Position,
+		Edited:     item.Edited,
+		CreatedAt:  item.CreatedAt,
+		UpdatedAt:  item.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(item.Content, &result.Content); err != nil {
+		return gencore.AnalysisItem{}, err
+	}
+
+	if item.Generated != nil {
+		var generated map[string]interface{}
+		if err := json.Unmarshal(item.Generated, &generated); err != nil {
+			return gencore.AnalysisItem{}, err
+		}
+		result.Generated = &generated
+	}
+
+	return result, nil
+}
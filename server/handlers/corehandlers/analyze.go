@@ -1,9 +1,15 @@
 package corehandlers
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"server/api/apirequests"
 	"server/api/apiresponses"
+	"server/api/logging"
+	"server/api/tools/features/llm"
+	"server/business/core"
 	"server/handlers/generated/gencore"
 	"server/sqlc/sqlgen"
 
@@ -29,18 +35,84 @@ func (h Handler) AnalyzeCollection(w http.ResponseWriter, r *http.Request, id op
 		r.Context(),
 		*userID,
 		id,
-		sqlgen.AnalysisType(req.Type),
+		analysisRequest(req),
 	)
+	if err != nil {
+		analysisError(w, err)
+		return
+	}
+
+	result, err := analysisResult(analysis.Type, analysis.Result)
 	if err != nil {
 		apiresponses.InternalError(w, "Internal Error", err)
 		return
 	}
 
 	apiresponses.Success(w, gencore.CollectionAnalysis{
-		Id:        analysis.ID,
-		Result:    string(analysis.Result),
-		Type:      gencore.CollectionAnalysisType(analysis.Type),
-		CreatedAt: &analysis.CreatedAt,
+		Id:         analysis.ID,
+		Result:     result,
+		Type:       analysis.Type,
+		CreatedAt:  analysis.CreatedAt,
+		Parameters: analysisParametersResponse(analysis.Parameters),
+		TemplateId: analysis.TemplateID,
+		Usage:      modelUsageResponse(analysis.Usage),
+		Reused:     &analysis.Reused,
+	})
+}
+
+func (h Handler) StreamCollectionAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid Request", err)
+		return
+	}
+
+	req, err := apirequests.Request[gencore.AnalyzeCollectionRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid Request", err)
+		return
+	}
+
+	stream := apiresponses.NewEventStream(w)
+	ctx := core.WithAnalysisObserver(r.Context(), func(event core.AnalysisEvent) {
+		if err := stream.Send(event.Type, event.Data); err != nil {
+			logging.Error(err, "failed to send analysis event", nil)
+		}
+	})
+
+	analysis, err := h.Core.AnalyzeCollection(ctx, *userID, id, analysisRequest(req))
+	if err != nil {
+		// Nothing has been streamed yet, so a regular status code can still be sent
+		if !stream.Started() {
+			analysisError(w, err)
+			return
+		}
+
+		logging.Error(err, "streamed analysis failed", map[string]interface{}{
+			"collection_id": id,
+		})
+		_ = stream.Send("error", analysisStreamError(err))
+		return
+	}
+
+	result, err := analysisResult(analysis.Type, analysis.Result)
+	if err != nil {
+		logging.Error(err, "streamed analysis failed", map[string]interface{}{
+			"collection_id": id,
+		})
+		_ = stream.Send("error", analysisStreamError(err))
+		return
+	}
+
+	_ = stream.Send("analysis", gencore.CollectionAnalysis{
+		Id:         analysis.ID,
+		Result:     result,
+		Type:       analysis.Type,
+		CreatedAt:  analysis.CreatedAt,
+		Parameters: analysisParametersResponse(analysis.Parameters),
+		TemplateId: analysis.TemplateID,
+		Usage:      modelUsageResponse(analysis.Usage),
+		Reused:     &analysis.Reused,
 	})
 }
 
@@ -65,11 +137,21 @@ func (handler Handler) GetCollectionAnalyses(w http.ResponseWriter, r *http.Requ
 
 	result := gencore.CollectionAnalyses{}
 	for _, i := range analyses {
+		content, err := analysisResult(i.Type, i.Result)
+		if err != nil {
+			apiresponses.InternalError(w, "Internal Error", err)
+			return
+		}
+
 		result = append(result, gencore.CollectionAnalysis{
-			Id:        i.ID,
-			Type:      gencore.CollectionAnalysisType(i.Type),
-			Result:    string(i.Result),
-			CreatedAt: &i.CreatedAt,
+			Id:         i.ID,
+			Type:       i.Type,
+			Result:     content,
+			CreatedAt:  i.CreatedAt,
+			Parameters: analysisParametersResponse(i.Parameters),
+			TemplateId: i.TemplateID,
+			Usage:      modelUsageResponse(i.Usage),
+			Imported:   &i.Imported,
 		})
 	}
 
@@ -77,7 +159,27 @@ func (handler Handler) GetCollectionAnalyses(w http.ResponseWriter, r *http.Requ
 }
 
 func (handler Handler) GetAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, analysisID openapi_types.UUID) {
-	analysis, err := handler.Queries.GetAnalysis(r.Context(), analysisID)
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid Request", err)
+		return
+	}
+
+	analysis, err := handler.Queries.GetAnalysis(r.Context(), sqlgen.GetAnalysisParams{
+		ID:           analysisID,
+		CollectionID: id,
+		UserID:       *userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		apiresponses.Error(w, "Analysis not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	content, err := analysisResult(analysis.Type, analysis.Result)
 	if err != nil {
 		apiresponses.InternalError(w, "Internal Error", err)
 		return
@@ -85,10 +187,175 @@ func (handler Handler) GetAnalysis(w http.ResponseWriter, r *http.Request, id op
 
 	result := gencore.CollectionAnalysis{
 		Id:        analysis.ID,
-		Result:    string(analysis.Result),
-		Type:      gencore.CollectionAnalysisType(analysis.Type),
-		CreatedAt: &analysis.CreatedAt,
+		Result:    content,
+		Type:      analysis.Type,
+		CreatedAt: analysis.CreatedAt,
+		Imported:  &analysis.Imported,
+		Usage: gencore.ModelUsage{
+			Model:        analysis.Model,
+			PromptHash:   analysis.PromptHash,
+			InputTokens:  analysis.InputTokens,
+			OutputTokens: analysis.OutputTokens,
+			LatencyMs:    analysis.LatencyMs,
+			Requests:     int(analysis.Requests),
+		},
+	}
+
+	if analysis.TemplateID.Valid {
+		result.TemplateId = &analysis.TemplateID.UUID
+	}
+
+	// Stored with the same field names the API uses
+	if err := json.Unmarshal(analysis.Parameters, &result.Parameters); err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
 	}
 
 	apiresponses.Success(w, result)
 }
+
+func (h Handler) GetAnalysisKinds(w http.ResponseWriter, r *http.Request) {
+	kinds := h.Core.ListAnalysisKinds()
+
+	result := make(gencore.AnalysisKinds, 0, len(kinds))
+	for _, kind := range kinds {
+		result = append(result, gencore.AnalysisKind{
+			Name:        kind.Name,
+			Description: kind.Description,
+			Schema:      kind.Schema,
+		})
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// analysisResult is an analysis' result as served, which leaves a quiz's answers out
+func analysisResult(analysisType string, result json.RawMessage) (string, error) {
+	hidden, err := core.HideQuizAnswers(analysisType, result)
+	if err != nil {
+		return "", err
+	}
+	return string(hidden), nil
+}
+
+// analysisRequest maps the API request onto the core analysis request
+func analysisRequest(req *gencore.AnalyzeCollectionRequest) core.AnalysisRequest {
+	request := core.AnalysisRequest{
+		Kind: req.Type,
+	}
+
+	if req.Provider != nil {
+		request.Provider = string(*req.Provider)
+	}
+	if req.Parameters != nil {
+		request.Parameters = analysisParameters(*req.Parameters)
+	}
+	if req.Regenerate != nil {
+		request.Regenerate = *req.Regenerate
+	}
+
+	return request
+}
+
+// analysisParameters maps API analysis parameters onto core parameters
+func analysisParameters(params gencore.AnalysisParameters) core.AnalysisParameters {
+	var result core.AnalysisParameters
+
+	if params.Count != nil {
+		result.Count = *params.Count
+	}
+	if params.Difficulty != nil {
+		result.Difficulty = string(*params.Difficulty)
+	}
+	if params.Audience != nil {
+		result.Audience = string(*params.Audience)
+	}
+	if params.Length != nil {
+		result.Length = string(*params.Length)
+	}
+	if params.FocusTopics != nil {
+		result.FocusTopics = *params.FocusTopics
+	}
+
+	return result
+}
+
+// analysisParametersResponse maps core analysis parameters onto the API, leaving unset ones out
+func analysisParametersResponse(params core.AnalysisParameters) gencore.AnalysisParameters {
+	var result gencore.AnalysisParameters
+
+	if params.Count > 0 {
+		result.Count = &params.Count
+	}
+	if params.Difficulty != "" {
+		difficulty := gencore.AnalysisParametersDifficulty(params.Difficulty)
+		result.Difficulty = &difficulty
+	}
+	if params.Audience != "" {
+		audience := gencore.AnalysisParametersAudience(params.Audience)
+		result.Audience = &audience
+	}
+	if params.Length != "" {
+		length := gencore.AnalysisParametersLength(params.Length)
+		result.Length = &length
+	}
+	if len(params.FocusTopics) > 0 {
+		result.FocusTopics = &params.FocusTopics
+	}
+
+	return result
+}
+
+func modelUsageResponse(usage llm.Usage) gencore.ModelUsage {
+	return gencore.ModelUsage{
+		Model:        usage.Model,
+		PromptHash:   usage.PromptHash,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		LatencyMs:    usage.Latency.Milliseconds(),
+		Requests:     usage.Requests,
+	}
+}
+
+// analysisError writes the response for a failed analysis
+func analysisError(w http.ResponseWriter, err error) {
+	var validationErr *core.AnalysisValidationError
+	var quotaErr *core.QuotaExceededError
+	switch {
+	case errors.Is(err, llm.ErrUnknownProvider):
+		apiresponses.BadRequest(w, "Unknown provider", err)
+	case errors.Is(err, core.ErrUnknownAnalysisKind):
+		apiresponses.BadRequest(w, "Unknown analysis type", err)
+	case errors.Is(err, core.ErrInvalidAnalysisParameters):
+		apiresponses.BadRequest(w, "Invalid analysis parameters", err)
+	case errors.As(err, &quotaErr):
+		quotaExceeded(w, quotaErr)
+	case errors.As(err, &validationErr):
+		apiresponses.ErrorWithBody(w, gencore.AnalysisValidationError{
+			Message:    "Analysis output failed schema validation",
+			Type:       string(validationErr.Kind),
+			Attempts:   validationErr.Attempts,
+			Violations: validationErr.Violations,
+		}, http.StatusBadGateway)
+	default:
+		apiresponses.InternalError(w, "Internal Error", err)
+	}
+}
+
+// analysisStreamError describes a failed analysis as the payload of an error event
+func analysisStreamError(err error) gencore.AnalysisStreamError {
+	var validationErr *core.AnalysisValidationError
+	if errors.As(err, &validationErr) {
+		kind := string(validationErr.Kind)
+		return gencore.AnalysisStreamError{
+			Message:    "Analysis output failed schema validation",
+			Type:       &kind,
+			Attempts:   &validationErr.Attempts,
+			Violations: &validationErr.Violations,
+		}
+	}
+
+	return gencore.AnalysisStreamError{
+		Message: "Analysis failed",
+	}
+}
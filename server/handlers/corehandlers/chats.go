@@ -0,0 +1,242 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/api/logging"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (POST /core/collection/{id}/chats)
+func (handler Handler) CreateCollectionChat(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	chat, err := handler.Core.CreateCollectionChat(r.Context(), *userID, id)
+	if err != nil {
+		chatError(w, err)
+		return
+	}
+
+	apiresponses.Success(w, chatResponse(chat))
+}
+
+// (GET /core/collection/{id}/chats)
+func (handler Handler) GetCollectionChats(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	chats, err := handler.Core.GetCollectionChats(r.Context(), *userID, id)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, chatsResponse(chats))
+}
+
+// (POST /core/course/{courseID}/chats)
+func (handler Handler) CreateCourseChat(w http.ResponseWriter, r *http.Request, courseID string) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	chat, err := handler.Core.CreateCourseChat(r.Context(), *userID, courseID)
+	if err != nil {
+		chatError(w, err)
+		return
+	}
+
+	apiresponses.Success(w, chatResponse(chat))
+}
+
+// (GET /core/course/{courseID}/chats)
+func (handler Handler) GetCourseChats(w http.ResponseWriter, r *http.Request, courseID string) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	chats, err := handler.Core.GetCourseChats(r.Context(), *userID, courseID)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, chatsResponse(chats))
+}
+
+// (GET /core/chat/{chatID})
+func (handler Handler) GetChat(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	chat, err := handler.Core.GetChat(r.Context(), *userID, chatID)
+	if err != nil {
+		chatError(w, err)
+		return
+	}
+
+	apiresponses.Success(w, chatResponse(chat))
+}
+
+// (DELETE /core/chat/{chatID})
+func (handler Handler) DeleteChat(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	if err := handler.Core.DeleteChat(r.Context(), *userID, chatID); err != nil {
+		chatError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// (POST /core/chat/{chatID}/messages)
+func (handler Handler) SendChatMessage(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	req, err := apirequests.Request[gencore.SendChatMessageRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	message, err := handler.Core.SendChatMessage(r.Context(), *userID, chatID, chatRequest(req))
+	if err != nil {
+		chatError(w, err)
+		return
+	}
+
+	apiresponses.Success(w, chatMessageResponse(*message))
+}
+
+// (POST /core/chat/{chatID}/messages/stream)
+func (handler Handler) StreamChatMessage(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	req, err := apirequests.Request[gencore.SendChatMessageRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	stream := apiresponses.NewEventStream(w)
+	request := chatRequest(req)
+	request.OnDelta = func(delta string) {
+		if err := stream.Send("token", gencore.ChatTokenEvent{Delta: delta}); err != nil {
+			logging.Error(err, "failed to send chat token", nil)
+		}
+	}
+
+	message, err := handler.Core.SendChatMessage(r.Context(), *userID, chatID, request)
+	if err != nil {
+		// Nothing has been streamed yet, so a regular status code can still be sent
+		if !stream.Started() {
+			chatError(w, err)
+			return
+		}
+
+		logging.Error(err, "streamed chat answer failed", map[string]interface{}{
+			"chat_id": chatID,
+		})
+		_ = stream.Send("error", gencore.ChatStreamError{Message: "Answering failed"})
+		return
+	}
+
+	_ = stream.Send("message", chatMessageResponse(*message))
+}
+
+func chatRequest(req *gencore.SendChatMessageRequest) core.ChatRequest {
+	request := core.ChatRequest{Content: req.Content}
+	if req.Provider != nil {
+		request.Provider = string(*req.Provider)
+	}
+	return request
+}
+
+func chatError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, core.ErrCollectionNotFound):
+		apiresponses.Error(w, "Collection not found", http.StatusNotFound)
+	case errors.Is(err, core.ErrCourseNotFound):
+		apiresponses.Error(w, "Course not found", http.StatusNotFound)
+	case errors.Is(err, core.ErrChatNotFound):
+		apiresponses.Error(w, "Chat not found", http.StatusNotFound)
+	case errors.Is(err, core.ErrNoChatMaterial):
+		apiresponses.Error(w, "None of the chat's documents have been extracted", http.StatusNotFound)
+	case errors.Is(err, core.ErrInvalidChatMessage):
+		apiresponses.BadRequest(w, err.Error(), err)
+	default:
+		analysisError(w, err)
+	}
+}
+
+func chatsResponse(chats []core.Chat) gencore.Chats {
+	result := make(gencore.Chats, 0, len(chats))
+	for i := range chats {
+		result = append(result, chatResponse(&chats[i]))
+	}
+	return result
+}
+
+func chatResponse(chat *core.Chat) gencore.Chat {
+	result := gencore.Chat{
+		Id:           chat.ID,
+		CollectionId: chat.CollectionID,
+		Title:        chat.Title,
+		CreatedAt:    chat.CreatedAt,
+		UpdatedAt:    chat.UpdatedAt,
+	}
+	if chat.Course != "" {
+		result.Course = &chat.Course
+	}
+	if chat.Messages != nil {
+		messages := make([]gencore.ChatMessage, 0, len(chat.Messages))
+		for _, message := range chat.Messages {
+			messages = append(messages, chatMessageResponse(message))
+		}
+		result.Messages = &messages
+	}
+	return result
+}
+
+func chatMessageResponse(message core.ChatMessage) gencore.ChatMessage {
+	return gencore.ChatMessage{
+		Id:        message.ID,
+		Role:      gencore.ChatMessageRole(message.Role),
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+		Sources:   sourcesResponse(message.Sources),
+		Usage:     modelUsageResponse(message.Usage),
+	}
+}
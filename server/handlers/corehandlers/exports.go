@@ -0,0 +1,102 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (GET /core/analysis/{analysisID}/export)
+func (handler Handler) ExportFlashcards(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID, params gencore.ExportFlashcardsParams) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	export, err := handler.Core.ExportFlashcards(r.Context(), *userID, analysisID, exportFormat(params.Format))
+	switch {
+	case errors.Is(err, core.ErrInvalidExportFormat):
+		apiresponses.BadRequest(w, "Invalid export format", err)
+		return
+	case errors.Is(err, core.ErrAnalysisNotFound):
+		apiresponses.Error(w, "Analysis not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrNotFlashcards):
+		apiresponses.BadRequest(w, "Only flashcard analyses can be exported", err)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.File(w, export.Filename, export.ContentType, export.Data)
+}
+
+// (GET /core/course/{courseID}/flashcards/export)
+func (handler Handler) ExportCourseFlashcards(w http.ResponseWriter, r *http.Request, courseID string, params gencore.ExportCourseFlashcardsParams) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	export, err := handler.Core.ExportCourseFlashcards(r.Context(), *userID, courseID, exportFormat(params.Format))
+	switch {
+	case errors.Is(err, core.ErrInvalidExportFormat):
+		apiresponses.BadRequest(w, "Invalid export format", err)
+		return
+	case errors.Is(err, core.ErrNoFlashcards):
+		apiresponses.Error(w, "The course has no flashcards", http.StatusNotFound)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.File(w, export.Filename, export.ContentType, export.Data)
+}
+
+// (GET /core/collection/{id}/study-guide)
+func (handler Handler) ExportStudyGuide(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params gencore.ExportStudyGuideParams) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	format := core.StudyGuidePDF
+	if params.Format != nil {
+		format = core.StudyGuideFormat(*params.Format)
+	}
+
+	export, err := handler.Core.ExportStudyGuide(r.Context(), *userID, id, format)
+	switch {
+	case errors.Is(err, core.ErrInvalidExportFormat):
+		apiresponses.BadRequest(w, "Invalid export format", err)
+		return
+	case errors.Is(err, core.ErrCollectionNotFound):
+		apiresponses.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrNoStudyGuide):
+		apiresponses.Error(w, "The collection has no summary or deep summary yet", http.StatusNotFound)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.File(w, export.Filename, export.ContentType, export.Data)
+}
+
+func exportFormat(format *gencore.FlashcardExportFormat) core.ExportFormat {
+	if format == nil {
+		return core.ExportApkg
+	}
+	return core.ExportFormat(*format)
+}
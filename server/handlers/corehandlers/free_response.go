@@ -0,0 +1,77 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (POST /core/analysis-item/{itemID}/grade)
+func (handler Handler) GradeFreeResponse(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	req, err := apirequests.Request[gencore.GradeFreeResponseRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	var provider string
+	if req.Provider != nil {
+		provider = string(*req.Provider)
+	}
+
+	grade, err := handler.Core.GradeFreeResponse(r.Context(), *userID, itemID, req.Answer, provider)
+	switch {
+	case errors.Is(err, core.ErrAnalysisItemNotFound):
+		apiresponses.Error(w, "Item not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrNoGradingMaterial):
+		apiresponses.Error(w, "The collection has no extracted content to grade against", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrNotFreeResponse):
+		apiresponses.BadRequest(w, "Only free-response questions can be graded", err)
+		return
+	case errors.Is(err, core.ErrInvalidAnswer):
+		apiresponses.BadRequest(w, err.Error(), err)
+		return
+	case err != nil:
+		analysisError(w, err)
+		return
+	}
+
+	apiresponses.Success(w, gencore.FreeResponseGrade{
+		ItemId:          grade.ItemID,
+		Question:        grade.Question,
+		ReferenceAnswer: grade.ReferenceAnswer,
+		Score:           grade.Score,
+		MaxScore:        grade.MaxScore,
+		Hit:             rubricCriterionGradesResponse(grade.Hit),
+		Missed:          rubricCriterionGradesResponse(grade.Missed),
+		Feedback:        grade.Feedback,
+		Sources:         sourcesResponse(grade.Sources),
+		Usage:           modelUsageResponse(grade.Usage),
+	})
+}
+
+func rubricCriterionGradesResponse(grades []core.RubricCriterionGrade) []gencore.RubricCriterionGrade {
+	result := make([]gencore.RubricCriterionGrade, 0, len(grades))
+	for _, grade := range grades {
+		result = append(result, gencore.RubricCriterionGrade{
+			Criterion:   grade.Criterion,
+			Points:      grade.Points,
+			Awarded:     grade.Awarded,
+			Explanation: grade.Explanation,
+		})
+	}
+	return result
+}
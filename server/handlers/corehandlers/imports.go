@@ -0,0 +1,64 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (POST /core/collection/{id}/import)
+func (handler Handler) ImportAnalysisItems(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params gencore.ImportAnalysisItemsParams) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	data, err := apirequests.Body(r, core.MaxImportSize)
+	if errors.Is(err, apirequests.ErrBodyTooLarge) {
+		apiresponses.Error(w, "The file is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	result, err := handler.Core.ImportItems(r.Context(), *userID, id, string(params.Kind), core.ImportFormat(params.Format), data)
+	switch {
+	case errors.Is(err, core.ErrNotImportable):
+		apiresponses.BadRequest(w, "Only flashcards and quizzes can be imported", err)
+		return
+	case errors.Is(err, core.ErrInvalidImportFormat):
+		apiresponses.BadRequest(w, "Invalid import format", err)
+		return
+	case errors.Is(err, core.ErrInvalidImport):
+		apiresponses.BadRequest(w, "The file can't be read", err)
+		return
+	case errors.Is(err, core.ErrCollectionNotFound):
+		apiresponses.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	problems := result.Problems
+	if problems == nil {
+		problems = []string{}
+	}
+
+	apiresponses.Success(w, gencore.ImportResult{
+		AnalysisId: result.AnalysisID,
+		Created:    result.Created,
+		Updated:    result.Updated,
+		Unchanged:  result.Unchanged,
+		Skipped:    result.Skipped,
+		Problems:   problems,
+	})
+}
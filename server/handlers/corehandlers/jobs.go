@@ -0,0 +1,86 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/api/tools/features/llm"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (POST /core/collection/{id}/analysis-jobs)
+func (handler Handler) EnqueueCollectionAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid Request", err)
+		return
+	}
+
+	req, err := apirequests.Request[gencore.AnalyzeCollectionRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid Request", err)
+		return
+	}
+
+	job, err := handler.Core.EnqueueAnalysis(r.Context(), *userID, id, analysisRequest(req))
+	if errors.Is(err, llm.ErrUnknownProvider) {
+		apiresponses.BadRequest(w, "Unknown provider", err)
+		return
+	}
+	if errors.Is(err, core.ErrUnknownAnalysisKind) {
+		apiresponses.BadRequest(w, "Unknown analysis type", err)
+		return
+	}
+	if errors.Is(err, core.ErrInvalidAnalysisParameters) {
+		apiresponses.BadRequest(w, "Invalid analysis parameters", err)
+		return
+	}
+	var quotaErr *core.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		quotaExceeded(w, quotaErr)
+		return
+	}
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Accepted(w, analysisJobResponse(job))
+}
+
+// (GET /core/analysis-job/{jobID})
+func (handler Handler) GetAnalysisJob(w http.ResponseWriter, r *http.Request, jobID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid Request", err)
+		return
+	}
+
+	job, err := handler.Core.GetAnalysisJob(r.Context(), *userID, jobID)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, analysisJobResponse(job))
+}
+
+func analysisJobResponse(job *core.AnalysisJob) gencore.AnalysisJob {
+	return gencore.AnalysisJob{
+		Id:           job.ID,
+		CollectionID: job.CollectionID,
+		Type:         job.Type,
+		Status:       gencore.AnalysisJobStatus(job.Status),
+		Provider:     job.Provider,
+		AnalysisID:   job.AnalysisID,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		UpdatedAt:    job.UpdatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
@@ -0,0 +1,95 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+)
+
+// (GET /core/course/{courseID}/prompt-templates)
+func (handler Handler) GetPromptTemplates(w http.ResponseWriter, r *http.Request, courseID string) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	templates, err := handler.Core.GetPromptTemplates(r.Context(), *userID, courseID)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	result := make(gencore.PromptTemplates, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, promptTemplateResponse(&template))
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (POST /core/course/{courseID}/prompt-templates)
+func (handler Handler) SavePromptTemplate(w http.ResponseWriter, r *http.Request, courseID string) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	request, err := apirequests.Request[gencore.SavePromptTemplateRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	template, err := handler.Core.SavePromptTemplate(r.Context(), *userID, courseID, request.Kind, request.Template)
+	switch {
+	case errors.Is(err, core.ErrCourseNotFound):
+		apiresponses.Error(w, "Course not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrUnknownAnalysisKind):
+		apiresponses.BadRequest(w, "Unknown analysis type", err)
+		return
+	case errors.Is(err, core.ErrInvalidPromptTemplate):
+		// The message says what's wrong with the template, so it's worth showing
+		apiresponses.BadRequest(w, err.Error(), err)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, promptTemplateResponse(template))
+}
+
+// (DELETE /core/course/{courseID}/prompt-templates/{kind})
+func (handler Handler) RetirePromptTemplates(w http.ResponseWriter, r *http.Request, courseID string, kind gencore.AnalysisKindName) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	if err := handler.Core.RetirePromptTemplates(r.Context(), *userID, courseID, kind); err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func promptTemplateResponse(template *core.PromptTemplate) gencore.PromptTemplate {
+	return gencore.PromptTemplate{
+		Id:        template.ID,
+		Course:    template.Course,
+		Kind:      template.Kind,
+		Version:   template.Version,
+		Template:  template.Body,
+		Active:    template.Active,
+		CreatedAt: template.CreatedAt,
+		RetiredAt: template.RetiredAt,
+	}
+}
@@ -0,0 +1,193 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (POST /core/analysis/{analysisID}/quiz-attempts)
+func (handler Handler) StartQuizAttempt(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	attempt, err := handler.Core.StartQuizAttempt(r.Context(), *userID, analysisID)
+	switch {
+	case errors.Is(err, core.ErrAnalysisNotFound):
+		apiresponses.Error(w, "Analysis not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrNotQuiz):
+		apiresponses.BadRequest(w, "Only quiz analyses can be attempted", err)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, quizAttemptResponse(attempt))
+}
+
+// (GET /core/analysis/{analysisID}/quiz-attempts)
+func (handler Handler) GetAnalysisQuizAttempts(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	attempts, err := handler.Core.GetQuizAttemptsByAnalysis(r.Context(), *userID, analysisID)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, quizAttemptsResponse(attempts))
+}
+
+// (GET /core/quiz-attempt/{attemptID})
+func (handler Handler) GetQuizAttempt(w http.ResponseWriter, r *http.Request, attemptID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	attempt, err := handler.Core.GetQuizAttempt(r.Context(), *userID, attemptID)
+	switch {
+	case errors.Is(err, core.ErrQuizAttemptNotFound):
+		apiresponses.Error(w, "Attempt not found", http.StatusNotFound)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, quizAttemptResponse(attempt))
+}
+
+// (POST /core/quiz-attempt/{attemptID}/submit)
+func (handler Handler) SubmitQuizAttempt(w http.ResponseWriter, r *http.Request, attemptID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	request, err := apirequests.Request[gencore.SubmitQuizAttemptRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	answers := make([]core.QuizAnswer, 0, len(request.Answers))
+	for _, answer := range request.Answers {
+		quizAnswer := core.QuizAnswer{
+			Index:    answer.Index,
+			Selected: answer.SelectedIndex,
+		}
+		if answer.TimeMs != nil {
+			quizAnswer.Time = time.Duration(*answer.TimeMs) * time.Millisecond
+		}
+		answers = append(answers, quizAnswer)
+	}
+
+	attempt, err := handler.Core.SubmitQuizAttempt(r.Context(), *userID, attemptID, answers)
+	switch {
+	case errors.Is(err, core.ErrQuizAttemptNotFound):
+		apiresponses.Error(w, "Attempt not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrQuizAttemptSubmitted):
+		apiresponses.Error(w, "Attempt already submitted", http.StatusConflict)
+		return
+	case errors.Is(err, core.ErrInvalidQuizAnswers):
+		apiresponses.BadRequest(w, err.Error(), err)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, quizAttemptResponse(attempt))
+}
+
+// (GET /core/course/{courseID}/quiz-attempts)
+func (handler Handler) GetCourseQuizAttempts(w http.ResponseWriter, r *http.Request, courseID string) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	attempts, err := handler.Core.GetQuizAttemptsByCourse(r.Context(), *userID, courseID)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, quizAttemptsResponse(attempts))
+}
+
+func quizAttemptsResponse(attempts []core.QuizAttempt) gencore.QuizAttempts {
+	result := make(gencore.QuizAttempts, 0, len(attempts))
+	for _, attempt := range attempts {
+		result = append(result, quizAttemptResponse(&attempt))
+	}
+	return result
+}
+
+func quizAttemptResponse(attempt *core.QuizAttempt) gencore.QuizAttempt {
+	result := gencore.QuizAttempt{
+		Id:              attempt.ID,
+		AnalysisId:      attempt.AnalysisID,
+		CollectionId:    attempt.CollectionID,
+		CollectionTitle: attempt.CollectionTitle,
+		Course:          attempt.Course,
+		StartedAt:       attempt.StartedAt,
+		SubmittedAt:     attempt.SubmittedAt,
+	}
+
+	if attempt.SubmittedAt != nil {
+		durationMs := attempt.Duration.Milliseconds()
+		result.Score = &attempt.Score
+		result.Total = &attempt.Total
+		result.DurationMs = &durationMs
+	}
+
+	if attempt.Questions != nil {
+		questions := make([]gencore.QuizQuestion, 0, len(attempt.Questions))
+		for _, question := range attempt.Questions {
+			questions = append(questions, gencore.QuizQuestion{
+				Index:    question.Index,
+				Question: question.Question,
+				Options:  question.Options,
+			})
+		}
+		result.Questions = &questions
+	}
+
+	if attempt.Results != nil {
+		results := make([]gencore.QuizAnswerResult, 0, len(attempt.Results))
+		for _, answer := range attempt.Results {
+			results = append(results, gencore.QuizAnswerResult{
+				Index:         answer.Index,
+				Question:      answer.Question,
+				SelectedIndex: answer.Selected,
+				CorrectIndex:  answer.CorrectIndex,
+				Correct:       answer.Correct,
+				TimeMs:        answer.Time.Milliseconds(),
+			})
+		}
+		result.Results = &results
+	}
+
+	return result
+}
@@ -0,0 +1,145 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// (POST /core/analysis/{analysisID}/review-cards)
+func (handler Handler) EnrollReviewCards(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	cards, err := handler.Core.EnrollFlashcards(r.Context(), *userID, analysisID)
+	switch {
+	case errors.Is(err, core.ErrAnalysisNotFound):
+		apiresponses.Error(w, "Analysis not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrNotFlashcards):
+		apiresponses.BadRequest(w, "Only flashcard analyses can be reviewed", err)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	result := make(gencore.ReviewCards, 0, len(cards))
+	for _, card := range cards {
+		result = append(result, reviewCardResponse(&card))
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (GET /core/reviews/due)
+func (handler Handler) GetDueReviews(w http.ResponseWriter, r *http.Request, params gencore.GetDueReviewsParams) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	filter := core.ReviewQueueFilter{
+		CollectionID: params.CollectionId,
+	}
+	if params.Course != nil {
+		filter.Course = *params.Course
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	if params.Timezone != nil {
+		filter.Timezone = *params.Timezone
+	}
+
+	queue, err := handler.Core.GetDueReviews(r.Context(), *userID, filter)
+	switch {
+	case errors.Is(err, core.ErrInvalidReviewFilter):
+		apiresponses.BadRequest(w, err.Error(), err)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	result := gencore.ReviewQueue{
+		Cards: make(gencore.ReviewCards, 0, len(queue.Cards)),
+		Due:   queue.Due,
+	}
+	for _, card := range queue.Cards {
+		result.Cards = append(result.Cards, reviewCardResponse(&card))
+	}
+
+	apiresponses.Success(w, result)
+}
+
+// (POST /core/review-card/{cardID}/grade)
+func (handler Handler) GradeReviewCard(w http.ResponseWriter, r *http.Request, cardID openapi_types.UUID) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	request, err := apirequests.Request[gencore.GradeReviewCardRequest](r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request body", err)
+		return
+	}
+
+	card, err := handler.Core.GradeReviewCard(r.Context(), *userID, cardID, request.Grade)
+	switch {
+	case errors.Is(err, core.ErrInvalidReviewGrade):
+		apiresponses.BadRequest(w, err.Error(), err)
+		return
+	case errors.Is(err, core.ErrReviewCardNotFound):
+		apiresponses.Error(w, "Card not found", http.StatusNotFound)
+		return
+	case err != nil:
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, reviewCardResponse(card))
+}
+
+func reviewCardResponse(card *core.ReviewCard) gencore.ReviewCard {
+	return gencore.ReviewCard{
+		Id:              card.ID,
+		AnalysisId:      card.AnalysisID,
+		ItemId:          card.ItemID,
+		Position:        card.Position,
+		CollectionId:    card.CollectionID,
+		CollectionTitle: card.CollectionTitle,
+		Course:          card.Course,
+		Question:        card.Question,
+		Answer:          card.Answer,
+		Sources:         sourcesResponse(card.Sources),
+		Repetitions:     card.Repetitions,
+		IntervalDays:    card.IntervalDays,
+		EaseFactor:      card.EaseFactor,
+		DueAt:           card.DueAt,
+		LastReviewedAt:  card.LastReviewedAt,
+	}
+}
+
+func sourcesResponse(sources []core.Source) []gencore.Source {
+	result := make([]gencore.Source, 0, len(sources))
+	for _, source := range sources {
+		result = append(result, gencore.Source{
+			DocumentId: source.DocumentID,
+			Title:      source.Title,
+			Page:       source.Page,
+		})
+	}
+	return result
+}
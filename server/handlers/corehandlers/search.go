@@ -0,0 +1,60 @@
+package corehandlers
+
+import (
+	"errors"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+)
+
+// (GET /core/search)
+func (handler Handler) Search(w http.ResponseWriter, r *http.Request, params gencore.SearchParams) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	filter := core.SearchFilter{
+		CollectionID: params.CollectionId,
+	}
+	if params.Course != nil {
+		filter.Course = *params.Course
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+
+	results, err := handler.Core.Search(r.Context(), *userID, params.Q, filter)
+	switch {
+	case errors.Is(err, core.ErrInvalidSearch):
+		apiresponses.BadRequest(w, err.Error(), err)
+		return
+	case err != nil:
+		analysisError(w, err)
+		return
+	}
+
+	response := gencore.SearchResults{
+		Query:   results.Query,
+		Results: make([]gencore.SearchResult, 0, len(results.Results)),
+		Usage:   modelUsageResponse(results.Usage),
+	}
+	for _, result := range results.Results {
+		response.Results = append(response.Results, gencore.SearchResult{
+			SegmentId:       result.SegmentID,
+			Content:         result.Content,
+			Score:           result.Score,
+			DocumentId:      result.DocumentID,
+			DocumentTitle:   result.DocumentTitle,
+			Page:            result.Page,
+			CollectionId:    result.CollectionID,
+			CollectionTitle: result.CollectionTitle,
+			Course:          result.Course,
+		})
+	}
+
+	apiresponses.Success(w, response)
+}
@@ -0,0 +1,74 @@
+package corehandlers
+
+import (
+	"math"
+	"net/http"
+	"server/api/apirequests"
+	"server/api/apiresponses"
+	"server/business/core"
+	"server/handlers/generated/gencore"
+	"strconv"
+	"time"
+)
+
+// (GET /core/usage)
+func (handler Handler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	userID, err := apirequests.User(r)
+	if err != nil {
+		apiresponses.BadRequest(w, "Invalid request", err)
+		return
+	}
+
+	usage, err := handler.Core.GetUsage(r.Context(), *userID)
+	if err != nil {
+		apiresponses.InternalError(w, "Internal Error", err)
+		return
+	}
+
+	apiresponses.Success(w, gencore.UsageSummary{
+		Day:   usagePeriodResponse(usage.Day),
+		Month: usagePeriodResponse(usage.Month),
+	})
+}
+
+// quotaExceeded writes the 429 for a used-up quota, telling the client when to retry
+func quotaExceeded(w http.ResponseWriter, err *core.QuotaExceededError) {
+	retryAfter := math.Ceil(time.Until(err.ResetsAt).Seconds())
+	w.Header().Set("Retry-After", strconv.Itoa(max(int(retryAfter), 1)))
+
+	apiresponses.ErrorWithBody(w, gencore.QuotaExceededError{
+		Message:  "Usage quota exceeded",
+		Period:   gencore.QuotaExceededErrorPeriod(err.Period),
+		Metric:   gencore.QuotaExceededErrorMetric(err.Metric),
+		Limit:    err.Limit,
+		Used:     err.Used,
+		ResetsAt: err.ResetsAt,
+	}, http.StatusTooManyRequests)
+}
+
+func usagePeriodResponse(period core.UsagePeriod) gencore.UsagePeriod {
+	result := gencore.UsagePeriod{
+		Start:      period.Start,
+		ResetsAt:   period.ResetsAt,
+		Used:       usageTotalsResponse(period.Used),
+		Extraction: usageTotalsResponse(period.Extraction),
+		Analysis:   usageTotalsResponse(period.Analysis),
+		Embedding:  usageTotalsResponse(period.Embedding),
+	}
+
+	if period.TokenLimit > 0 {
+		result.TokenLimit = &period.TokenLimit
+	}
+	if period.RequestLimit > 0 {
+		result.RequestLimit = &period.RequestLimit
+	}
+
+	return result
+}
+
+func usageTotalsResponse(totals core.UsageTotals) gencore.UsageTotals {
+	return gencore.UsageTotals{
+		Requests: totals.Requests,
+		Tokens:   totals.Tokens,
+	}
+}
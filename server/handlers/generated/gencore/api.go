@@ -13,29 +13,311 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
-// Defines values for AnalyzeCollectionRequestType.
+// Defines values for AnalysisJobStatus.
 const (
-	AnalyzeCollectionRequestTypeDeepSummary AnalyzeCollectionRequestType = "deep_summary"
-	AnalyzeCollectionRequestTypeFlashcards  AnalyzeCollectionRequestType = "flashcards"
-	AnalyzeCollectionRequestTypeQuiz        AnalyzeCollectionRequestType = "quiz"
-	AnalyzeCollectionRequestTypeSummary     AnalyzeCollectionRequestType = "summary"
+	AnalysisJobStatusAnalyzing  AnalysisJobStatus = "analyzing"
+	AnalysisJobStatusExtracting AnalysisJobStatus = "extracting"
+	AnalysisJobStatusFailed     AnalysisJobStatus = "failed"
+	AnalysisJobStatusQueued     AnalysisJobStatus = "queued"
+	AnalysisJobStatusSucceeded  AnalysisJobStatus = "succeeded"
 )
 
-// Defines values for CollectionAnalysisType.
+// Defines values for AnalysisParametersAudience.
 const (
-	CollectionAnalysisTypeDeepSummary CollectionAnalysisType = "deep_summary"
-	CollectionAnalysisTypeFlashcards  CollectionAnalysisType = "flashcards"
-	CollectionAnalysisTypeQuiz        CollectionAnalysisType = "quiz"
-	CollectionAnalysisTypeSummary     CollectionAnalysisType = "summary"
+	Graduate      AnalysisParametersAudience = "graduate"
+	Intro         AnalysisParametersAudience = "intro"
+	Undergraduate AnalysisParametersAudience = "undergraduate"
 )
 
+// Defines values for AnalysisParametersDifficulty.
+const (
+	AnalysisParametersDifficultyEasy   AnalysisParametersDifficulty = "easy"
+	AnalysisParametersDifficultyHard   AnalysisParametersDifficulty = "hard"
+	AnalysisParametersDifficultyMedium AnalysisParametersDifficulty = "medium"
+)
+
+// Defines values for AnalysisParametersLength.
+const (
+	AnalysisParametersLengthLong   AnalysisParametersLength = "long"
+	AnalysisParametersLengthMedium AnalysisParametersLength = "medium"
+	AnalysisParametersLengthShort  AnalysisParametersLength = "short"
+)
+
+// Defines values for AnalysisStatusEventStatus.
+const (
+	AnalysisStatusEventStatusAnalyzing  AnalysisStatusEventStatus = "analyzing"
+	AnalysisStatusEventStatusExtracting AnalysisStatusEventStatus = "extracting"
+)
+
+// Defines values for ChatMessageRole.
+const (
+	Assistant ChatMessageRole = "assistant"
+	User      ChatMessageRole = "user"
+)
+
+// Defines values for FlashcardExportFormat.
+const (
+	FlashcardExportFormatApkg FlashcardExportFormat = "apkg"
+	FlashcardExportFormatCsv  FlashcardExportFormat = "csv"
+	FlashcardExportFormatTsv  FlashcardExportFormat = "tsv"
+)
+
+// Defines values for ImportFormat.
+const (
+	ImportFormatApkg    ImportFormat = "apkg"
+	ImportFormatCsv     ImportFormat = "csv"
+	ImportFormatQuizlet ImportFormat = "quizlet"
+	ImportFormatTsv     ImportFormat = "tsv"
+)
+
+// Defines values for LLMProvider.
+const (
+	Fake   LLMProvider = "fake"
+	Gemini LLMProvider = "gemini"
+	Openai LLMProvider = "openai"
+)
+
+// Defines values for QuotaExceededErrorMetric.
+const (
+	Requests QuotaExceededErrorMetric = "requests"
+	Tokens   QuotaExceededErrorMetric = "tokens"
+)
+
+// Defines values for QuotaExceededErrorPeriod.
+const (
+	Day   QuotaExceededErrorPeriod = "day"
+	Month QuotaExceededErrorPeriod = "month"
+)
+
+// Defines values for StudyGuideFormat.
+const (
+	Latex    StudyGuideFormat = "latex"
+	Markdown StudyGuideFormat = "markdown"
+	Pdf      StudyGuideFormat = "pdf"
+)
+
+// Defines values for ImportAnalysisItemsParamsKind.
+const (
+	Flashcards ImportAnalysisItemsParamsKind = "flashcards"
+	Quiz       ImportAnalysisItemsParamsKind = "quiz"
+)
+
+// AnalysisDocumentEvent defines model for AnalysisDocumentEvent.
+type AnalysisDocumentEvent struct {
+	// Cached The document's text had already been extracted
+	Cached bool `json:"cached"`
+
+	// Completed Documents finished so far. Documents are extracted concurrently and finish out of order.
+	Completed  int                `json:"completed"`
+	DocumentId openapi_types.UUID `json:"documentId"`
+	Index      int                `json:"index"`
+	Total      int                `json:"total"`
+}
+
+// AnalysisItem One item of a list-shaped analysis, such as a flashcard or quiz question
+type AnalysisItem struct {
+	AnalysisId openapi_types.UUID `json:"analysisId"`
+
+	// Content The item as it stands, following the analysis kind's item schema. Quiz questions keep their
+	// correct_index, since only the analysis' owner manages its items.
+	Content   map[string]interface{} `json:"content"`
+	CreatedAt time.Time              `json:"createdAt"`
+
+	// Edited Content is the user's rather than the model's
+	Edited bool `json:"edited"`
+
+	// Generated The model's original item. Unset for items added by hand.
+	Generated *map[string]interface{} `json:"generated,omitempty"`
+	Id        openapi_types.UUID      `json:"id"`
+	Position  int                     `json:"position"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+// AnalysisItemRequest defines model for AnalysisItemRequest.
+type AnalysisItemRequest struct {
+	Content map[string]interface{} `json:"content"`
+}
+
+// AnalysisItems defines model for AnalysisItems.
+type AnalysisItems = []AnalysisItem
+
+// AnalysisJob defines model for AnalysisJob.
+type AnalysisJob struct {
+	AnalysisID   *openapi_types.UUID `json:"analysisID,omitempty"`
+	CollectionID openapi_types.UUID  `json:"collectionID"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	Error        *string             `json:"error,omitempty"`
+	FinishedAt   *time.Time          `json:"finishedAt,omitempty"`
+	Id           openapi_types.UUID  `json:"id"`
+	Provider     string              `json:"provider"`
+	StartedAt    *time.Time          `json:"startedAt,omitempty"`
+	Status       AnalysisJobStatus   `json:"status"`
+
+	// Type Name of a registered analysis kind. GET /core/analysis-kinds lists them;
+	// the built-in kinds are summary, flashcards, quiz, free_response, deep_summary, glossary, timeline
+	// and concept_map.
+	Type      AnalysisKindName `json:"type"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// AnalysisJobStatus defines model for AnalysisJob.Status.
+type AnalysisJobStatus string
+
+// AnalysisKind defines model for AnalysisKind.
+type AnalysisKind struct {
+	Description string `json:"description"`
+	Name        string `json:"name"`
+
+	// Schema JSON schema that results of this kind follow, before sources are resolved
+	Schema string `json:"schema"`
+}
+
+// AnalysisKindName Name of a registered analysis kind. GET /core/analysis-kinds lists them;
+// the built-in kinds are summary, flashcards, quiz, free_response, deep_summary, glossary, timeline
+// and concept_map.
+type AnalysisKindName = string
+
+// AnalysisKinds defines model for AnalysisKinds.
+type AnalysisKinds = []AnalysisKind
+
+// AnalysisParameters Optional tuning for an analysis. Omitted fields leave the choice to the model.
+// Parameters are part of what makes two analyses identical for reuse.
+type AnalysisParameters struct {
+	Audience *AnalysisParametersAudience `json:"audience,omitempty"`
+
+	// Count Number of items to generate (flashcards, questions, terms, ...)
+	Count      *int                          `json:"count,omitempty"`
+	Difficulty *AnalysisParametersDifficulty `json:"difficulty,omitempty"`
+
+	// FocusTopics Topics to concentrate on, at most 10
+	FocusTopics *[]string `json:"focusTopics,omitempty"`
+
+	// Length Length of written text such as summaries and explanations
+	Length *AnalysisParametersLength `json:"length,omitempty"`
+}
+
+// AnalysisParametersAudience defines model for AnalysisParameters.Audience.
+type AnalysisParametersAudience string
+
+// AnalysisParametersDifficulty defines model for AnalysisParameters.Difficulty.
+type AnalysisParametersDifficulty string
+
+// AnalysisParametersLength Length of written text such as summaries and explanations
+type AnalysisParametersLength string
+
+// AnalysisPartialResultEvent defines model for AnalysisPartialResultEvent.
+type AnalysisPartialResultEvent struct {
+	Chunk int `json:"chunk"`
+
+	// Result The chunk's analysis, in the same shape as the final result except that
+	// "sources" still hold the unresolved [S#] labels
+	Result interface{} `json:"result"`
+	Total  int         `json:"total"`
+}
+
+// AnalysisSnapshotEvent defines model for AnalysisSnapshotEvent.
+type AnalysisSnapshotEvent struct {
+	SnapshotId openapi_types.UUID `json:"snapshotId"`
+}
+
+// AnalysisStatusEvent defines model for AnalysisStatusEvent.
+type AnalysisStatusEvent struct {
+	Status AnalysisStatusEventStatus `json:"status"`
+}
+
+// AnalysisStatusEventStatus defines model for AnalysisStatusEvent.Status.
+type AnalysisStatusEventStatus string
+
+// AnalysisStreamError defines model for AnalysisStreamError.
+type AnalysisStreamError struct {
+	Attempts   *int      `json:"attempts,omitempty"`
+	Message    string    `json:"message"`
+	Type       *string   `json:"type,omitempty"`
+	Violations *[]string `json:"violations,omitempty"`
+}
+
+// AnalysisTokenEvent defines model for AnalysisTokenEvent.
+type AnalysisTokenEvent struct {
+	Attempt int    `json:"attempt"`
+	Delta   string `json:"delta"`
+}
+
+// AnalysisValidationError defines model for AnalysisValidationError.
+type AnalysisValidationError struct {
+	Attempts   int      `json:"attempts"`
+	Message    string   `json:"message"`
+	Type       string   `json:"type"`
+	Violations []string `json:"violations"`
+}
+
 // AnalyzeCollectionRequest defines model for AnalyzeCollectionRequest.
 type AnalyzeCollectionRequest struct {
-	Type AnalyzeCollectionRequestType `json:"type"`
+	// Parameters Optional tuning for an analysis. Omitted fields leave the choice to the model.
+	// Parameters are part of what makes two analyses identical for reuse.
+	Parameters *AnalysisParameters `json:"parameters,omitempty"`
+
+	// Provider LLM vendor to run the analysis with. Defaults to the deployment's configured provider.
+	// Only providers the deployment has configured are accepted; "fake" is for offline development.
+	Provider *LLMProvider `json:"provider,omitempty"`
+
+	// Regenerate Call the model even if this collection was already analyzed with identical content
+	// and parameters. By default the earlier analysis is returned.
+	Regenerate *bool `json:"regenerate,omitempty"`
+
+	// Type Name of a registered analysis kind. GET /core/analysis-kinds lists them;
+	// the built-in kinds are summary, flashcards, quiz, free_response, deep_summary, glossary, timeline
+	// and concept_map.
+	Type AnalysisKindName `json:"type"`
+}
+
+// Chat defines model for Chat.
+type Chat struct {
+	// CollectionId Set for chats over a collection
+	CollectionId *openapi_types.UUID `json:"collectionId,omitempty"`
+
+	// Course Set for chats over a course
+	Course    *string            `json:"course,omitempty"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Messages Set when a chat is fetched on its own
+	Messages *[]ChatMessage `json:"messages,omitempty"`
+
+	// Title The first question, empty until one is asked
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ChatMessage defines model for ChatMessage.
+type ChatMessage struct {
+	Content   string             `json:"content"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+	Role      ChatMessageRole    `json:"role"`
+
+	// Sources The pages an answer cites, numbered by its [1], [2], ... markers. Empty for questions.
+	Sources []Source `json:"sources"`
+
+	// Usage What producing a result cost, totalled over every model request it took.
+	// Blank for analyses from before usage was recorded.
+	Usage ModelUsage `json:"usage"`
+}
+
+// ChatMessageRole defines model for ChatMessage.Role.
+type ChatMessageRole string
+
+// ChatStreamError defines model for ChatStreamError.
+type ChatStreamError struct {
+	Message string `json:"message"`
+}
+
+// ChatTokenEvent defines model for ChatTokenEvent.
+type ChatTokenEvent struct {
+	Delta string `json:"delta"`
 }
 
-// AnalyzeCollectionRequestType defines model for AnalyzeCollectionRequest.Type.
-type AnalyzeCollectionRequestType string
+// Chats defines model for Chats.
+type Chats = []Chat
 
 // Collection defines model for Collection.
 type Collection struct {
@@ -50,14 +332,36 @@ type CollectionAnalyses = []CollectionAnalysis
 
 // CollectionAnalysis defines model for CollectionAnalysis.
 type CollectionAnalysis struct {
-	Id        openapi_types.UUID     `json:"id"`
-	Result    string                 `json:"result"`
-	Type      CollectionAnalysisType `json:"type"`
-	CreatedAt *time.Time             `json:"createdAt,omitempty"`
-}
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
 
-// CollectionAnalysisType defines model for CollectionAnalysis.Type.
-type CollectionAnalysisType string
+	// Imported The analysis holds flashcards or quiz questions imported into the collection rather than generated
+	Imported *bool `json:"imported,omitempty"`
+
+	// Parameters Optional tuning for an analysis. Omitted fields leave the choice to the model.
+	// Parameters are part of what makes two analyses identical for reuse.
+	Parameters AnalysisParameters `json:"parameters"`
+
+	// Result JSON document in the shape of the analysis type. Each item (or the summary as a whole)
+	// has a "sources" array of Source objects citing the documents and pages it came from.
+	// Quiz questions leave out their correct_index, which only a submitted attempt reveals.
+	Result string `json:"result"`
+
+	// Reused An earlier analysis of identical content was returned instead of running a new one
+	Reused *bool `json:"reused,omitempty"`
+
+	// TemplateId The course prompt template version that produced the analysis. Absent for the default instructions.
+	TemplateId *openapi_types.UUID `json:"templateId,omitempty"`
+
+	// Type Name of a registered analysis kind. GET /core/analysis-kinds lists them;
+	// the built-in kinds are summary, flashcards, quiz, free_response, deep_summary, glossary, timeline
+	// and concept_map.
+	Type AnalysisKindName `json:"type"`
+
+	// Usage What producing a result cost, totalled over every model request it took.
+	// Blank for analyses from before usage was recorded.
+	Usage ModelUsage `json:"usage"`
+}
 
 // CollectionNames defines model for CollectionNames.
 type CollectionNames = []Collection
@@ -65,6 +369,27 @@ type CollectionNames = []Collection
 // Collections defines model for Collections.
 type Collections = []Collection
 
+// ConceptMap Result of a concept_map analysis once parsed. Edges join node ids and read from -> to.
+type ConceptMap struct {
+	Edges []ConceptMapEdge `json:"edges"`
+	Nodes []ConceptMapNode `json:"nodes"`
+}
+
+// ConceptMapEdge defines model for ConceptMapEdge.
+type ConceptMapEdge struct {
+	From  string `json:"from"`
+	Label string `json:"label"`
+	To    string `json:"to"`
+}
+
+// ConceptMapNode defines model for ConceptMapNode.
+type ConceptMapNode struct {
+	Description string   `json:"description"`
+	Id          string   `json:"id"`
+	Label       string   `json:"label"`
+	Sources     []Source `json:"sources"`
+}
+
 // CourseNames defines model for CourseNames.
 type CourseNames = []string
 
@@ -80,6 +405,93 @@ type Document struct {
 // Documents defines model for Documents.
 type Documents = []Document
 
+// FlashcardExportFormat apkg (the default) is an Anki package with a deck per collection
+type FlashcardExportFormat string
+
+// FreeResponseGrade defines model for FreeResponseGrade.
+type FreeResponseGrade struct {
+	Feedback string `json:"feedback"`
+
+	// Hit Rubric criteria awarded in full
+	Hit      []RubricCriterionGrade `json:"hit"`
+	ItemId   openapi_types.UUID     `json:"itemId"`
+	MaxScore int                    `json:"maxScore"`
+
+	// Missed Rubric criteria awarded partly or not at all
+	Missed          []RubricCriterionGrade `json:"missed"`
+	Question        string                 `json:"question"`
+	ReferenceAnswer string                 `json:"referenceAnswer"`
+	Score           int                    `json:"score"`
+
+	// Sources The pages the feedback draws on
+	Sources []Source `json:"sources"`
+
+	// Usage What producing a result cost, totalled over every model request it took.
+	// Blank for analyses from before usage was recorded.
+	Usage ModelUsage `json:"usage"`
+}
+
+// GradeFreeResponseRequest defines model for GradeFreeResponseRequest.
+type GradeFreeResponseRequest struct {
+	// Answer The student's answer, up to 10000 characters
+	Answer string `json:"answer"`
+
+	// Provider LLM vendor to run the analysis with. Defaults to the deployment's configured provider.
+	// Only providers the deployment has configured are accepted; "fake" is for offline development.
+	Provider *LLMProvider `json:"provider,omitempty"`
+}
+
+// GradeReviewCardRequest defines model for GradeReviewCardRequest.
+type GradeReviewCardRequest struct {
+	// Grade Quality of recall from 0 (blackout) to 5 (perfect). 3 and up count as remembered.
+	Grade int `json:"grade"`
+}
+
+// ImportFormat apkg for Anki packages, csv or tsv, or quizlet for Quizlet's export
+type ImportFormat string
+
+// ImportResult defines model for ImportResult.
+type ImportResult struct {
+	// AnalysisId The collection's imported analysis of the kind
+	AnalysisId openapi_types.UUID `json:"analysisId"`
+	Created    int                `json:"created"`
+
+	// Problems Why rows were skipped, for the first few
+	Problems []string `json:"problems"`
+
+	// Skipped Rows that didn't make a valid item
+	Skipped int `json:"skipped"`
+
+	// Unchanged Items imported before as they are, or deleted since
+	Unchanged int `json:"unchanged"`
+
+	// Updated Items imported before that changed in the file since
+	Updated int `json:"updated"`
+}
+
+// LLMProvider LLM vendor to run the analysis with. Defaults to the deployment's configured provider.
+// Only providers the deployment has configured are accepted; "fake" is for offline development.
+type LLMProvider string
+
+// ModelUsage What producing a result cost, totalled over every model request it took.
+// Blank for analyses from before usage was recorded.
+type ModelUsage struct {
+	InputTokens int64 `json:"inputTokens"`
+
+	// LatencyMs Time spent waiting on the model, including rate limiting
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Model Model that answered, as reported by the provider
+	Model        string `json:"model"`
+	OutputTokens int64  `json:"outputTokens"`
+
+	// PromptHash SHA-256 of the instructions sent with the first request
+	PromptHash string `json:"promptHash"`
+
+	// Requests Model requests made, including schema repairs and chunks of long collections
+	Requests int `json:"requests"`
+}
+
 // NewCollectionRequest defines model for NewCollectionRequest.
 type NewCollectionRequest struct {
 	Course string `json:"course"`
@@ -102,6 +514,220 @@ type NewCourseResponse struct {
 	CourseName string `json:"courseName"`
 }
 
+// PromptTemplate defines model for PromptTemplate.
+type PromptTemplate struct {
+	// Active This is the version analyses of the kind in the course currently use
+	Active    bool               `json:"active"`
+	Course    string             `json:"course"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Kind Name of a registered analysis kind. GET /core/analysis-kinds lists them;
+	// the built-in kinds are summary, flashcards, quiz, free_response, deep_summary, glossary, timeline
+	// and concept_map.
+	Kind      AnalysisKindName `json:"kind"`
+	RetiredAt *time.Time       `json:"retiredAt,omitempty"`
+	Template  string           `json:"template"`
+	Version   int              `json:"version"`
+}
+
+// PromptTemplates defines model for PromptTemplates.
+type PromptTemplates = []PromptTemplate
+
+// QuizAnswer defines model for QuizAnswer.
+type QuizAnswer struct {
+	// Index Index of the question answered
+	Index int `json:"index"`
+
+	// SelectedIndex Index of the chosen option. Leave out to skip the question.
+	SelectedIndex *int `json:"selectedIndex,omitempty"`
+
+	// TimeMs Time spent on the question
+	TimeMs *int64 `json:"timeMs,omitempty"`
+}
+
+// QuizAnswerResult defines model for QuizAnswerResult.
+type QuizAnswerResult struct {
+	Correct       bool   `json:"correct"`
+	CorrectIndex  int    `json:"correctIndex"`
+	Index         int    `json:"index"`
+	Question      string `json:"question"`
+	SelectedIndex *int   `json:"selectedIndex,omitempty"`
+	TimeMs        int64  `json:"timeMs"`
+}
+
+// QuizAttempt defines model for QuizAttempt.
+type QuizAttempt struct {
+	AnalysisId      openapi_types.UUID `json:"analysisId"`
+	CollectionId    openapi_types.UUID `json:"collectionId"`
+	CollectionTitle string             `json:"collectionTitle"`
+	Course          string             `json:"course"`
+
+	// DurationMs Time from starting to submitting. Set once submitted.
+	DurationMs *int64             `json:"durationMs,omitempty"`
+	Id         openapi_types.UUID `json:"id"`
+
+	// Questions Set when the attempt is started
+	Questions *[]QuizQuestion `json:"questions,omitempty"`
+
+	// Results Set when a submitted attempt is fetched on its own or just submitted
+	Results *[]QuizAnswerResult `json:"results,omitempty"`
+
+	// Score Questions answered correctly. Set once submitted.
+	Score     *int      `json:"score,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+
+	// SubmittedAt Unset while the attempt is in progress
+	SubmittedAt *time.Time `json:"submittedAt,omitempty"`
+
+	// Total Questions in the quiz. Set once submitted.
+	Total *int `json:"total,omitempty"`
+}
+
+// QuizAttempts defines model for QuizAttempts.
+type QuizAttempts = []QuizAttempt
+
+// QuizQuestion defines model for QuizQuestion.
+type QuizQuestion struct {
+	Index    int      `json:"index"`
+	Options  []string `json:"options"`
+	Question string   `json:"question"`
+}
+
+// QuotaExceededError defines model for QuotaExceededError.
+type QuotaExceededError struct {
+	Limit    int64                    `json:"limit"`
+	Message  string                   `json:"message"`
+	Metric   QuotaExceededErrorMetric `json:"metric"`
+	Period   QuotaExceededErrorPeriod `json:"period"`
+	ResetsAt time.Time                `json:"resetsAt"`
+	Used     int64                    `json:"used"`
+}
+
+// QuotaExceededErrorMetric defines model for QuotaExceededError.Metric.
+type QuotaExceededErrorMetric string
+
+// QuotaExceededErrorPeriod defines model for QuotaExceededError.Period.
+type QuotaExceededErrorPeriod string
+
+// ReorderAnalysisItemsRequest defines model for ReorderAnalysisItemsRequest.
+type ReorderAnalysisItemsRequest struct {
+	// ItemIds Every item of the analysis, each exactly once, in the new order
+	ItemIds []openapi_types.UUID `json:"itemIds"`
+}
+
+// ReviewCard defines model for ReviewCard.
+type ReviewCard struct {
+	AnalysisId      openapi_types.UUID `json:"analysisId"`
+	Answer          string             `json:"answer"`
+	CollectionId    openapi_types.UUID `json:"collectionId"`
+	CollectionTitle string             `json:"collectionTitle"`
+	Course          string             `json:"course"`
+	DueAt           time.Time          `json:"dueAt"`
+	EaseFactor      float64            `json:"easeFactor"`
+	Id              openapi_types.UUID `json:"id"`
+	IntervalDays    int                `json:"intervalDays"`
+
+	// ItemId The analysis item the card reviews. Edits to the item show up on the card.
+	ItemId         openapi_types.UUID `json:"itemId"`
+	LastReviewedAt *time.Time         `json:"lastReviewedAt,omitempty"`
+
+	// Position Position of the card's item in the analysis
+	Position int    `json:"position"`
+	Question string `json:"question"`
+
+	// Repetitions Passing reviews in a row
+	Repetitions int      `json:"repetitions"`
+	Sources     []Source `json:"sources"`
+}
+
+// ReviewCards defines model for ReviewCards.
+type ReviewCards = []ReviewCard
+
+// ReviewQueue defines model for ReviewQueue.
+type ReviewQueue struct {
+	Cards ReviewCards `json:"cards"`
+
+	// Due Every due card matching the filter, which may be more than the cards returned
+	Due int64 `json:"due"`
+}
+
+// RubricCriterionGrade defines model for RubricCriterionGrade.
+type RubricCriterionGrade struct {
+	Awarded     int    `json:"awarded"`
+	Criterion   string `json:"criterion"`
+	Explanation string `json:"explanation"`
+
+	// Points What the criterion is worth
+	Points int `json:"points"`
+}
+
+// SavePromptTemplateRequest defines model for SavePromptTemplateRequest.
+type SavePromptTemplateRequest struct {
+	// Kind Name of a registered analysis kind. GET /core/analysis-kinds lists them;
+	// the built-in kinds are summary, flashcards, quiz, free_response, deep_summary, glossary, timeline
+	// and concept_map.
+	Kind AnalysisKindName `json:"kind"`
+
+	// Template Go text/template body. Available fields are {{.Course}}, {{.Collection}}, {{.Kind}},
+	// {{.Task}} (the kind's built-in task), {{.Schema}} and {{.Default}} (the complete default
+	// instructions, to add to them instead of replacing them). The output must contain the
+	// schema, through either {{.Schema}} or {{.Default}}.
+	Template string `json:"template"`
+}
+
+// SearchResult A passage of the user's material that matches a search
+type SearchResult struct {
+	CollectionId    openapi_types.UUID `json:"collectionId"`
+	CollectionTitle string             `json:"collectionTitle"`
+	Content         string             `json:"content"`
+	Course          string             `json:"course"`
+	DocumentId      openapi_types.UUID `json:"documentId"`
+	DocumentTitle   string             `json:"documentTitle"`
+	Page            int                `json:"page"`
+
+	// Score Cosine similarity between the passage and the query, higher is closer
+	Score     float64            `json:"score"`
+	SegmentId openapi_types.UUID `json:"segmentId"`
+}
+
+// SearchResults defines model for SearchResults.
+type SearchResults struct {
+	Query string `json:"query"`
+
+	// Results Best match first
+	Results []SearchResult `json:"results"`
+
+	// Usage What producing a result cost, totalled over every model request it took.
+	// Blank for analyses from before usage was recorded.
+	Usage ModelUsage `json:"usage"`
+}
+
+// SendChatMessageRequest defines model for SendChatMessageRequest.
+type SendChatMessageRequest struct {
+	// Content The question, up to 4000 characters
+	Content string `json:"content"`
+
+	// Provider LLM vendor to run the analysis with. Defaults to the deployment's configured provider.
+	// Only providers the deployment has configured are accepted; "fake" is for offline development.
+	Provider *LLMProvider `json:"provider,omitempty"`
+}
+
+// Source A page of a document an analysis item was drawn from
+type Source struct {
+	DocumentId openapi_types.UUID `json:"documentId"`
+	Page       int                `json:"page"`
+	Title      string             `json:"title"`
+}
+
+// StudyGuideFormat pdf (the default), markdown, or latex for a standalone .tex document
+type StudyGuideFormat string
+
+// SubmitQuizAttemptRequest defines model for SubmitQuizAttemptRequest.
+type SubmitQuizAttemptRequest struct {
+	Answers []QuizAnswer `json:"answers"`
+}
+
 // UploadFileRequest defines model for UploadFileRequest.
 type UploadFileRequest struct {
 	CollectionID openapi_types.UUID `json:"collectionID"`
@@ -113,21 +739,188 @@ type UploadFileResponse struct {
 	UploadURL string `json:"uploadURL"`
 }
 
+// UsagePeriod defines model for UsagePeriod.
+type UsagePeriod struct {
+	// Analysis Tokens count input and output together
+	Analysis UsageTotals `json:"analysis"`
+
+	// Embedding Tokens count input and output together
+	Embedding UsageTotals `json:"embedding"`
+
+	// Extraction Tokens count input and output together
+	Extraction UsageTotals `json:"extraction"`
+
+	// RequestLimit Absent when unlimited
+	RequestLimit *int64    `json:"requestLimit,omitempty"`
+	ResetsAt     time.Time `json:"resetsAt"`
+	Start        time.Time `json:"start"`
+
+	// TokenLimit Absent when unlimited
+	TokenLimit *int64 `json:"tokenLimit,omitempty"`
+
+	// Used Tokens count input and output together
+	Used UsageTotals `json:"used"`
+}
+
+// UsageSummary Quotas reset at midnight UTC and on the first of each month
+type UsageSummary struct {
+	Day   UsagePeriod `json:"day"`
+	Month UsagePeriod `json:"month"`
+}
+
+// UsageTotals Tokens count input and output together
+type UsageTotals struct {
+	Requests int64 `json:"requests"`
+	Tokens   int64 `json:"tokens"`
+}
+
+// ExportFlashcardsParams defines parameters for ExportFlashcards.
+type ExportFlashcardsParams struct {
+	Format *FlashcardExportFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ImportAnalysisItemsTextBody defines parameters for ImportAnalysisItems.
+type ImportAnalysisItemsTextBody = string
+
+// ImportAnalysisItemsParams defines parameters for ImportAnalysisItems.
+type ImportAnalysisItemsParams struct {
+	Kind   ImportAnalysisItemsParamsKind `form:"kind" json:"kind"`
+	Format ImportFormat                  `form:"format" json:"format"`
+}
+
+// ImportAnalysisItemsParamsKind defines parameters for ImportAnalysisItems.
+type ImportAnalysisItemsParamsKind string
+
+// ExportStudyGuideParams defines parameters for ExportStudyGuide.
+type ExportStudyGuideParams struct {
+	Format *StudyGuideFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ExportCourseFlashcardsParams defines parameters for ExportCourseFlashcards.
+type ExportCourseFlashcardsParams struct {
+	Format *FlashcardExportFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetDueReviewsParams defines parameters for GetDueReviews.
+type GetDueReviewsParams struct {
+	Course       *string             `form:"course,omitempty" json:"course,omitempty"`
+	CollectionId *openapi_types.UUID `form:"collectionId,omitempty" json:"collectionId,omitempty"`
+	Limit        *int                `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Timezone IANA name of the user's timezone, such as Europe/Berlin, whose day the queue covers
+	Timezone *string `form:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// SearchParams defines parameters for Search.
+type SearchParams struct {
+	Q            string              `form:"q" json:"q"`
+	Course       *string             `form:"course,omitempty" json:"course,omitempty"`
+	CollectionId *openapi_types.UUID `form:"collectionId,omitempty" json:"collectionId,omitempty"`
+	Limit        *int                `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// EditAnalysisItemJSONRequestBody defines body for EditAnalysisItem for application/json ContentType.
+type EditAnalysisItemJSONRequestBody = AnalysisItemRequest
+
+// GradeFreeResponseJSONRequestBody defines body for GradeFreeResponse for application/json ContentType.
+type GradeFreeResponseJSONRequestBody = GradeFreeResponseRequest
+
+// AddAnalysisItemJSONRequestBody defines body for AddAnalysisItem for application/json ContentType.
+type AddAnalysisItemJSONRequestBody = AnalysisItemRequest
+
+// ReorderAnalysisItemsJSONRequestBody defines body for ReorderAnalysisItems for application/json ContentType.
+type ReorderAnalysisItemsJSONRequestBody = ReorderAnalysisItemsRequest
+
+// SendChatMessageJSONRequestBody defines body for SendChatMessage for application/json ContentType.
+type SendChatMessageJSONRequestBody = SendChatMessageRequest
+
+// StreamChatMessageJSONRequestBody defines body for StreamChatMessage for application/json ContentType.
+type StreamChatMessageJSONRequestBody = SendChatMessageRequest
+
 // NewCollectionJSONRequestBody defines body for NewCollection for application/json ContentType.
 type NewCollectionJSONRequestBody = NewCollectionRequest
 
+// EnqueueCollectionAnalysisJSONRequestBody defines body for EnqueueCollectionAnalysis for application/json ContentType.
+type EnqueueCollectionAnalysisJSONRequestBody = AnalyzeCollectionRequest
+
 // AnalyzeCollectionJSONRequestBody defines body for AnalyzeCollection for application/json ContentType.
 type AnalyzeCollectionJSONRequestBody = AnalyzeCollectionRequest
 
+// StreamCollectionAnalysisJSONRequestBody defines body for StreamCollectionAnalysis for application/json ContentType.
+type StreamCollectionAnalysisJSONRequestBody = AnalyzeCollectionRequest
+
+// ImportAnalysisItemsTextRequestBody defines body for ImportAnalysisItems for text/plain ContentType.
+type ImportAnalysisItemsTextRequestBody = ImportAnalysisItemsTextBody
+
 // NewCourseJSONRequestBody defines body for NewCourse for application/json ContentType.
 type NewCourseJSONRequestBody = NewCourseRequest
 
+// SavePromptTemplateJSONRequestBody defines body for SavePromptTemplate for application/json ContentType.
+type SavePromptTemplateJSONRequestBody = SavePromptTemplateRequest
+
 // UploadFileJSONRequestBody defines body for UploadFile for application/json ContentType.
 type UploadFileJSONRequestBody = UploadFileRequest
 
+// SubmitQuizAttemptJSONRequestBody defines body for SubmitQuizAttempt for application/json ContentType.
+type SubmitQuizAttemptJSONRequestBody = SubmitQuizAttemptRequest
+
+// GradeReviewCardJSONRequestBody defines body for GradeReviewCard for application/json ContentType.
+type GradeReviewCardJSONRequestBody = GradeReviewCardRequest
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (DELETE /core/analysis-item/{itemID})
+	DeleteAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID)
+
+	// (PUT /core/analysis-item/{itemID})
+	EditAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID)
+
+	// (DELETE /core/analysis-item/{itemID}/edit)
+	RevertAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID)
+
+	// (POST /core/analysis-item/{itemID}/grade)
+	GradeFreeResponse(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID)
+	// Retrieve the status of an analysis job
+	// (GET /core/analysis-job/{jobID})
+	GetAnalysisJob(w http.ResponseWriter, r *http.Request, jobID openapi_types.UUID)
+	// List the analysis kinds that can be requested
+	// (GET /core/analysis-kinds)
+	GetAnalysisKinds(w http.ResponseWriter, r *http.Request)
+
+	// (GET /core/analysis/{analysisID}/export)
+	ExportFlashcards(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID, params ExportFlashcardsParams)
+
+	// (GET /core/analysis/{analysisID}/items)
+	GetAnalysisItems(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID)
+
+	// (POST /core/analysis/{analysisID}/items)
+	AddAnalysisItem(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID)
+
+	// (PUT /core/analysis/{analysisID}/items/order)
+	ReorderAnalysisItems(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID)
+
+	// (GET /core/analysis/{analysisID}/quiz-attempts)
+	GetAnalysisQuizAttempts(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID)
+
+	// (POST /core/analysis/{analysisID}/quiz-attempts)
+	StartQuizAttempt(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID)
+
+	// (POST /core/analysis/{analysisID}/review-cards)
+	EnrollReviewCards(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID)
+
+	// (DELETE /core/chat/{chatID})
+	DeleteChat(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID)
+
+	// (GET /core/chat/{chatID})
+	GetChat(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID)
+
+	// (POST /core/chat/{chatID}/messages)
+	SendChatMessage(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID)
+
+	// (POST /core/chat/{chatID}/messages/stream)
+	StreamChatMessage(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID)
+
 	// (POST /core/collection)
 	NewCollection(w http.ResponseWriter, r *http.Request)
 
@@ -136,12 +929,30 @@ type ServerInterface interface {
 	// Retrieve analyses for a collection
 	// (GET /core/collection/{id}/analyses)
 	GetCollectionAnalyses(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// Queue an AI analysis on a collection
+	// (POST /core/collection/{id}/analysis-jobs)
+	EnqueueCollectionAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
 	// Retrieve and analysis
 	// (GET /core/collection/{id}/analysis/{analysisID})
 	GetAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, analysisID openapi_types.UUID)
 	// Run an AI analysis on a collection
 	// (POST /core/collection/{id}/analyze)
 	AnalyzeCollection(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// Run an AI analysis on a collection, streaming progress
+	// (POST /core/collection/{id}/analyze/stream)
+	StreamCollectionAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+
+	// (GET /core/collection/{id}/chats)
+	GetCollectionChats(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+
+	// (POST /core/collection/{id}/chats)
+	CreateCollectionChat(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+
+	// (POST /core/collection/{id}/import)
+	ImportAnalysisItems(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params ImportAnalysisItemsParams)
+
+	// (GET /core/collection/{id}/study-guide)
+	ExportStudyGuide(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params ExportStudyGuideParams)
 
 	// (GET /core/collections/{courseID}/{type})
 	FilterCollections(w http.ResponseWriter, r *http.Request, courseID string, pType string)
@@ -152,9 +963,30 @@ type ServerInterface interface {
 	// (POST /core/course)
 	NewCourse(w http.ResponseWriter, r *http.Request)
 
+	// (GET /core/course/{courseID}/chats)
+	GetCourseChats(w http.ResponseWriter, r *http.Request, courseID string)
+
+	// (POST /core/course/{courseID}/chats)
+	CreateCourseChat(w http.ResponseWriter, r *http.Request, courseID string)
+
 	// (GET /core/course/{courseID}/collections)
 	GetCourseCollections(w http.ResponseWriter, r *http.Request, courseID string)
 
+	// (GET /core/course/{courseID}/flashcards/export)
+	ExportCourseFlashcards(w http.ResponseWriter, r *http.Request, courseID string, params ExportCourseFlashcardsParams)
+
+	// (GET /core/course/{courseID}/prompt-templates)
+	GetPromptTemplates(w http.ResponseWriter, r *http.Request, courseID string)
+
+	// (POST /core/course/{courseID}/prompt-templates)
+	SavePromptTemplate(w http.ResponseWriter, r *http.Request, courseID string)
+
+	// (DELETE /core/course/{courseID}/prompt-templates/{kind})
+	RetirePromptTemplates(w http.ResponseWriter, r *http.Request, courseID string, kind AnalysisKindName)
+
+	// (GET /core/course/{courseID}/quiz-attempts)
+	GetCourseQuizAttempts(w http.ResponseWriter, r *http.Request, courseID string)
+
 	// (GET /core/courses)
 	GetCourses(w http.ResponseWriter, r *http.Request)
 
@@ -163,31 +995,142 @@ type ServerInterface interface {
 
 	// (GET /core/document/{id})
 	GetDocument(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+
+	// (GET /core/quiz-attempt/{attemptID})
+	GetQuizAttempt(w http.ResponseWriter, r *http.Request, attemptID openapi_types.UUID)
+
+	// (POST /core/quiz-attempt/{attemptID}/submit)
+	SubmitQuizAttempt(w http.ResponseWriter, r *http.Request, attemptID openapi_types.UUID)
+
+	// (POST /core/review-card/{cardID}/grade)
+	GradeReviewCard(w http.ResponseWriter, r *http.Request, cardID openapi_types.UUID)
+
+	// (GET /core/reviews/due)
+	GetDueReviews(w http.ResponseWriter, r *http.Request, params GetDueReviewsParams)
+	// Search the user's documents by meaning
+	// (GET /core/search)
+	Search(w http.ResponseWriter, r *http.Request, params SearchParams)
+	// Model usage and quotas for the current day and month
+	// (GET /core/usage)
+	GetUsage(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
-// (POST /core/collection)
-func (_ Unimplemented) NewCollection(w http.ResponseWriter, r *http.Request) {
+// (DELETE /core/analysis-item/{itemID})
+func (_ Unimplemented) DeleteAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (GET /core/collection/{id})
-func (_ Unimplemented) GetCollection(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+// (PUT /core/analysis-item/{itemID})
+func (_ Unimplemented) EditAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Retrieve analyses for a collection
-// (GET /core/collection/{id}/analyses)
-func (_ Unimplemented) GetCollectionAnalyses(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+// (DELETE /core/analysis-item/{itemID}/edit)
+func (_ Unimplemented) RevertAnalysisItem(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Retrieve and analysis
-// (GET /core/collection/{id}/analysis/{analysisID})
-func (_ Unimplemented) GetAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, analysisID openapi_types.UUID) {
+// (POST /core/analysis-item/{itemID}/grade)
+func (_ Unimplemented) GradeFreeResponse(w http.ResponseWriter, r *http.Request, itemID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Retrieve the status of an analysis job
+// (GET /core/analysis-job/{jobID})
+func (_ Unimplemented) GetAnalysisJob(w http.ResponseWriter, r *http.Request, jobID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List the analysis kinds that can be requested
+// (GET /core/analysis-kinds)
+func (_ Unimplemented) GetAnalysisKinds(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/analysis/{analysisID}/export)
+func (_ Unimplemented) ExportFlashcards(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID, params ExportFlashcardsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/analysis/{analysisID}/items)
+func (_ Unimplemented) GetAnalysisItems(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/analysis/{analysisID}/items)
+func (_ Unimplemented) AddAnalysisItem(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /core/analysis/{analysisID}/items/order)
+func (_ Unimplemented) ReorderAnalysisItems(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/analysis/{analysisID}/quiz-attempts)
+func (_ Unimplemented) GetAnalysisQuizAttempts(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/analysis/{analysisID}/quiz-attempts)
+func (_ Unimplemented) StartQuizAttempt(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/analysis/{analysisID}/review-cards)
+func (_ Unimplemented) EnrollReviewCards(w http.ResponseWriter, r *http.Request, analysisID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /core/chat/{chatID})
+func (_ Unimplemented) DeleteChat(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/chat/{chatID})
+func (_ Unimplemented) GetChat(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/chat/{chatID}/messages)
+func (_ Unimplemented) SendChatMessage(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/chat/{chatID}/messages/stream)
+func (_ Unimplemented) StreamChatMessage(w http.ResponseWriter, r *http.Request, chatID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/collection)
+func (_ Unimplemented) NewCollection(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/collection/{id})
+func (_ Unimplemented) GetCollection(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Retrieve analyses for a collection
+// (GET /core/collection/{id}/analyses)
+func (_ Unimplemented) GetCollectionAnalyses(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Queue an AI analysis on a collection
+// (POST /core/collection/{id}/analysis-jobs)
+func (_ Unimplemented) EnqueueCollectionAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Retrieve and analysis
+// (GET /core/collection/{id}/analysis/{analysisID})
+func (_ Unimplemented) GetAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, analysisID openapi_types.UUID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -197,6 +1140,32 @@ func (_ Unimplemented) AnalyzeCollection(w http.ResponseWriter, r *http.Request,
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Run an AI analysis on a collection, streaming progress
+// (POST /core/collection/{id}/analyze/stream)
+func (_ Unimplemented) StreamCollectionAnalysis(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/collection/{id}/chats)
+func (_ Unimplemented) GetCollectionChats(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/collection/{id}/chats)
+func (_ Unimplemented) CreateCollectionChat(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/collection/{id}/import)
+func (_ Unimplemented) ImportAnalysisItems(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params ImportAnalysisItemsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/collection/{id}/study-guide)
+func (_ Unimplemented) ExportStudyGuide(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params ExportStudyGuideParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /core/collections/{courseID}/{type})
 func (_ Unimplemented) FilterCollections(w http.ResponseWriter, r *http.Request, courseID string, pType string) {
 	w.WriteHeader(http.StatusNotImplemented)
@@ -212,11 +1181,46 @@ func (_ Unimplemented) NewCourse(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (GET /core/course/{courseID}/chats)
+func (_ Unimplemented) GetCourseChats(w http.ResponseWriter, r *http.Request, courseID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/course/{courseID}/chats)
+func (_ Unimplemented) CreateCourseChat(w http.ResponseWriter, r *http.Request, courseID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /core/course/{courseID}/collections)
 func (_ Unimplemented) GetCourseCollections(w http.ResponseWriter, r *http.Request, courseID string) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (GET /core/course/{courseID}/flashcards/export)
+func (_ Unimplemented) ExportCourseFlashcards(w http.ResponseWriter, r *http.Request, courseID string, params ExportCourseFlashcardsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/course/{courseID}/prompt-templates)
+func (_ Unimplemented) GetPromptTemplates(w http.ResponseWriter, r *http.Request, courseID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/course/{courseID}/prompt-templates)
+func (_ Unimplemented) SavePromptTemplate(w http.ResponseWriter, r *http.Request, courseID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /core/course/{courseID}/prompt-templates/{kind})
+func (_ Unimplemented) RetirePromptTemplates(w http.ResponseWriter, r *http.Request, courseID string, kind AnalysisKindName) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/course/{courseID}/quiz-attempts)
+func (_ Unimplemented) GetCourseQuizAttempts(w http.ResponseWriter, r *http.Request, courseID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /core/courses)
 func (_ Unimplemented) GetCourses(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
@@ -232,6 +1236,38 @@ func (_ Unimplemented) GetDocument(w http.ResponseWriter, r *http.Request, id op
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (GET /core/quiz-attempt/{attemptID})
+func (_ Unimplemented) GetQuizAttempt(w http.ResponseWriter, r *http.Request, attemptID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/quiz-attempt/{attemptID}/submit)
+func (_ Unimplemented) SubmitQuizAttempt(w http.ResponseWriter, r *http.Request, attemptID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /core/review-card/{cardID}/grade)
+func (_ Unimplemented) GradeReviewCard(w http.ResponseWriter, r *http.Request, cardID openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /core/reviews/due)
+func (_ Unimplemented) GetDueReviews(w http.ResponseWriter, r *http.Request, params GetDueReviewsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Search the user's documents by meaning
+// (GET /core/search)
+func (_ Unimplemented) Search(w http.ResponseWriter, r *http.Request, params SearchParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Model usage and quotas for the current day and month
+// (GET /core/usage)
+func (_ Unimplemented) GetUsage(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	Handler            ServerInterface
@@ -239,13 +1275,925 @@ type ServerInterfaceWrapper struct {
 	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// DeleteAnalysisItem operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAnalysisItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "itemID" -------------
+	var itemID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "itemID", chi.URLParam(r, "itemID"), &itemID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "itemID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAnalysisItem(w, r, itemID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EditAnalysisItem operation middleware
+func (siw *ServerInterfaceWrapper) EditAnalysisItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "itemID" -------------
+	var itemID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "itemID", chi.URLParam(r, "itemID"), &itemID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "itemID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EditAnalysisItem(w, r, itemID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevertAnalysisItem operation middleware
+func (siw *ServerInterfaceWrapper) RevertAnalysisItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "itemID" -------------
+	var itemID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "itemID", chi.URLParam(r, "itemID"), &itemID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "itemID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevertAnalysisItem(w, r, itemID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GradeFreeResponse operation middleware
+func (siw *ServerInterfaceWrapper) GradeFreeResponse(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "itemID" -------------
+	var itemID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "itemID", chi.URLParam(r, "itemID"), &itemID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "itemID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GradeFreeResponse(w, r, itemID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAnalysisJob operation middleware
+func (siw *ServerInterfaceWrapper) GetAnalysisJob(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "jobID" -------------
+	var jobID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobID", chi.URLParam(r, "jobID"), &jobID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "jobID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAnalysisJob(w, r, jobID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAnalysisKinds operation middleware
+func (siw *ServerInterfaceWrapper) GetAnalysisKinds(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAnalysisKinds(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportFlashcards operation middleware
+func (siw *ServerInterfaceWrapper) ExportFlashcards(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportFlashcardsParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportFlashcards(w, r, analysisID, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAnalysisItems operation middleware
+func (siw *ServerInterfaceWrapper) GetAnalysisItems(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAnalysisItems(w, r, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AddAnalysisItem operation middleware
+func (siw *ServerInterfaceWrapper) AddAnalysisItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddAnalysisItem(w, r, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReorderAnalysisItems operation middleware
+func (siw *ServerInterfaceWrapper) ReorderAnalysisItems(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReorderAnalysisItems(w, r, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAnalysisQuizAttempts operation middleware
+func (siw *ServerInterfaceWrapper) GetAnalysisQuizAttempts(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAnalysisQuizAttempts(w, r, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// StartQuizAttempt operation middleware
+func (siw *ServerInterfaceWrapper) StartQuizAttempt(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StartQuizAttempt(w, r, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EnrollReviewCards operation middleware
+func (siw *ServerInterfaceWrapper) EnrollReviewCards(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EnrollReviewCards(w, r, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteChat operation middleware
+func (siw *ServerInterfaceWrapper) DeleteChat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "chatID" -------------
+	var chatID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "chatID", chi.URLParam(r, "chatID"), &chatID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "chatID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteChat(w, r, chatID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetChat operation middleware
+func (siw *ServerInterfaceWrapper) GetChat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "chatID" -------------
+	var chatID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "chatID", chi.URLParam(r, "chatID"), &chatID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "chatID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetChat(w, r, chatID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SendChatMessage operation middleware
+func (siw *ServerInterfaceWrapper) SendChatMessage(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "chatID" -------------
+	var chatID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "chatID", chi.URLParam(r, "chatID"), &chatID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "chatID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SendChatMessage(w, r, chatID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// StreamChatMessage operation middleware
+func (siw *ServerInterfaceWrapper) StreamChatMessage(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "chatID" -------------
+	var chatID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "chatID", chi.URLParam(r, "chatID"), &chatID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "chatID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StreamChatMessage(w, r, chatID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// NewCollection operation middleware
+func (siw *ServerInterfaceWrapper) NewCollection(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.NewCollection(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCollection operation middleware
+func (siw *ServerInterfaceWrapper) GetCollection(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCollection(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCollectionAnalyses operation middleware
+func (siw *ServerInterfaceWrapper) GetCollectionAnalyses(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCollectionAnalyses(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EnqueueCollectionAnalysis operation middleware
+func (siw *ServerInterfaceWrapper) EnqueueCollectionAnalysis(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EnqueueCollectionAnalysis(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAnalysis operation middleware
+func (siw *ServerInterfaceWrapper) GetAnalysis(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "analysisID" -------------
+	var analysisID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAnalysis(w, r, id, analysisID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AnalyzeCollection operation middleware
+func (siw *ServerInterfaceWrapper) AnalyzeCollection(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AnalyzeCollection(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// StreamCollectionAnalysis operation middleware
+func (siw *ServerInterfaceWrapper) StreamCollectionAnalysis(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StreamCollectionAnalysis(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCollectionChats operation middleware
+func (siw *ServerInterfaceWrapper) GetCollectionChats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCollectionChats(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateCollectionChat operation middleware
+func (siw *ServerInterfaceWrapper) CreateCollectionChat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateCollectionChat(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ImportAnalysisItems operation middleware
+func (siw *ServerInterfaceWrapper) ImportAnalysisItems(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportAnalysisItemsParams
+
+	// ------------- Required query parameter "kind" -------------
+
+	if paramValue := r.URL.Query().Get("kind"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "kind"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "kind", r.URL.Query(), &params.Kind)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "kind", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "format" -------------
+
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "format"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportAnalysisItems(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportStudyGuide operation middleware
+func (siw *ServerInterfaceWrapper) ExportStudyGuide(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportStudyGuideParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportStudyGuide(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// FilterCollections operation middleware
+func (siw *ServerInterfaceWrapper) FilterCollections(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "type" -------------
+	var pType string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "type", chi.URLParam(r, "type"), &pType, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FilterCollections(w, r, courseID, pType)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCollectionDocuments operation middleware
+func (siw *ServerInterfaceWrapper) GetCollectionDocuments(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCollectionDocuments(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// NewCourse operation middleware
+func (siw *ServerInterfaceWrapper) NewCourse(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.NewCourse(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCourseChats operation middleware
+func (siw *ServerInterfaceWrapper) GetCourseChats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCourseChats(w, r, courseID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateCourseChat operation middleware
+func (siw *ServerInterfaceWrapper) CreateCourseChat(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateCourseChat(w, r, courseID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCourseCollections operation middleware
+func (siw *ServerInterfaceWrapper) GetCourseCollections(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCourseCollections(w, r, courseID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportCourseFlashcards operation middleware
+func (siw *ServerInterfaceWrapper) ExportCourseFlashcards(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportCourseFlashcardsParams
 
-// NewCollection operation middleware
-func (siw *ServerInterfaceWrapper) NewCollection(w http.ResponseWriter, r *http.Request) {
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.NewCollection(w, r)
+		siw.Handler.ExportCourseFlashcards(w, r, courseID, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -255,22 +2203,22 @@ func (siw *ServerInterfaceWrapper) NewCollection(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// GetCollection operation middleware
-func (siw *ServerInterfaceWrapper) GetCollection(w http.ResponseWriter, r *http.Request) {
+// GetPromptTemplates operation middleware
+func (siw *ServerInterfaceWrapper) GetPromptTemplates(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id openapi_types.UUID
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCollection(w, r, id)
+		siw.Handler.GetPromptTemplates(w, r, courseID)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -280,22 +2228,22 @@ func (siw *ServerInterfaceWrapper) GetCollection(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// GetCollectionAnalyses operation middleware
-func (siw *ServerInterfaceWrapper) GetCollectionAnalyses(w http.ResponseWriter, r *http.Request) {
+// SavePromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) SavePromptTemplate(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id openapi_types.UUID
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCollectionAnalyses(w, r, id)
+		siw.Handler.SavePromptTemplate(w, r, courseID)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -305,31 +2253,31 @@ func (siw *ServerInterfaceWrapper) GetCollectionAnalyses(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// GetAnalysis operation middleware
-func (siw *ServerInterfaceWrapper) GetAnalysis(w http.ResponseWriter, r *http.Request) {
+// RetirePromptTemplates operation middleware
+func (siw *ServerInterfaceWrapper) RetirePromptTemplates(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id openapi_types.UUID
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
 		return
 	}
 
-	// ------------- Path parameter "analysisID" -------------
-	var analysisID openapi_types.UUID
+	// ------------- Path parameter "kind" -------------
+	var kind AnalysisKindName
 
-	err = runtime.BindStyledParameterWithOptions("simple", "analysisID", chi.URLParam(r, "analysisID"), &analysisID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "kind", chi.URLParam(r, "kind"), &kind, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "analysisID", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "kind", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAnalysis(w, r, id, analysisID)
+		siw.Handler.RetirePromptTemplates(w, r, courseID, kind)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -339,22 +2287,22 @@ func (siw *ServerInterfaceWrapper) GetAnalysis(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// AnalyzeCollection operation middleware
-func (siw *ServerInterfaceWrapper) AnalyzeCollection(w http.ResponseWriter, r *http.Request) {
+// GetCourseQuizAttempts operation middleware
+func (siw *ServerInterfaceWrapper) GetCourseQuizAttempts(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id openapi_types.UUID
+	// ------------- Path parameter "courseID" -------------
+	var courseID string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.AnalyzeCollection(w, r, id)
+		siw.Handler.GetCourseQuizAttempts(w, r, courseID)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -364,31 +2312,25 @@ func (siw *ServerInterfaceWrapper) AnalyzeCollection(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// FilterCollections operation middleware
-func (siw *ServerInterfaceWrapper) FilterCollections(w http.ResponseWriter, r *http.Request) {
-
-	var err error
+// GetCourses operation middleware
+func (siw *ServerInterfaceWrapper) GetCourses(w http.ResponseWriter, r *http.Request) {
 
-	// ------------- Path parameter "courseID" -------------
-	var courseID string
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCourses(w, r)
+	}))
 
-	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
-		return
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	// ------------- Path parameter "type" -------------
-	var pType string
+	handler.ServeHTTP(w, r)
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "type", chi.URLParam(r, "type"), &pType, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
-		return
-	}
+// UploadFile operation middleware
+func (siw *ServerInterfaceWrapper) UploadFile(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.FilterCollections(w, r, courseID, pType)
+		siw.Handler.UploadFile(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -398,8 +2340,8 @@ func (siw *ServerInterfaceWrapper) FilterCollections(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// GetCollectionDocuments operation middleware
-func (siw *ServerInterfaceWrapper) GetCollectionDocuments(w http.ResponseWriter, r *http.Request) {
+// GetDocument operation middleware
+func (siw *ServerInterfaceWrapper) GetDocument(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -413,7 +2355,7 @@ func (siw *ServerInterfaceWrapper) GetCollectionDocuments(w http.ResponseWriter,
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCollectionDocuments(w, r, id)
+		siw.Handler.GetDocument(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -423,11 +2365,22 @@ func (siw *ServerInterfaceWrapper) GetCollectionDocuments(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// NewCourse operation middleware
-func (siw *ServerInterfaceWrapper) NewCourse(w http.ResponseWriter, r *http.Request) {
+// GetQuizAttempt operation middleware
+func (siw *ServerInterfaceWrapper) GetQuizAttempt(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "attemptID" -------------
+	var attemptID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "attemptID", chi.URLParam(r, "attemptID"), &attemptID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "attemptID", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.NewCourse(w, r)
+		siw.Handler.GetQuizAttempt(w, r, attemptID)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -437,22 +2390,22 @@ func (siw *ServerInterfaceWrapper) NewCourse(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
-// GetCourseCollections operation middleware
-func (siw *ServerInterfaceWrapper) GetCourseCollections(w http.ResponseWriter, r *http.Request) {
+// SubmitQuizAttempt operation middleware
+func (siw *ServerInterfaceWrapper) SubmitQuizAttempt(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "courseID" -------------
-	var courseID string
+	// ------------- Path parameter "attemptID" -------------
+	var attemptID openapi_types.UUID
 
-	err = runtime.BindStyledParameterWithOptions("simple", "courseID", chi.URLParam(r, "courseID"), &courseID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "attemptID", chi.URLParam(r, "attemptID"), &attemptID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "courseID", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "attemptID", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCourseCollections(w, r, courseID)
+		siw.Handler.SubmitQuizAttempt(w, r, attemptID)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -462,11 +2415,22 @@ func (siw *ServerInterfaceWrapper) GetCourseCollections(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// GetCourses operation middleware
-func (siw *ServerInterfaceWrapper) GetCourses(w http.ResponseWriter, r *http.Request) {
+// GradeReviewCard operation middleware
+func (siw *ServerInterfaceWrapper) GradeReviewCard(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cardID" -------------
+	var cardID openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cardID", chi.URLParam(r, "cardID"), &cardID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cardID", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCourses(w, r)
+		siw.Handler.GradeReviewCard(w, r, cardID)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -476,11 +2440,48 @@ func (siw *ServerInterfaceWrapper) GetCourses(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// UploadFile operation middleware
-func (siw *ServerInterfaceWrapper) UploadFile(w http.ResponseWriter, r *http.Request) {
+// GetDueReviews operation middleware
+func (siw *ServerInterfaceWrapper) GetDueReviews(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDueReviewsParams
+
+	// ------------- Optional query parameter "course" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "course", r.URL.Query(), &params.Course)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "course", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "collectionId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "collectionId", r.URL.Query(), &params.CollectionId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "collectionId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "timezone" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "timezone", r.URL.Query(), &params.Timezone)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "timezone", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UploadFile(w, r)
+		siw.Handler.GetDueReviews(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -490,22 +2491,69 @@ func (siw *ServerInterfaceWrapper) UploadFile(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// GetDocument operation middleware
-func (siw *ServerInterfaceWrapper) GetDocument(w http.ResponseWriter, r *http.Request) {
+// Search operation middleware
+func (siw *ServerInterfaceWrapper) Search(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id openapi_types.UUID
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchParams
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "course" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "course", r.URL.Query(), &params.Course)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "course", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "collectionId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "collectionId", r.URL.Query(), &params.CollectionId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "collectionId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetDocument(w, r, id)
+		siw.Handler.Search(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsage(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -628,6 +2676,57 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/core/analysis-item/{itemID}", wrapper.DeleteAnalysisItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/core/analysis-item/{itemID}", wrapper.EditAnalysisItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/core/analysis-item/{itemID}/edit", wrapper.RevertAnalysisItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/analysis-item/{itemID}/grade", wrapper.GradeFreeResponse)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/analysis-job/{jobID}", wrapper.GetAnalysisJob)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/analysis-kinds", wrapper.GetAnalysisKinds)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/analysis/{analysisID}/export", wrapper.ExportFlashcards)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/analysis/{analysisID}/items", wrapper.GetAnalysisItems)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/analysis/{analysisID}/items", wrapper.AddAnalysisItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/core/analysis/{analysisID}/items/order", wrapper.ReorderAnalysisItems)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/analysis/{analysisID}/quiz-attempts", wrapper.GetAnalysisQuizAttempts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/analysis/{analysisID}/quiz-attempts", wrapper.StartQuizAttempt)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/analysis/{analysisID}/review-cards", wrapper.EnrollReviewCards)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/core/chat/{chatID}", wrapper.DeleteChat)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/chat/{chatID}", wrapper.GetChat)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/chat/{chatID}/messages", wrapper.SendChatMessage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/chat/{chatID}/messages/stream", wrapper.StreamChatMessage)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/core/collection", wrapper.NewCollection)
 	})
@@ -637,12 +2736,30 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/core/collection/{id}/analyses", wrapper.GetCollectionAnalyses)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/collection/{id}/analysis-jobs", wrapper.EnqueueCollectionAnalysis)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/core/collection/{id}/analysis/{analysisID}", wrapper.GetAnalysis)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/core/collection/{id}/analyze", wrapper.AnalyzeCollection)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/collection/{id}/analyze/stream", wrapper.StreamCollectionAnalysis)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/collection/{id}/chats", wrapper.GetCollectionChats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/collection/{id}/chats", wrapper.CreateCollectionChat)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/collection/{id}/import", wrapper.ImportAnalysisItems)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/collection/{id}/study-guide", wrapper.ExportStudyGuide)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/core/collections/{courseID}/{type}", wrapper.FilterCollections)
 	})
@@ -652,9 +2769,30 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/core/course", wrapper.NewCourse)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/course/{courseID}/chats", wrapper.GetCourseChats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/course/{courseID}/chats", wrapper.CreateCourseChat)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/core/course/{courseID}/collections", wrapper.GetCourseCollections)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/course/{courseID}/flashcards/export", wrapper.ExportCourseFlashcards)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/course/{courseID}/prompt-templates", wrapper.GetPromptTemplates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/course/{courseID}/prompt-templates", wrapper.SavePromptTemplate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/core/course/{courseID}/prompt-templates/{kind}", wrapper.RetirePromptTemplates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/course/{courseID}/quiz-attempts", wrapper.GetCourseQuizAttempts)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/core/courses", wrapper.GetCourses)
 	})
@@ -664,6 +2802,24 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/core/document/{id}", wrapper.GetDocument)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/quiz-attempt/{attemptID}", wrapper.GetQuizAttempt)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/quiz-attempt/{attemptID}/submit", wrapper.SubmitQuizAttempt)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/core/review-card/{cardID}/grade", wrapper.GradeReviewCard)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/reviews/due", wrapper.GetDueReviews)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/search", wrapper.Search)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/core/usage", wrapper.GetUsage)
+	})
 
 	return r
 }
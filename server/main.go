@@ -50,7 +50,7 @@ func Main(server *server.Server) {
 
 	// img, err := imageanalysis.NewImageAnalyzer[Res](imageanalysis.NewImageAnalyzerParams{
 	// 	ObjectStore: server.Services.Minio,
-	// 	AI:          server.Services.OpenAI,
+	// 	AI:          server.Services.LLM.Default(),
 	// 	Postgres:    server.Services.Postgres,
 	// })
 	// if err != nil {
@@ -1,6 +1,7 @@
 package setup
 
 import (
+	"context"
 	"log"
 	"server/api/serviceaccess"
 	"server/api/tools/features/sessions"
@@ -31,6 +32,12 @@ func Handlers(mux *chi.Mux, services *serviceaccess.Access) {
 		log.Fatal(err)
 	}
 
+	// Background analysis jobs run for the lifetime of the server
+	core.StartAnalysisWorkers(context.Background())
+
+	// Extracted pages without embeddings are embedded in the background for search
+	core.StartEmbeddingBackfill(context.Background())
+
 	// Create single shared database query client
 	queries := sqlgen.New(services.Postgres)
 
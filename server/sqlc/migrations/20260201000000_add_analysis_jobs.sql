@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE analysis_job_status AS ENUM (
+    'queued',
+    'extracting',
+    'analyzing',
+    'succeeded',
+    'failed'
+);
+
+CREATE TABLE analysis_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES user_accounts(id),
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    type analysis_type NOT NULL,
+    status analysis_job_status NOT NULL DEFAULT 'queued',
+    analysis_id UUID REFERENCES collection_analyses(id),
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_analysis_jobs_status_created_at
+ON analysis_jobs (status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analysis_jobs;
+DROP TYPE IF EXISTS analysis_job_status;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An empty provider means the deployment default at the time the job runs
+ALTER TABLE analysis_jobs
+ADD COLUMN provider VARCHAR NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE analysis_jobs
+DROP COLUMN IF EXISTS provider;
+-- +goose StatementEnd
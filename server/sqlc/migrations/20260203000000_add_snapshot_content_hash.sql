@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Hash of the snapshot's combined content and the analysis parameters, used to reuse earlier analyses.
+-- Snapshots taken before hashing keep an empty hash and are never matched.
+ALTER TABLE collection_snapshots
+ADD COLUMN content_hash VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_snapshots_collection_hash
+ON collection_snapshots (collection_id, content_hash);
+
+-- Jobs that skip reuse and always call the model
+ALTER TABLE analysis_jobs
+ADD COLUMN regenerate BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE analysis_jobs
+DROP COLUMN IF EXISTS regenerate;
+
+DROP INDEX IF EXISTS idx_snapshots_collection_hash;
+
+ALTER TABLE collection_snapshots
+DROP COLUMN IF EXISTS content_hash;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE extraction_method AS ENUM (
+    'text_layer', -- Read from a PDF's embedded text
+    'vision'      -- Transcribed from an image by the LLM
+);
+
+-- Concurrent analyses could extract the same document twice. Keep one extraction per document,
+-- so existing rows fit the unique index below.
+DELETE FROM document_extractions e
+USING document_extractions kept
+WHERE e.document_id = kept.document_id
+  AND e.id > kept.id;
+
+-- Multi-page documents store one extraction per page. Existing rows are single images.
+ALTER TABLE document_extractions
+ADD COLUMN page INTEGER NOT NULL DEFAULT 1,
+ADD COLUMN method extraction_method NOT NULL DEFAULT 'vision';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_extractions_document_page
+ON document_extractions (document_id, page);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_extractions_document_page;
+
+ALTER TABLE document_extractions
+DROP COLUMN IF EXISTS method,
+DROP COLUMN IF EXISTS page;
+
+DROP TYPE IF EXISTS extraction_method;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Anchors for the [S#] labels in combined_content: [{"label", "documentId", "title", "page"}]
+ALTER TABLE collection_snapshots
+ADD COLUMN sources JSONB NOT NULL DEFAULT '[]';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collection_snapshots
+DROP COLUMN IF EXISTS sources;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Analysis kinds are registered in code, so new kinds no longer need a migration
+ALTER TABLE collection_analyses
+ALTER COLUMN type TYPE VARCHAR USING type::text;
+
+ALTER TABLE analysis_jobs
+ALTER COLUMN type TYPE VARCHAR USING type::text;
+
+DROP TYPE IF EXISTS analysis_type;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Fails if rows use kinds outside the original enum
+CREATE TYPE analysis_type AS ENUM (
+    'summary',
+    'flashcards',
+    'quiz',
+    'deep_summary'
+);
+
+ALTER TABLE analysis_jobs
+ALTER COLUMN type TYPE analysis_type USING type::analysis_type;
+
+ALTER TABLE collection_analyses
+ALTER COLUMN type TYPE analysis_type USING type::analysis_type;
+-- +goose StatementEnd
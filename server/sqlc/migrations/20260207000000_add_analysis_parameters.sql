@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tuning parameters (count, difficulty, audience, length, focus topics) an analysis was requested with.
+-- Analyses from before parameters existed used the defaults, stored as an empty object.
+ALTER TABLE collection_analyses
+ADD COLUMN parameters JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE analysis_jobs
+ADD COLUMN parameters JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE analysis_jobs
+DROP COLUMN IF EXISTS parameters;
+
+ALTER TABLE collection_analyses
+DROP COLUMN IF EXISTS parameters;
+-- +goose StatementEnd
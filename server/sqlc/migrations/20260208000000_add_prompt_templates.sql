@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-course prompt templates for an analysis kind. Templates are immutable: saving one adds
+-- a new version, and the highest version that hasn't been retired is the one analyses use.
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    creator_id UUID NOT NULL,
+    course TEXT NOT NULL,
+    kind VARCHAR NOT NULL,
+    version INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMP,
+    FOREIGN KEY (course, creator_id) REFERENCES courses(name, creator_id) ON DELETE CASCADE,
+    UNIQUE (creator_id, course, kind, version)
+);
+
+-- The template version that produced an analysis. NULL means the default instructions.
+ALTER TABLE collection_analyses
+ADD COLUMN template_id UUID REFERENCES prompt_templates(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collection_analyses
+DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS prompt_templates;
+-- +goose StatementEnd
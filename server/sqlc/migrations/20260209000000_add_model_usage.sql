@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What produced each analysis and extraction, and what it cost. prompt_hash is the SHA-256 of the
+-- instructions sent with the first model request. Rows from before this was recorded are left blank.
+ALTER TABLE collection_analyses
+ADD COLUMN model VARCHAR NOT NULL DEFAULT '',
+ADD COLUMN prompt_hash VARCHAR NOT NULL DEFAULT '',
+ADD COLUMN input_tokens BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN output_tokens BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN latency_ms BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN requests INTEGER NOT NULL DEFAULT 0;
+
+-- Pages read from a PDF's text layer make no model request and keep the defaults
+ALTER TABLE document_extractions
+ADD COLUMN model VARCHAR NOT NULL DEFAULT '',
+ADD COLUMN prompt_hash VARCHAR NOT NULL DEFAULT '',
+ADD COLUMN input_tokens BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN output_tokens BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN latency_ms BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE document_extractions
+DROP COLUMN IF EXISTS latency_ms,
+DROP COLUMN IF EXISTS output_tokens,
+DROP COLUMN IF EXISTS input_tokens,
+DROP COLUMN IF EXISTS prompt_hash,
+DROP COLUMN IF EXISTS model;
+
+ALTER TABLE collection_analyses
+DROP COLUMN IF EXISTS requests,
+DROP COLUMN IF EXISTS latency_ms,
+DROP COLUMN IF EXISTS output_tokens,
+DROP COLUMN IF EXISTS input_tokens,
+DROP COLUMN IF EXISTS prompt_hash,
+DROP COLUMN IF EXISTS model;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE usage_kind AS ENUM ('extraction', 'analysis');
+
+-- One row per extracted document or analysis run, totalling the model requests it made
+CREATE TABLE IF NOT EXISTS usage_events (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES user_accounts(id) ON DELETE CASCADE,
+    kind usage_kind NOT NULL,
+    requests INTEGER NOT NULL,
+    input_tokens BIGINT NOT NULL,
+    output_tokens BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_user_created
+ON usage_events (user_id, created_at);
+
+-- Per-user quota overrides. NULL keeps the deployment default, 0 removes the limit.
+CREATE TABLE IF NOT EXISTS usage_limit_overrides (
+    user_id UUID PRIMARY KEY NOT NULL REFERENCES user_accounts(id) ON DELETE CASCADE,
+    daily_tokens BIGINT,
+    monthly_tokens BIGINT,
+    daily_requests INTEGER,
+    monthly_requests INTEGER,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS usage_limit_overrides;
+DROP TABLE IF EXISTS usage_events;
+DROP TYPE IF EXISTS usage_kind;
+-- +goose StatementEnd
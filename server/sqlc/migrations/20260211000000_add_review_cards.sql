@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A flashcard a user has enrolled for review, scheduled with SM-2. The card's text is copied from the
+-- analysis when enrolled so regenerating the analysis doesn't change cards under a user mid-schedule.
+CREATE TABLE IF NOT EXISTS review_cards (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES user_accounts(id) ON DELETE CASCADE,
+    analysis_id UUID NOT NULL REFERENCES collection_analyses(id) ON DELETE CASCADE,
+    card_index INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    sources JSONB NOT NULL DEFAULT '[]',
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    due_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, analysis_id, card_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_cards_user_due
+ON review_cards (user_id, due_at);
+
+-- Every grade given, with the schedule it produced
+CREATE TABLE IF NOT EXISTS review_logs (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    card_id UUID NOT NULL REFERENCES review_cards(id) ON DELETE CASCADE,
+    grade SMALLINT NOT NULL,
+    interval_days INTEGER NOT NULL,
+    ease_factor DOUBLE PRECISION NOT NULL,
+    reviewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_logs;
+DROP TABLE IF EXISTS review_cards;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A user's attempt at a quiz analysis. submitted_at, score and total stay NULL until it's graded.
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES user_accounts(id) ON DELETE CASCADE,
+    analysis_id UUID NOT NULL REFERENCES collection_analyses(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    submitted_at TIMESTAMPTZ,
+    score INTEGER,
+    total INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_analysis
+ON quiz_attempts (user_id, analysis_id, started_at);
+
+-- How each question of a graded attempt was answered. The question and correct answer are copied
+-- so history reads the same however the quiz changes later. selected_index is NULL when skipped.
+CREATE TABLE IF NOT EXISTS quiz_attempt_answers (
+    attempt_id UUID NOT NULL REFERENCES quiz_attempts(id) ON DELETE CASCADE,
+    question_index INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    selected_index INTEGER,
+    correct_index INTEGER NOT NULL,
+    correct BOOLEAN NOT NULL,
+    time_ms BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (attempt_id, question_index)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS quiz_attempt_answers;
+DROP TABLE IF EXISTS quiz_attempts;
+-- +goose StatementEnd
@@ -0,0 +1,90 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The items of list-shaped analyses (flashcards, quiz questions, glossary terms, ...) as their own rows.
+-- generated is the model's item and never changes. edited is the user's version once they change it,
+-- and items users add have only that. Either is JSON null when absent. Deleting just marks the item,
+-- so generated items never come back.
+CREATE TABLE IF NOT EXISTS analysis_items (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    analysis_id UUID NOT NULL REFERENCES collection_analyses(id) ON DELETE CASCADE,
+    generated_index INTEGER,
+    generated JSONB NOT NULL DEFAULT 'null',
+    edited JSONB NOT NULL DEFAULT 'null',
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    UNIQUE (analysis_id, generated_index),
+    CHECK (generated <> 'null' OR edited <> 'null')
+);
+
+CREATE INDEX IF NOT EXISTS idx_analysis_items_analysis_position
+ON analysis_items (analysis_id, position);
+
+INSERT INTO analysis_items (analysis_id, generated_index, generated, position)
+SELECT a.id, e.ordinality - 1, e.value, e.ordinality - 1
+FROM collection_analyses a
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(a.result) = 'array' THEN a.result ELSE '[]'::jsonb END
+) WITH ORDINALITY AS e(value, ordinality);
+
+-- Review cards point at their item and read its current text, so edits show up in reviews
+ALTER TABLE review_cards
+ADD COLUMN item_id UUID REFERENCES analysis_items(id) ON DELETE CASCADE;
+
+UPDATE review_cards r
+SET item_id = i.id
+FROM analysis_items i
+WHERE i.analysis_id = r.analysis_id
+  AND i.generated_index = r.card_index;
+
+ALTER TABLE review_cards
+ALTER COLUMN item_id SET NOT NULL,
+DROP CONSTRAINT IF EXISTS review_cards_user_id_analysis_id_card_index_key,
+DROP COLUMN card_index,
+DROP COLUMN question,
+DROP COLUMN answer,
+DROP COLUMN sources,
+ADD CONSTRAINT review_cards_user_id_item_id_key UNIQUE (user_id, item_id);
+
+-- Attempts are graded against the questions as they were served, since items can now change mid-attempt
+ALTER TABLE quiz_attempts
+ADD COLUMN questions JSONB NOT NULL DEFAULT '[]';
+
+UPDATE quiz_attempts q
+SET questions = COALESCE((
+    SELECT jsonb_agg(i.generated ORDER BY i.position)
+    FROM analysis_items i
+    WHERE i.analysis_id = q.analysis_id
+), '[]')
+WHERE q.submitted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE quiz_attempts
+DROP COLUMN IF EXISTS questions;
+
+-- Card text is restored from the items as they stand
+ALTER TABLE review_cards
+DROP CONSTRAINT IF EXISTS review_cards_user_id_item_id_key,
+ADD COLUMN card_index INTEGER,
+ADD COLUMN question TEXT NOT NULL DEFAULT '',
+ADD COLUMN answer TEXT NOT NULL DEFAULT '',
+ADD COLUMN sources JSONB NOT NULL DEFAULT '[]';
+
+UPDATE review_cards r
+SET card_index = i.position,
+    question = COALESCE(NULLIF(i.edited, 'null'), i.generated)->>'question',
+    answer = COALESCE(NULLIF(i.edited, 'null'), i.generated)->>'answer',
+    sources = COALESCE(COALESCE(NULLIF(i.edited, 'null'), i.generated)->'sources', '[]')
+FROM analysis_items i
+WHERE i.id = r.item_id;
+
+ALTER TABLE review_cards
+DROP COLUMN item_id,
+ALTER COLUMN card_index SET NOT NULL,
+ADD CONSTRAINT review_cards_user_id_analysis_id_card_index_key UNIQUE (user_id, analysis_id, card_index);
+
+DROP TABLE IF EXISTS analysis_items;
+-- +goose StatementEnd
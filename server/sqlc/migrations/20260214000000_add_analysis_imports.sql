@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Flashcards and quiz questions imported from files (Anki packages, CSV/TSV, Quizlet) live in an imported
+-- analysis of their collection, one per kind. Their items are keyed by the id they have in the file, so
+-- importing an updated file again replaces generated with the file's version and keeps the user's edits.
+ALTER TABLE collection_analyses
+ADD COLUMN imported BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE analysis_items
+ADD COLUMN external_id TEXT,
+ADD CONSTRAINT analysis_items_analysis_id_external_id_key UNIQUE (analysis_id, external_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM collection_analyses
+WHERE imported;
+
+ALTER TABLE analysis_items
+DROP CONSTRAINT IF EXISTS analysis_items_analysis_id_external_id_key,
+DROP COLUMN IF EXISTS external_id;
+
+ALTER TABLE collection_analyses
+DROP COLUMN IF EXISTS imported;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A user's conversation with their notes, over one collection or every collection of a course.
+-- title is taken from the first question; it is empty until one is asked.
+CREATE TABLE IF NOT EXISTS chats (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES user_accounts(id) ON DELETE CASCADE,
+    collection_id UUID REFERENCES collections(id) ON DELETE CASCADE,
+    course TEXT,
+    title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((collection_id IS NULL) <> (course IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_chats_user_updated
+ON chats (user_id, updated_at);
+
+-- The questions and answers of a chat. sources are the pages an answer cites, as resolved Source objects;
+-- the answer's [1], [2], ... markers number them. Answers record what they cost like analyses.
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    chat_id UUID NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('user', 'assistant')),
+    content TEXT NOT NULL,
+    sources JSONB NOT NULL DEFAULT '[]',
+    model VARCHAR NOT NULL DEFAULT '',
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    requests INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_chat_created
+ON chat_messages (chat_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS chats;
+-- +goose StatementEnd
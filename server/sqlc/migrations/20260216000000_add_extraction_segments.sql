@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- Passages of extracted pages, embedded for semantic search. Vectors are only comparable with those of
+-- the same model, so each records its model and search only reads the current model's. Search scans a
+-- user's own segments exactly rather than through an approximate index, which filtering by owner would
+-- leave with too few candidates.
+CREATE TABLE IF NOT EXISTS extraction_segments (
+    id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+    extraction_id UUID NOT NULL REFERENCES document_extractions(id) ON DELETE CASCADE,
+    segment INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    model VARCHAR NOT NULL,
+    embedding vector(768) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (extraction_id, model, segment)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TYPE usage_kind ADD VALUE IF NOT EXISTS 'embedding';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Postgres can't drop an enum value, so usage_kind keeps 'embedding'
+DROP TABLE IF EXISTS extraction_segments;
+DROP EXTENSION IF EXISTS vector;
+-- +goose StatementEnd
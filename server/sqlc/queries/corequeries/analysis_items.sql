@@ -0,0 +1,59 @@
+-- name: CreateAnalysisItem :one
+INSERT INTO analysis_items (analysis_id, generated_index, generated, edited, position)
+VALUES (@analysis_id, sqlc.narg(generated_index), @generated, @edited, @position)
+RETURNING *;
+
+-- name: GetAnalysisItems :many
+SELECT * FROM analysis_items
+WHERE analysis_id = @analysis_id
+  AND deleted_at IS NULL
+ORDER BY position, created_at;
+
+-- name: GetUserAnalysisItem :one
+-- A live item of an analysis of one of the user's collections
+SELECT i.*, a.type AS analysis_type, s.collection_id
+FROM analysis_items i
+JOIN collection_analyses a ON a.id = i.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE i.id = @id
+  AND i.deleted_at IS NULL
+  AND c.creator_id = @user_id;
+
+-- name: NextAnalysisItemPosition :one
+SELECT (COALESCE(MAX(position), -1) + 1)::integer
+FROM analysis_items
+WHERE analysis_id = @analysis_id;
+
+-- name: SetAnalysisItemEdit :one
+-- A JSON null edit reverts the item to its generated content
+UPDATE analysis_items
+SET edited = @edited,
+    updated_at = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: SetAnalysisItemPosition :exec
+UPDATE analysis_items
+SET position = @position,
+    updated_at = now()
+WHERE id = @id;
+
+-- name: DeleteAnalysisItem :exec
+UPDATE analysis_items
+SET deleted_at = now()
+WHERE id = @id;
+
+-- name: RefreshAnalysisResult :exec
+-- Rebuilds an analysis' result from its live items as they stand, edits included
+UPDATE collection_analyses a
+SET result = COALESCE((
+    SELECT jsonb_agg(
+        CASE WHEN i.edited <> 'null' THEN i.edited ELSE i.generated END
+        ORDER BY i.position, i.created_at
+    )
+    FROM analysis_items i
+    WHERE i.analysis_id = a.id
+      AND i.deleted_at IS NULL
+), '[]')
+WHERE a.id = @id;
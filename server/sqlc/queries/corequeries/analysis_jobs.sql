@@ -0,0 +1,73 @@
+-- name: CreateAnalysisJob :one
+-- Jobs created already running (synchronous analyses) are stamped as started
+INSERT INTO analysis_jobs (user_id, collection_id, type, provider, parameters, regenerate, status, started_at)
+VALUES (
+    @user_id, @collection_id, @type, @provider, @parameters, @regenerate, @status,
+    CASE WHEN @status = 'queued' THEN NULL ELSE now() END
+)
+RETURNING *;
+
+-- name: GetAnalysisJob :one
+SELECT * FROM analysis_jobs
+WHERE id = @id
+  AND user_id = @user_id;
+
+-- name: ClaimNextAnalysisJob :one
+-- Picks the oldest queued job, skipping rows other workers have already locked
+UPDATE analysis_jobs
+SET status = 'extracting',
+    attempts = attempts + 1,
+    started_at = now(),
+    updated_at = now()
+WHERE id = (
+    SELECT j.id FROM analysis_jobs j
+    WHERE j.status = 'queued'
+    ORDER BY j.created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: SetAnalysisJobStatus :exec
+UPDATE analysis_jobs
+SET status = @status,
+    updated_at = now()
+WHERE id = @id;
+
+-- name: CompleteAnalysisJob :exec
+UPDATE analysis_jobs
+SET status = 'succeeded',
+    analysis_id = @analysis_id::uuid,
+    error = NULL,
+    updated_at = now(),
+    finished_at = now()
+WHERE id = @id;
+
+-- name: FailAnalysisJob :exec
+UPDATE analysis_jobs
+SET status = 'failed',
+    error = @error::text,
+    updated_at = now(),
+    finished_at = now()
+WHERE id = @id;
+
+-- name: FailAbandonedAnalysisJobs :execrows
+-- Stale jobs that have used up their attempts are failed rather than requeued, so a job that keeps
+-- taking the server down with it isn't retried forever
+UPDATE analysis_jobs
+SET status = 'failed',
+    error = 'abandoned after ' || attempts || ' attempts',
+    updated_at = now(),
+    finished_at = now()
+WHERE status IN ('extracting', 'analyzing')
+  AND updated_at < @stale_before
+  AND attempts >= @max_attempts::int;
+
+-- name: RequeueStaleAnalysisJobs :execrows
+-- Jobs left running by a crashed or restarted server go back on the queue
+UPDATE analysis_jobs
+SET status = 'queued',
+    updated_at = now()
+WHERE status IN ('extracting', 'analyzing')
+  AND updated_at < @stale_before
+  AND attempts < @max_attempts::int;
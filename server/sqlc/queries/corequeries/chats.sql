@@ -0,0 +1,47 @@
+-- name: CreateChat :one
+INSERT INTO chats (user_id, collection_id, course)
+VALUES (@user_id, sqlc.narg(collection_id), sqlc.narg(course))
+RETURNING *;
+
+-- name: GetChat :one
+SELECT * FROM chats
+WHERE id = @id
+  AND user_id = @user_id;
+
+-- name: GetChats :many
+-- The user's chats over a collection or a course, most recently active first
+SELECT * FROM chats
+WHERE user_id = @user_id
+  AND (sqlc.narg(collection_id)::uuid IS NULL OR collection_id = sqlc.narg(collection_id))
+  AND (sqlc.narg(course)::text IS NULL OR course = sqlc.narg(course))
+ORDER BY updated_at DESC;
+
+-- name: DeleteChat :execrows
+DELETE FROM chats
+WHERE id = @id
+  AND user_id = @user_id;
+
+-- name: GetChatMessages :many
+SELECT * FROM chat_messages
+WHERE chat_id = @chat_id
+ORDER BY created_at, id;
+
+-- name: CreateChatMessage :one
+-- created_at is given so a question and its answer, stored together, keep their order
+INSERT INTO chat_messages (
+    chat_id, role, content, sources,
+    model, input_tokens, output_tokens, latency_ms, requests, created_at
+)
+VALUES (
+    @chat_id, @role, @content, @sources,
+    @model, @input_tokens, @output_tokens, @latency_ms, @requests, @created_at
+)
+RETURNING *;
+
+-- name: TouchChat :one
+-- Marks a chat active, naming it after its first question
+UPDATE chats
+SET updated_at = NOW(),
+    title = CASE WHEN title = '' THEN @title ELSE title END
+WHERE id = @id
+RETURNING *;
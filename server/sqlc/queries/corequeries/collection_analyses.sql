@@ -1,6 +1,9 @@
 -- name: CreateCollectionAnalysis :one
-INSERT INTO collection_analyses (snapshot_id, type, result)
-VALUES ($1, $2, $3)
+INSERT INTO collection_analyses (
+    snapshot_id, type, parameters, template_id, result,
+    model, prompt_hash, input_tokens, output_tokens, latency_ms, requests
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: GetCollectionAnalysesByCollection :many
@@ -12,8 +15,8 @@ ORDER BY a.created_at DESC;
 
 
 -- name: CreateCollectionSnapshot :one
-INSERT INTO collection_snapshots (collection_id, combined_content)
-VALUES ($1, $2)
+INSERT INTO collection_snapshots (collection_id, combined_content, content_hash, sources)
+VALUES ($1, $2, $3, $4)
 RETURNING id, collection_id, combined_content, created_at;
 
 -- name: GetCollectionSnapshots :many
@@ -22,6 +25,23 @@ FROM collection_snapshots
 WHERE collection_id = $1
 ORDER BY created_at DESC;
 
+-- name: GetAnalysisByContentHash :one
+-- The most recent analysis of a snapshot with identical content and parameters
+SELECT a.*
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+WHERE s.collection_id = @collection_id
+  AND s.content_hash = @content_hash
+  AND a.type = @type
+  AND NOT a.imported
+ORDER BY a.created_at DESC
+LIMIT 1;
+
 -- name: GetAnalysis :one
-SELECT * FROM collection_analyses
-WHERE id = @id;
\ No newline at end of file
+SELECT a.*
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE a.id = @id
+  AND s.collection_id = @collection_id
+  AND c.creator_id = @user_id;
\ No newline at end of file
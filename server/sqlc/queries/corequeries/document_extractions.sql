@@ -1,7 +1,11 @@
--- name: CreateDocumentExtraction :one
-INSERT INTO document_extractions (document_id, content)
-VALUES ($1, $2)
-RETURNING id;
+-- name: CreateDocumentExtraction :execrows
+-- Stores nothing when the page was already stored, by another job extracting the same document
+INSERT INTO document_extractions (
+    document_id, page, method, content,
+    model, prompt_hash, input_tokens, output_tokens, latency_ms
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (document_id, page) DO NOTHING;
 
 -- name: HasDocumentExtraction :one
 SELECT EXISTS (
@@ -11,7 +15,18 @@ SELECT EXISTS (
 );
 
 -- name: GetDocumentExtractionsByCollection :many
-SELECT e.content
+SELECT e.document_id, d.title, e.page, e.content
 FROM document_extractions e
 JOIN documents d ON d.id = e.document_id
-WHERE d.collection_id = $1;
\ No newline at end of file
+WHERE d.collection_id = $1
+ORDER BY d.id, e.page; -- Stable order keeps snapshot content hashes comparable
+
+-- name: GetDocumentExtractionsByCourse :many
+-- Every page of every collection in one of the user's courses, in the same columns and order as by collection
+SELECT e.document_id, d.title, e.page, e.content
+FROM document_extractions e
+JOIN documents d ON d.id = e.document_id
+JOIN collections c ON c.id = d.collection_id
+WHERE c.creator_id = @user_id
+  AND c.course = @course
+ORDER BY d.id, e.page;
@@ -0,0 +1,30 @@
+-- name: GetUserAnalysisCollection :one
+-- One of the user's analyses with the collection it was run on
+SELECT a.id, a.type, c.id AS collection_id, c.title AS collection_title, c.course
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE a.id = @id
+  AND c.creator_id = @user_id;
+
+-- name: GetLatestCourseAnalyses :many
+-- The newest analysis of a type for each of the user's collections in a course, by collection title
+SELECT * FROM (
+    SELECT DISTINCT ON (c.id) a.id, a.type, c.id AS collection_id, c.title AS collection_title, c.course
+    FROM collection_analyses a
+    JOIN collection_snapshots s ON s.id = a.snapshot_id
+    JOIN collections c ON c.id = s.collection_id
+    WHERE c.creator_id = @user_id
+      AND c.course = @course
+      AND a.type = @type
+    ORDER BY c.id, a.created_at DESC
+) latest
+ORDER BY latest.collection_title, latest.collection_id;
+
+-- name: GetLatestCollectionAnalyses :many
+-- The newest analysis of each type run on a collection
+SELECT DISTINCT ON (a.type) a.*
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+WHERE s.collection_id = @collection_id
+ORDER BY a.type, a.created_at DESC;
@@ -0,0 +1,48 @@
+-- name: GetUnembeddedExtractions :many
+-- Pages with content but no segments from the given model, optionally of one document, with the user
+-- who owns them, in a stable order so a backfill works through the backlog page by page. Pages up to the
+-- given one are skipped, so a pass moves on past pages that failed instead of fetching them again
+SELECT e.id, e.document_id, e.page, e.content, c.creator_id AS user_id
+FROM document_extractions e
+JOIN documents d ON d.id = e.document_id
+JOIN collections c ON c.id = d.collection_id
+WHERE e.content ~ '\S'
+  AND (sqlc.narg(document_id)::uuid IS NULL OR e.document_id = sqlc.narg(document_id))
+  AND (
+    sqlc.narg(after_document_id)::uuid IS NULL
+    OR (e.document_id, e.page) > (sqlc.narg(after_document_id)::uuid, sqlc.narg(after_page)::int)
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM extraction_segments s
+    WHERE s.extraction_id = e.id
+      AND s.model = @model
+  )
+ORDER BY e.document_id, e.page
+LIMIT @max_extractions;
+
+-- name: DeleteExtractionSegments :exec
+-- Drops a page's segments, including other models', before it is embedded again
+DELETE FROM extraction_segments
+WHERE extraction_id = @extraction_id;
+
+-- name: CreateExtractionSegment :exec
+INSERT INTO extraction_segments (extraction_id, segment, content, model, embedding)
+VALUES (@extraction_id, @segment, @content, @model, @embedding);
+
+-- name: SearchExtractionSegments :many
+-- The user's segments closest to a query embedding, optionally within one collection or course.
+-- score is the cosine similarity, from -1 to 1.
+SELECT s.id, s.content, e.page, d.id AS document_id, d.title AS document_title,
+       c.id AS collection_id, c.title AS collection_title, c.course,
+       (1 - (s.embedding <=> sqlc.arg(embedding)::vector))::float8 AS score
+FROM extraction_segments s
+JOIN document_extractions e ON e.id = s.extraction_id
+JOIN documents d ON d.id = e.document_id
+JOIN collections c ON c.id = d.collection_id
+WHERE c.creator_id = @user_id
+  AND s.model = @model
+  AND (sqlc.narg(collection_id)::uuid IS NULL OR c.id = sqlc.narg(collection_id))
+  AND (sqlc.narg(course)::text IS NULL OR c.course = sqlc.narg(course))
+ORDER BY s.embedding <=> sqlc.arg(embedding)::vector
+LIMIT @max_results;
@@ -0,0 +1,32 @@
+-- name: LockUserCollection :one
+-- Serializes imports into a collection
+SELECT id FROM collections
+WHERE id = @id
+  AND creator_id = @user_id
+FOR UPDATE;
+
+-- name: GetImportedAnalysis :one
+SELECT a.*
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+WHERE s.collection_id = @collection_id
+  AND a.type = @type
+  AND a.imported
+ORDER BY a.created_at
+LIMIT 1;
+
+-- name: CreateImportedAnalysis :one
+INSERT INTO collection_analyses (snapshot_id, type, result, imported)
+VALUES (@snapshot_id, @type, '[]', true)
+RETURNING *;
+
+-- name: UpsertImportedAnalysisItem :one
+-- Returns nothing when the item is already imported as it is, or the user deleted it
+INSERT INTO analysis_items (analysis_id, external_id, generated, position)
+VALUES (@analysis_id, @external_id, @generated, @position)
+ON CONFLICT (analysis_id, external_id) DO UPDATE
+SET generated = EXCLUDED.generated,
+    updated_at = NOW()
+WHERE analysis_items.deleted_at IS NULL
+  AND analysis_items.generated IS DISTINCT FROM EXCLUDED.generated
+RETURNING (xmax = 0)::boolean AS created;
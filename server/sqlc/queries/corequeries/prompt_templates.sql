@@ -0,0 +1,39 @@
+-- name: CreatePromptTemplate :one
+-- Versions count up per course and kind, starting at 1
+INSERT INTO prompt_templates (creator_id, course, kind, version, body)
+VALUES (
+    @creator_id, @course, @kind,
+    (
+        SELECT COALESCE(MAX(t.version), 0) + 1
+        FROM prompt_templates t
+        WHERE t.creator_id = @creator_id
+          AND t.course = @course
+          AND t.kind = @kind
+    ),
+    @body
+)
+RETURNING *;
+
+-- name: GetPromptTemplates :many
+SELECT * FROM prompt_templates
+WHERE creator_id = @creator_id
+  AND course = @course
+ORDER BY kind, version DESC;
+
+-- name: GetActivePromptTemplate :one
+SELECT * FROM prompt_templates
+WHERE creator_id = @creator_id
+  AND course = @course
+  AND kind = @kind
+  AND retired_at IS NULL
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: RetirePromptTemplates :execrows
+-- Analyses of the kind go back to the default instructions until a new version is saved
+UPDATE prompt_templates
+SET retired_at = now()
+WHERE creator_id = @creator_id
+  AND course = @course
+  AND kind = @kind
+  AND retired_at IS NULL;
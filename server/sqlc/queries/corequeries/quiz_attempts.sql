@@ -0,0 +1,55 @@
+-- name: CreateQuizAttempt :one
+INSERT INTO quiz_attempts (user_id, analysis_id, questions)
+VALUES (@user_id, @analysis_id, @questions)
+RETURNING *;
+
+-- name: GetQuizAttempt :one
+SELECT q.*, c.id AS collection_id, c.title AS collection_title, c.course
+FROM quiz_attempts q
+JOIN collection_analyses a ON a.id = q.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE q.id = @id
+  AND q.user_id = @user_id;
+
+-- name: GetQuizAttemptsByAnalysis :many
+SELECT q.*, c.id AS collection_id, c.title AS collection_title, c.course
+FROM quiz_attempts q
+JOIN collection_analyses a ON a.id = q.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE q.user_id = @user_id
+  AND q.analysis_id = @analysis_id
+ORDER BY q.started_at DESC;
+
+-- name: GetQuizAttemptsByCourse :many
+SELECT q.*, c.id AS collection_id, c.title AS collection_title, c.course
+FROM quiz_attempts q
+JOIN collection_analyses a ON a.id = q.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE q.user_id = @user_id
+  AND c.course = @course
+ORDER BY q.started_at DESC;
+
+-- name: CompleteQuizAttempt :execrows
+-- Affects no rows when the attempt was already submitted
+UPDATE quiz_attempts
+SET submitted_at = @submitted_at,
+    score = @score,
+    total = @total
+WHERE id = @id
+  AND submitted_at IS NULL;
+
+-- name: CreateQuizAttemptAnswer :exec
+INSERT INTO quiz_attempt_answers (
+    attempt_id, question_index, question, selected_index, correct_index, correct, time_ms
+)
+VALUES (
+    @attempt_id, @question_index, @question, @selected_index, @correct_index, @correct, @time_ms
+);
+
+-- name: GetQuizAttemptAnswers :many
+SELECT * FROM quiz_attempt_answers
+WHERE attempt_id = @attempt_id
+ORDER BY question_index;
@@ -0,0 +1,85 @@
+-- name: GetUserAnalysis :one
+-- An analysis of one of the user's collections
+SELECT a.*
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE a.id = @id
+  AND c.creator_id = @user_id;
+
+-- name: EnrollReviewCard :exec
+-- Cards already enrolled keep their schedule
+INSERT INTO review_cards (user_id, analysis_id, item_id)
+VALUES (@user_id, @analysis_id, @item_id)
+ON CONFLICT (user_id, item_id) DO NOTHING;
+
+-- name: GetReviewCardsByAnalysis :many
+SELECT r.*, i.position, COALESCE(NULLIF(i.edited, 'null'), i.generated)::jsonb AS content,
+       c.id AS collection_id, c.title AS collection_title, c.course
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.user_id = @user_id
+  AND r.analysis_id = @analysis_id
+  AND i.deleted_at IS NULL
+ORDER BY i.position;
+
+-- name: GetDueReviewCards :many
+-- Cards due by a time across the user's collections, most overdue first. Cards whose item was deleted are skipped.
+-- Course and collection narrow the queue when set.
+SELECT r.*, i.position, COALESCE(NULLIF(i.edited, 'null'), i.generated)::jsonb AS content,
+       c.id AS collection_id, c.title AS collection_title, c.course
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.user_id = @user_id
+  AND r.due_at <= @due_by
+  AND i.deleted_at IS NULL
+  AND (sqlc.narg(course)::text IS NULL OR c.course = sqlc.narg(course))
+  AND (sqlc.narg(collection_id)::uuid IS NULL OR c.id = sqlc.narg(collection_id))
+ORDER BY r.due_at, i.position
+LIMIT @max_cards;
+
+-- name: CountDueReviewCards :one
+SELECT COUNT(*)
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.user_id = @user_id
+  AND r.due_at <= @due_by
+  AND i.deleted_at IS NULL
+  AND (sqlc.narg(course)::text IS NULL OR c.course = sqlc.narg(course))
+  AND (sqlc.narg(collection_id)::uuid IS NULL OR c.id = sqlc.narg(collection_id));
+
+-- name: GetReviewCardForUpdate :one
+-- Locks the card so concurrent grades are applied one after the other
+SELECT r.*, i.position, COALESCE(NULLIF(i.edited, 'null'), i.generated)::jsonb AS content,
+       c.id AS collection_id, c.title AS collection_title, c.course
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.id = @id
+  AND r.user_id = @user_id
+  AND i.deleted_at IS NULL
+FOR UPDATE OF r;
+
+-- name: UpdateReviewSchedule :exec
+UPDATE review_cards
+SET repetitions = @repetitions,
+    interval_days = @interval_days,
+    ease_factor = @ease_factor,
+    due_at = @due_at,
+    last_reviewed_at = @reviewed_at
+WHERE id = @id;
+
+-- name: CreateReviewLog :exec
+INSERT INTO review_logs (card_id, grade, interval_days, ease_factor, reviewed_at)
+VALUES (@card_id, @grade, @interval_days, @ease_factor, @reviewed_at);
@@ -0,0 +1,17 @@
+-- name: CreateUsageEvent :exec
+INSERT INTO usage_events (user_id, kind, requests, input_tokens, output_tokens)
+VALUES (@user_id, @kind, @requests, @input_tokens, @output_tokens);
+
+-- name: GetUsageTotals :many
+-- Consumption since a point in time, split by kind
+SELECT kind,
+       COALESCE(SUM(requests), 0)::bigint AS requests,
+       COALESCE(SUM(input_tokens + output_tokens), 0)::bigint AS tokens
+FROM usage_events
+WHERE user_id = @user_id
+  AND created_at >= @since
+GROUP BY kind;
+
+-- name: GetUsageLimitOverride :one
+SELECT * FROM usage_limit_overrides
+WHERE user_id = @user_id;
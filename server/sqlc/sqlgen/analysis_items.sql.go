@@ -0,0 +1,239 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: analysis_items.sql
+
+package sqlgen
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createAnalysisItem = `-- name: CreateAnalysisItem :one
+INSERT INTO analysis_items (analysis_id, generated_index, generated, edited, position)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, analysis_id, generated_index, generated, edited, position, created_at, updated_at, deleted_at, external_id
+`
+
+type CreateAnalysisItemParams struct {
+	AnalysisID     uuid.UUID
+	GeneratedIndex sql.NullInt32
+	Generated      json.RawMessage
+	Edited         json.RawMessage
+	Position       int32
+}
+
+func (q *Queries) CreateAnalysisItem(ctx context.Context, arg CreateAnalysisItemParams) (AnalysisItem, error) {
+	row := q.db.QueryRowContext(ctx, createAnalysisItem,
+		arg.AnalysisID,
+		arg.GeneratedIndex,
+		arg.Generated,
+		arg.Edited,
+		arg.Position,
+	)
+	var i AnalysisItem
+	err := row.Scan(
+		&i.ID,
+		&i.AnalysisID,
+		&i.GeneratedIndex,
+		&i.Generated,
+		&i.Edited,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const deleteAnalysisItem = `-- name: DeleteAnalysisItem :exec
+UPDATE analysis_items
+SET deleted_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) DeleteAnalysisItem(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAnalysisItem, id)
+	return err
+}
+
+const getAnalysisItems = `-- name: GetAnalysisItems :many
+SELECT id, analysis_id, generated_index, generated, edited, position, created_at, updated_at, deleted_at, external_id FROM analysis_items
+WHERE analysis_id = $1
+  AND deleted_at IS NULL
+ORDER BY position, created_at
+`
+
+func (q *Queries) GetAnalysisItems(ctx context.Context, analysisID uuid.UUID) ([]AnalysisItem, error) {
+	rows, err := q.db.QueryContext(ctx, getAnalysisItems, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AnalysisItem
+	for rows.Next() {
+		var i AnalysisItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.AnalysisID,
+			&i.GeneratedIndex,
+			&i.Generated,
+			&i.Edited,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserAnalysisItem = `-- name: GetUserAnalysisItem :one
+SELECT i.id, i.analysis_id, i.generated_index, i.generated, i.edited, i.position, i.created_at, i.updated_at, i.deleted_at, i.external_id, a.type AS analysis_type, s.collection_id
+FROM analysis_items i
+JOIN collection_analyses a ON a.id = i.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE i.id = $1
+  AND i.deleted_at IS NULL
+  AND c.creator_id = $2
+`
+
+type GetUserAnalysisItemParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetUserAnalysisItemRow struct {
+	ID             uuid.UUID
+	AnalysisID     uuid.UUID
+	GeneratedIndex sql.NullInt32
+	Generated      json.RawMessage
+	Edited         json.RawMessage
+	Position       int32
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      sql.NullTime
+	ExternalID     sql.NullString
+	AnalysisType   string
+	CollectionID   uuid.UUID
+}
+
+// A live item of an analysis of one of the user's collections
+func (q *Queries) GetUserAnalysisItem(ctx context.Context, arg GetUserAnalysisItemParams) (GetUserAnalysisItemRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserAnalysisItem, arg.ID, arg.UserID)
+	var i GetUserAnalysisItemRow
+	err := row.Scan(
+		&i.ID,
+		&i.AnalysisID,
+		&i.GeneratedIndex,
+		&i.Generated,
+		&i.Edited,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExternalID,
+		&i.AnalysisType,
+		&i.CollectionID,
+	)
+	return i, err
+}
+
+const nextAnalysisItemPosition = `-- name: NextAnalysisItemPosition :one
+SELECT (COALESCE(MAX(position), -1) + 1)::integer
+FROM analysis_items
+WHERE analysis_id = $1
+`
+
+func (q *Queries) NextAnalysisItemPosition(ctx context.Context, analysisID uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, nextAnalysisItemPosition, analysisID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const refreshAnalysisResult = `-- name: RefreshAnalysisResult :exec
+UPDATE collection_analyses a
+SET result = COALESCE((
+    SELECT jsonb_agg(
+        CASE WHEN i.edited <> 'null' THEN i.edited ELSE i.generated END
+        ORDER BY i.position, i.created_at
+    )
+    FROM analysis_items i
+    WHERE i.analysis_id = a.id
+      AND i.deleted_at IS NULL
+), '[]')
+WHERE a.id = $1
+`
+
+// Rebuilds an analysis' result from its live items as they stand, edits included
+func (q *Queries) RefreshAnalysisResult(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, refreshAnalysisResult, id)
+	return err
+}
+
+const setAnalysisItemEdit = `-- name: SetAnalysisItemEdit :one
+UPDATE analysis_items
+SET edited = $1,
+    updated_at = now()
+WHERE id = $2
+RETURNING id, analysis_id, generated_index, generated, edited, position, created_at, updated_at, deleted_at, external_id
+`
+
+type SetAnalysisItemEditParams struct {
+	Edited json.RawMessage
+	ID     uuid.UUID
+}
+
+// A JSON null edit reverts the item to its generated content
+func (q *Queries) SetAnalysisItemEdit(ctx context.Context, arg SetAnalysisItemEditParams) (AnalysisItem, error) {
+	row := q.db.QueryRowContext(ctx, setAnalysisItemEdit, arg.Edited, arg.ID)
+	var i AnalysisItem
+	err := row.Scan(
+		&i.ID,
+		&i.AnalysisID,
+		&i.GeneratedIndex,
+		&i.Generated,
+		&i.Edited,
+		&i.Position,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const setAnalysisItemPosition = `-- name: SetAnalysisItemPosition :exec
+UPDATE analysis_items
+SET position = $1,
+    updated_at = now()
+WHERE id = $2
+`
+
+type SetAnalysisItemPositionParams struct {
+	Position int32
+	ID       uuid.UUID
+}
+
+func (q *Queries) SetAnalysisItemPosition(ctx context.Context, arg SetAnalysisItemPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setAnalysisItemPosition, arg.Position, arg.ID)
+	return err
+}
@@ -0,0 +1,244 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: analysis_jobs.sql
+
+package sqlgen
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimNextAnalysisJob = `-- name: ClaimNextAnalysisJob :one
+UPDATE analysis_jobs
+SET status = 'extracting',
+    attempts = attempts + 1,
+    started_at = now(),
+    updated_at = now()
+WHERE id = (
+    SELECT j.id FROM analysis_jobs j
+    WHERE j.status = 'queued'
+    ORDER BY j.created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING id, user_id, collection_id, type, status, analysis_id, error, attempts, created_at, updated_at, started_at, finished_at, provider, regenerate, parameters
+`
+
+// Picks the oldest queued job, skipping rows other workers have already locked
+func (q *Queries) ClaimNextAnalysisJob(ctx context.Context) (AnalysisJob, error) {
+	row := q.db.QueryRowContext(ctx, claimNextAnalysisJob)
+	var i AnalysisJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CollectionID,
+		&i.Type,
+		&i.Status,
+		&i.AnalysisID,
+		&i.Error,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Provider,
+		&i.Regenerate,
+		&i.Parameters,
+	)
+	return i, err
+}
+
+const completeAnalysisJob = `-- name: CompleteAnalysisJob :exec
+UPDATE analysis_jobs
+SET status = 'succeeded',
+    analysis_id = $1::uuid,
+    error = NULL,
+    updated_at = now(),
+    finished_at = now()
+WHERE id = $2
+`
+
+type CompleteAnalysisJobParams struct {
+	AnalysisID uuid.UUID
+	ID         uuid.UUID
+}
+
+func (q *Queries) CompleteAnalysisJob(ctx context.Context, arg CompleteAnalysisJobParams) error {
+	_, err := q.db.ExecContext(ctx, completeAnalysisJob, arg.AnalysisID, arg.ID)
+	return err
+}
+
+const createAnalysisJob = `-- name: CreateAnalysisJob :one
+INSERT INTO analysis_jobs (user_id, collection_id, type, provider, parameters, regenerate, status, started_at)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7,
+    CASE WHEN $7 = 'queued' THEN NULL ELSE now() END
+)
+RETURNING id, user_id, collection_id, type, status, analysis_id, error, attempts, created_at, updated_at, started_at, finished_at, provider, regenerate, parameters
+`
+
+type CreateAnalysisJobParams struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	Type         string
+	Provider     string
+	Parameters   json.RawMessage
+	Regenerate   bool
+	Status       AnalysisJobStatus
+}
+
+// Jobs created already running (synchronous analyses) are stamped as started
+func (q *Queries) CreateAnalysisJob(ctx context.Context, arg CreateAnalysisJobParams) (AnalysisJob, error) {
+	row := q.db.QueryRowContext(ctx, createAnalysisJob,
+		arg.UserID,
+		arg.CollectionID,
+		arg.Type,
+		arg.Provider,
+		arg.Parameters,
+		arg.Regenerate,
+		arg.Status,
+	)
+	var i AnalysisJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CollectionID,
+		&i.Type,
+		&i.Status,
+		&i.AnalysisID,
+		&i.Error,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Provider,
+		&i.Regenerate,
+		&i.Parameters,
+	)
+	return i, err
+}
+
+const failAbandonedAnalysisJobs = `-- name: FailAbandonedAnalysisJobs :execrows
+UPDATE analysis_jobs
+SET status = 'failed',
+    error = 'abandoned after ' || attempts || ' attempts',
+    updated_at = now(),
+    finished_at = now()
+WHERE status IN ('extracting', 'analyzing')
+  AND updated_at < $1
+  AND attempts >= $2::int
+`
+
+type FailAbandonedAnalysisJobsParams struct {
+	StaleBefore time.Time
+	MaxAttempts int32
+}
+
+// Stale jobs that have used up their attempts are failed rather than requeued, so a job that keeps
+// taking the server down with it isn't retried forever
+func (q *Queries) FailAbandonedAnalysisJobs(ctx context.Context, arg FailAbandonedAnalysisJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failAbandonedAnalysisJobs, arg.StaleBefore, arg.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const failAnalysisJob = `-- name: FailAnalysisJob :exec
+UPDATE analysis_jobs
+SET status = 'failed',
+    error = $1::text,
+    updated_at = now(),
+    finished_at = now()
+WHERE id = $2
+`
+
+type FailAnalysisJobParams struct {
+	Error string
+	ID    uuid.UUID
+}
+
+func (q *Queries) FailAnalysisJob(ctx context.Context, arg FailAnalysisJobParams) error {
+	_, err := q.db.ExecContext(ctx, failAnalysisJob, arg.Error, arg.ID)
+	return err
+}
+
+const getAnalysisJob = `-- name: GetAnalysisJob :one
+SELECT id, user_id, collection_id, type, status, analysis_id, error, attempts, created_at, updated_at, started_at, finished_at, provider, regenerate, parameters FROM analysis_jobs
+WHERE id = $1
+  AND user_id = $2
+`
+
+type GetAnalysisJobParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetAnalysisJob(ctx context.Context, arg GetAnalysisJobParams) (AnalysisJob, error) {
+	row := q.db.QueryRowContext(ctx, getAnalysisJob, arg.ID, arg.UserID)
+	var i AnalysisJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CollectionID,
+		&i.Type,
+		&i.Status,
+		&i.AnalysisID,
+		&i.Error,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Provider,
+		&i.Regenerate,
+		&i.Parameters,
+	)
+	return i, err
+}
+
+const requeueStaleAnalysisJobs = `-- name: RequeueStaleAnalysisJobs :execrows
+UPDATE analysis_jobs
+SET status = 'queued',
+    updated_at = now()
+WHERE status IN ('extracting', 'analyzing')
+  AND updated_at < $1
+  AND attempts < $2::int
+`
+
+type RequeueStaleAnalysisJobsParams struct {
+	StaleBefore time.Time
+	MaxAttempts int32
+}
+
+// Jobs left running by a crashed or restarted server go back on the queue
+func (q *Queries) RequeueStaleAnalysisJobs(ctx context.Context, arg RequeueStaleAnalysisJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requeueStaleAnalysisJobs, arg.StaleBefore, arg.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setAnalysisJobStatus = `-- name: SetAnalysisJobStatus :exec
+UPDATE analysis_jobs
+SET status = $1,
+    updated_at = now()
+WHERE id = $2
+`
+
+type SetAnalysisJobStatusParams struct {
+	Status AnalysisJobStatus
+	ID     uuid.UUID
+}
+
+func (q *Queries) SetAnalysisJobStatus(ctx context.Context, arg SetAnalysisJobStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setAnalysisJobStatus, arg.Status, arg.ID)
+	return err
+}
@@ -0,0 +1,259 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: chats.sql
+
+package sqlgen
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createChat = `-- name: CreateChat :one
+INSERT INTO chats (user_id, collection_id, course)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, collection_id, course, title, created_at, updated_at
+`
+
+type CreateChatParams struct {
+	UserID       uuid.UUID
+	CollectionID uuid.NullUUID
+	Course       sql.NullString
+}
+
+func (q *Queries) CreateChat(ctx context.Context, arg CreateChatParams) (Chat, error) {
+	row := q.db.QueryRowContext(ctx, createChat, arg.UserID, arg.CollectionID, arg.Course)
+	var i Chat
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CollectionID,
+		&i.Course,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createChatMessage = `-- name: CreateChatMessage :one
+INSERT INTO chat_messages (
+    chat_id, role, content, sources,
+    model, input_tokens, output_tokens, latency_ms, requests, created_at
+)
+VALUES (
+    $1, $2, $3, $4,
+    $5, $6, $7, $8, $9, $10
+)
+RETURNING id, chat_id, role, content, sources, model, input_tokens, output_tokens, latency_ms, requests, created_at
+`
+
+type CreateChatMessageParams struct {
+	ChatID       uuid.UUID
+	Role         string
+	Content      string
+	Sources      json.RawMessage
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	LatencyMs    int64
+	Requests     int32
+	CreatedAt    time.Time
+}
+
+// created_at is given so a question and its answer, stored together, keep their order
+func (q *Queries) CreateChatMessage(ctx context.Context, arg CreateChatMessageParams) (ChatMessage, error) {
+	row := q.db.QueryRowContext(ctx, createChatMessage,
+		arg.ChatID,
+		arg.Role,
+		arg.Content,
+		arg.Sources,
+		arg.Model,
+		arg.InputTokens,
+		arg.OutputTokens,
+		arg.LatencyMs,
+		arg.Requests,
+		arg.CreatedAt,
+	)
+	var i ChatMessage
+	err := row.Scan(
+		&i.ID,
+		&i.ChatID,
+		&i.Role,
+		&i.Content,
+		&i.Sources,
+		&i.Model,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteChat = `-- name: DeleteChat :execrows
+DELETE FROM chats
+WHERE id = $1
+  AND user_id = $2
+`
+
+type DeleteChatParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteChat(ctx context.Context, arg DeleteChatParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteChat, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getChat = `-- name: GetChat :one
+SELECT id, user_id, collection_id, course, title, created_at, updated_at FROM chats
+WHERE id = $1
+  AND user_id = $2
+`
+
+type GetChatParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetChat(ctx context.Context, arg GetChatParams) (Chat, error) {
+	row := q.db.QueryRowContext(ctx, getChat, arg.ID, arg.UserID)
+	var i Chat
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CollectionID,
+		&i.Course,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getChatMessages = `-- name: GetChatMessages :many
+SELECT id, chat_id, role, content, sources, model, input_tokens, output_tokens, latency_ms, requests, created_at FROM chat_messages
+WHERE chat_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetChatMessages(ctx context.Context, chatID uuid.UUID) ([]ChatMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getChatMessages, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ChatMessage
+	for rows.Next() {
+		var i ChatMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChatID,
+			&i.Role,
+			&i.Content,
+			&i.Sources,
+			&i.Model,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.LatencyMs,
+			&i.Requests,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChats = `-- name: GetChats :many
+SELECT id, user_id, collection_id, course, title, created_at, updated_at FROM chats
+WHERE user_id = $1
+  AND ($2::uuid IS NULL OR collection_id = $2)
+  AND ($3::text IS NULL OR course = $3)
+ORDER BY updated_at DESC
+`
+
+type GetChatsParams struct {
+	UserID       uuid.UUID
+	CollectionID uuid.NullUUID
+	Course       sql.NullString
+}
+
+// The user's chats over a collection or a course, most recently active first
+func (q *Queries) GetChats(ctx context.Context, arg GetChatsParams) ([]Chat, error) {
+	rows, err := q.db.QueryContext(ctx, getChats, arg.UserID, arg.CollectionID, arg.Course)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chat
+	for rows.Next() {
+		var i Chat
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CollectionID,
+			&i.Course,
+			&i.Title,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchChat = `-- name: TouchChat :one
+UPDATE chats
+SET updated_at = NOW(),
+    title = CASE WHEN title = '' THEN $1 ELSE title END
+WHERE id = $2
+RETURNING id, user_id, collection_id, course, title, created_at, updated_at
+`
+
+type TouchChatParams struct {
+	Title string
+	ID    uuid.UUID
+}
+
+// Marks a chat active, naming it after its first question
+func (q *Queries) TouchChat(ctx context.Context, arg TouchChatParams) (Chat, error) {
+	row := q.db.QueryRowContext(ctx, touchChat, arg.Title, arg.ID)
+	var i Chat
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CollectionID,
+		&i.Course,
+		&i.Title,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
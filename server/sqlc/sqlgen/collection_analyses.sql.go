@@ -8,24 +8,48 @@ package sqlgen
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const createCollectionAnalysis = `-- name: CreateCollectionAnalysis :one
-INSERT INTO collection_analyses (snapshot_id, type, result)
-VALUES ($1, $2, $3)
-RETURNING id, snapshot_id, type, result, created_at
+INSERT INTO collection_analyses (
+    snapshot_id, type, parameters, template_id, result,
+    model, prompt_hash, input_tokens, output_tokens, latency_ms, requests
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, snapshot_id, type, result, created_at, parameters, template_id, model, prompt_hash, input_tokens, output_tokens, latency_ms, requests, imported
 `
 
 type CreateCollectionAnalysisParams struct {
-	SnapshotID uuid.UUID
-	Type       AnalysisType
-	Result     json.RawMessage
+	SnapshotID   uuid.UUID
+	Type         string
+	Parameters   json.RawMessage
+	TemplateID   uuid.NullUUID
+	Result       json.RawMessage
+	Model        string
+	PromptHash   string
+	InputTokens  int64
+	OutputTokens int64
+	LatencyMs    int64
+	Requests     int32
 }
 
 func (q *Queries) CreateCollectionAnalysis(ctx context.Context, arg CreateCollectionAnalysisParams) (CollectionAnalysis, error) {
-	row := q.db.QueryRowContext(ctx, createCollectionAnalysis, arg.SnapshotID, arg.Type, arg.Result)
+	row := q.db.QueryRowContext(ctx, createCollectionAnalysis,
+		arg.SnapshotID,
+		arg.Type,
+		arg.Parameters,
+		arg.TemplateID,
+		arg.Result,
+		arg.Model,
+		arg.PromptHash,
+		arg.InputTokens,
+		arg.OutputTokens,
+		arg.LatencyMs,
+		arg.Requests,
+	)
 	var i CollectionAnalysis
 	err := row.Scan(
 		&i.ID,
@@ -33,24 +57,47 @@ func (q *Queries) CreateCollectionAnalysis(ctx context.Context, arg CreateCollec
 		&i.Type,
 		&i.Result,
 		&i.CreatedAt,
+		&i.Parameters,
+		&i.TemplateID,
+		&i.Model,
+		&i.PromptHash,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.Imported,
 	)
 	return i, err
 }
 
 const createCollectionSnapshot = `-- name: CreateCollectionSnapshot :one
-INSERT INTO collection_snapshots (collection_id, combined_content)
-VALUES ($1, $2)
+INSERT INTO collection_snapshots (collection_id, combined_content, content_hash, sources)
+VALUES ($1, $2, $3, $4)
 RETURNING id, collection_id, combined_content, created_at
 `
 
 type CreateCollectionSnapshotParams struct {
 	CollectionID    uuid.UUID
 	CombinedContent string
+	ContentHash     string
+	Sources         json.RawMessage
 }
 
-func (q *Queries) CreateCollectionSnapshot(ctx context.Context, arg CreateCollectionSnapshotParams) (CollectionSnapshot, error) {
-	row := q.db.QueryRowContext(ctx, createCollectionSnapshot, arg.CollectionID, arg.CombinedContent)
-	var i CollectionSnapshot
+type CreateCollectionSnapshotRow struct {
+	ID              uuid.UUID
+	CollectionID    uuid.UUID
+	CombinedContent string
+	CreatedAt       time.Time
+}
+
+func (q *Queries) CreateCollectionSnapshot(ctx context.Context, arg CreateCollectionSnapshotParams) (CreateCollectionSnapshotRow, error) {
+	row := q.db.QueryRowContext(ctx, createCollectionSnapshot,
+		arg.CollectionID,
+		arg.CombinedContent,
+		arg.ContentHash,
+		arg.Sources,
+	)
+	var i CreateCollectionSnapshotRow
 	err := row.Scan(
 		&i.ID,
 		&i.CollectionID,
@@ -61,12 +108,64 @@ func (q *Queries) CreateCollectionSnapshot(ctx context.Context, arg CreateCollec
 }
 
 const getAnalysis = `-- name: GetAnalysis :one
-SELECT id, snapshot_id, type, result, created_at FROM collection_analyses
-WHERE id = $1
+SELECT a.id, a.snapshot_id, a.type, a.result, a.created_at, a.parameters, a.template_id, a.model, a.prompt_hash, a.input_tokens, a.output_tokens, a.latency_ms, a.requests, a.imported
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE a.id = $1
+  AND s.collection_id = $2
+  AND c.creator_id = $3
+`
+
+type GetAnalysisParams struct {
+	ID           uuid.UUID
+	CollectionID uuid.UUID
+	UserID       uuid.UUID
+}
+
+func (q *Queries) GetAnalysis(ctx context.Context, arg GetAnalysisParams) (CollectionAnalysis, error) {
+	row := q.db.QueryRowContext(ctx, getAnalysis, arg.ID, arg.CollectionID, arg.UserID)
+	var i CollectionAnalysis
+	err := row.Scan(
+		&i.ID,
+		&i.SnapshotID,
+		&i.Type,
+		&i.Result,
+		&i.CreatedAt,
+		&i.Parameters,
+		&i.TemplateID,
+		&i.Model,
+		&i.PromptHash,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.Imported,
+	)
+	return i, err
+}
+
+const getAnalysisByContentHash = `-- name: GetAnalysisByContentHash :one
+SELECT a.id, a.snapshot_id, a.type, a.result, a.created_at, a.parameters, a.template_id, a.model, a.prompt_hash, a.input_tokens, a.output_tokens, a.latency_ms, a.requests, a.imported
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+WHERE s.collection_id = $1
+  AND s.content_hash = $2
+  AND a.type = $3
+  AND NOT a.imported
+ORDER BY a.created_at DESC
+LIMIT 1
 `
 
-func (q *Queries) GetAnalysis(ctx context.Context, id uuid.UUID) (CollectionAnalysis, error) {
-	row := q.db.QueryRowContext(ctx, getAnalysis, id)
+type GetAnalysisByContentHashParams struct {
+	CollectionID uuid.UUID
+	ContentHash  string
+	Type         string
+}
+
+// The most recent analysis of a snapshot with identical content and parameters
+func (q *Queries) GetAnalysisByContentHash(ctx context.Context, arg GetAnalysisByContentHashParams) (CollectionAnalysis, error) {
+	row := q.db.QueryRowContext(ctx, getAnalysisByContentHash, arg.CollectionID, arg.ContentHash, arg.Type)
 	var i CollectionAnalysis
 	err := row.Scan(
 		&i.ID,
@@ -74,12 +173,21 @@ func (q *Queries) GetAnalysis(ctx context.Context, id uuid.UUID) (CollectionAnal
 		&i.Type,
 		&i.Result,
 		&i.CreatedAt,
+		&i.Parameters,
+		&i.TemplateID,
+		&i.Model,
+		&i.PromptHash,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.Imported,
 	)
 	return i, err
 }
 
 const getCollectionAnalysesByCollection = `-- name: GetCollectionAnalysesByCollection :many
-SELECT a.id, a.snapshot_id, a.type, a.result, a.created_at
+SELECT a.id, a.snapshot_id, a.type, a.result, a.created_at, a.parameters, a.template_id, a.model, a.prompt_hash, a.input_tokens, a.output_tokens, a.latency_ms, a.requests, a.imported
 FROM collection_analyses a
 JOIN collection_snapshots s ON s.id = a.snapshot_id
 WHERE s.collection_id = $1
@@ -101,6 +209,15 @@ func (q *Queries) GetCollectionAnalysesByCollection(ctx context.Context, collect
 			&i.Type,
 			&i.Result,
 			&i.CreatedAt,
+			&i.Parameters,
+			&i.TemplateID,
+			&i.Model,
+			&i.PromptHash,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.LatencyMs,
+			&i.Requests,
+			&i.Imported,
 		); err != nil {
 			return nil, err
 		}
@@ -122,15 +239,22 @@ WHERE collection_id = $1
 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetCollectionSnapshots(ctx context.Context, collectionID uuid.UUID) ([]CollectionSnapshot, error) {
+type GetCollectionSnapshotsRow struct {
+	ID              uuid.UUID
+	CollectionID    uuid.UUID
+	CombinedContent string
+	CreatedAt       time.Time
+}
+
+func (q *Queries) GetCollectionSnapshots(ctx context.Context, collectionID uuid.UUID) ([]GetCollectionSnapshotsRow, error) {
 	rows, err := q.db.QueryContext(ctx, getCollectionSnapshots, collectionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CollectionSnapshot
+	var items []GetCollectionSnapshotsRow
 	for rows.Next() {
-		var i CollectionSnapshot
+		var i GetCollectionSnapshotsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CollectionID,
@@ -11,44 +11,132 @@ import (
 	"github.com/google/uuid"
 )
 
-const createDocumentExtraction = `-- name: CreateDocumentExtraction :one
-INSERT INTO document_extractions (document_id, content)
-VALUES ($1, $2)
-RETURNING id
+const createDocumentExtraction = `-- name: CreateDocumentExtraction :execrows
+INSERT INTO document_extractions (
+    document_id, page, method, content,
+    model, prompt_hash, input_tokens, output_tokens, latency_ms
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (document_id, page) DO NOTHING
 `
 
 type CreateDocumentExtractionParams struct {
-	DocumentID uuid.UUID
-	Content    string
+	DocumentID   uuid.UUID
+	Page         int32
+	Method       ExtractionMethod
+	Content      string
+	Model        string
+	PromptHash   string
+	InputTokens  int64
+	OutputTokens int64
+	LatencyMs    int64
 }
 
-func (q *Queries) CreateDocumentExtraction(ctx context.Context, arg CreateDocumentExtractionParams) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, createDocumentExtraction, arg.DocumentID, arg.Content)
-	var id uuid.UUID
-	err := row.Scan(&id)
-	return id, err
+// Stores nothing when the page was already stored, by another job extracting the same document
+func (q *Queries) CreateDocumentExtraction(ctx context.Context, arg CreateDocumentExtractionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createDocumentExtraction,
+		arg.DocumentID,
+		arg.Page,
+		arg.Method,
+		arg.Content,
+		arg.Model,
+		arg.PromptHash,
+		arg.InputTokens,
+		arg.OutputTokens,
+		arg.LatencyMs,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getDocumentExtractionsByCollection = `-- name: GetDocumentExtractionsByCollection :many
-SELECT e.content
+SELECT e.document_id, d.title, e.page, e.content
 FROM document_extractions e
 JOIN documents d ON d.id = e.document_id
 WHERE d.collection_id = $1
+ORDER BY d.id, e.page
 `
 
-func (q *Queries) GetDocumentExtractionsByCollection(ctx context.Context, collectionID uuid.UUID) ([]string, error) {
+type GetDocumentExtractionsByCollectionRow struct {
+	DocumentID uuid.UUID
+	Title      string
+	Page       int32
+	Content    string
+}
+
+func (q *Queries) GetDocumentExtractionsByCollection(ctx context.Context, collectionID uuid.UUID) ([]GetDocumentExtractionsByCollectionRow, error) {
 	rows, err := q.db.QueryContext(ctx, getDocumentExtractionsByCollection, collectionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []GetDocumentExtractionsByCollectionRow
+	for rows.Next() {
+		var i GetDocumentExtractionsByCollectionRow
+		if err := rows.Scan(
+			&i.DocumentID,
+			&i.Title,
+			&i.Page,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDocumentExtractionsByCourse = `-- name: GetDocumentExtractionsByCourse :many
+
+SELECT e.document_id, d.title, e.page, e.content
+FROM document_extractions e
+JOIN documents d ON d.id = e.document_id
+JOIN collections c ON c.id = d.collection_id
+WHERE c.creator_id = $1
+  AND c.course = $2
+ORDER BY d.id, e.page
+`
+
+type GetDocumentExtractionsByCourseParams struct {
+	UserID uuid.UUID
+	Course string
+}
+
+type GetDocumentExtractionsByCourseRow struct {
+	DocumentID uuid.UUID
+	Title      string
+	Page       int32
+	Content    string
+}
+
+// Stable order keeps snapshot content hashes comparable
+// Every page of every collection in one of the user's courses, in the same columns and order as by collection
+func (q *Queries) GetDocumentExtractionsByCourse(ctx context.Context, arg GetDocumentExtractionsByCourseParams) ([]GetDocumentExtractionsByCourseRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDocumentExtractionsByCourse, arg.UserID, arg.Course)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDocumentExtractionsByCourseRow
 	for rows.Next() {
-		var content string
-		if err := rows.Scan(&content); err != nil {
+		var i GetDocumentExtractionsByCourseRow
+		if err := rows.Scan(
+			&i.DocumentID,
+			&i.Title,
+			&i.Page,
+			&i.Content,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, content)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -0,0 +1,153 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: exports.sql
+
+package sqlgen
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getLatestCollectionAnalyses = `-- name: GetLatestCollectionAnalyses :many
+SELECT DISTINCT ON (a.type) a.id, a.snapshot_id, a.type, a.result, a.created_at, a.parameters, a.template_id, a.model, a.prompt_hash, a.input_tokens, a.output_tokens, a.latency_ms, a.requests, a.imported
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+WHERE s.collection_id = $1
+ORDER BY a.type, a.created_at DESC
+`
+
+// The newest analysis of each type run on a collection
+func (q *Queries) GetLatestCollectionAnalyses(ctx context.Context, collectionID uuid.UUID) ([]CollectionAnalysis, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestCollectionAnalyses, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CollectionAnalysis
+	for rows.Next() {
+		var i CollectionAnalysis
+		if err := rows.Scan(
+			&i.ID,
+			&i.SnapshotID,
+			&i.Type,
+			&i.Result,
+			&i.CreatedAt,
+			&i.Parameters,
+			&i.TemplateID,
+			&i.Model,
+			&i.PromptHash,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.LatencyMs,
+			&i.Requests,
+			&i.Imported,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestCourseAnalyses = `-- name: GetLatestCourseAnalyses :many
+SELECT id, type, collection_id, collection_title, course FROM (
+    SELECT DISTINCT ON (c.id) a.id, a.type, c.id AS collection_id, c.title AS collection_title, c.course
+    FROM collection_analyses a
+    JOIN collection_snapshots s ON s.id = a.snapshot_id
+    JOIN collections c ON c.id = s.collection_id
+    WHERE c.creator_id = $1
+      AND c.course = $2
+      AND a.type = $3
+    ORDER BY c.id, a.created_at DESC
+) latest
+ORDER BY latest.collection_title, latest.collection_id
+`
+
+type GetLatestCourseAnalysesParams struct {
+	UserID uuid.UUID
+	Course string
+	Type   string
+}
+
+type GetLatestCourseAnalysesRow struct {
+	ID              uuid.UUID
+	Type            string
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+// The newest analysis of a type for each of the user's collections in a course, by collection title
+func (q *Queries) GetLatestCourseAnalyses(ctx context.Context, arg GetLatestCourseAnalysesParams) ([]GetLatestCourseAnalysesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestCourseAnalyses, arg.UserID, arg.Course, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLatestCourseAnalysesRow
+	for rows.Next() {
+		var i GetLatestCourseAnalysesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.CollectionID,
+			&i.CollectionTitle,
+			&i.Course,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserAnalysisCollection = `-- name: GetUserAnalysisCollection :one
+SELECT a.id, a.type, c.id AS collection_id, c.title AS collection_title, c.course
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE a.id = $1
+  AND c.creator_id = $2
+`
+
+type GetUserAnalysisCollectionParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetUserAnalysisCollectionRow struct {
+	ID              uuid.UUID
+	Type            string
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+// One of the user's analyses with the collection it was run on
+func (q *Queries) GetUserAnalysisCollection(ctx context.Context, arg GetUserAnalysisCollectionParams) (GetUserAnalysisCollectionRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserAnalysisCollection, arg.ID, arg.UserID)
+	var i GetUserAnalysisCollectionRow
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.CollectionID,
+		&i.CollectionTitle,
+		&i.Course,
+	)
+	return i, err
+}
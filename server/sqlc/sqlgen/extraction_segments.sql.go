@@ -0,0 +1,203 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: extraction_segments.sql
+
+package sqlgen
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	pgvector "github.com/pgvector/pgvector-go"
+)
+
+const createExtractionSegment = `-- name: CreateExtractionSegment :exec
+INSERT INTO extraction_segments (extraction_id, segment, content, model, embedding)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateExtractionSegmentParams struct {
+	ExtractionID uuid.UUID
+	Segment      int32
+	Content      string
+	Model        string
+	Embedding    pgvector.Vector
+}
+
+func (q *Queries) CreateExtractionSegment(ctx context.Context, arg CreateExtractionSegmentParams) error {
+	_, err := q.db.ExecContext(ctx, createExtractionSegment,
+		arg.ExtractionID,
+		arg.Segment,
+		arg.Content,
+		arg.Model,
+		arg.Embedding,
+	)
+	return err
+}
+
+const deleteExtractionSegments = `-- name: DeleteExtractionSegments :exec
+DELETE FROM extraction_segments
+WHERE extraction_id = $1
+`
+
+// Drops a page's segments, including other models', before it is embedded again
+func (q *Queries) DeleteExtractionSegments(ctx context.Context, extractionID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteExtractionSegments, extractionID)
+	return err
+}
+
+const getUnembeddedExtractions = `-- name: GetUnembeddedExtractions :many
+SELECT e.id, e.document_id, e.page, e.content, c.creator_id AS user_id
+FROM document_extractions e
+JOIN documents d ON d.id = e.document_id
+JOIN collections c ON c.id = d.collection_id
+WHERE e.content ~ '\S'
+  AND ($1::uuid IS NULL OR e.document_id = $1)
+  AND (
+    $2::uuid IS NULL
+    OR (e.document_id, e.page) > ($2::uuid, $3::int)
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM extraction_segments s
+    WHERE s.extraction_id = e.id
+      AND s.model = $4
+  )
+ORDER BY e.document_id, e.page
+LIMIT $5
+`
+
+type GetUnembeddedExtractionsParams struct {
+	DocumentID      uuid.NullUUID
+	AfterDocumentID uuid.NullUUID
+	AfterPage       sql.NullInt32
+	Model           string
+	MaxExtractions  int32
+}
+
+type GetUnembeddedExtractionsRow struct {
+	ID         uuid.UUID
+	DocumentID uuid.UUID
+	Page       int32
+	Content    string
+	UserID     uuid.UUID
+}
+
+// Pages with content but no segments from the given model, optionally of one document, with the user
+// who owns them, in a stable order so a backfill works through the backlog page by page. Pages up to the
+// given one are skipped, so a pass moves on past pages that failed instead of fetching them again
+func (q *Queries) GetUnembeddedExtractions(ctx context.Context, arg GetUnembeddedExtractionsParams) ([]GetUnembeddedExtractionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnembeddedExtractions,
+		arg.DocumentID,
+		arg.AfterDocumentID,
+		arg.AfterPage,
+		arg.Model,
+		arg.MaxExtractions,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnembeddedExtractionsRow
+	for rows.Next() {
+		var i GetUnembeddedExtractionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.DocumentID,
+			&i.Page,
+			&i.Content,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchExtractionSegments = `-- name: SearchExtractionSegments :many
+SELECT s.id, s.content, e.page, d.id AS document_id, d.title AS document_title,
+       c.id AS collection_id, c.title AS collection_title, c.course,
+       (1 - (s.embedding <=> $1::vector))::float8 AS score
+FROM extraction_segments s
+JOIN document_extractions e ON e.id = s.extraction_id
+JOIN documents d ON d.id = e.document_id
+JOIN collections c ON c.id = d.collection_id
+WHERE c.creator_id = $2
+  AND s.model = $3
+  AND ($4::uuid IS NULL OR c.id = $4)
+  AND ($5::text IS NULL OR c.course = $5)
+ORDER BY s.embedding <=> $1::vector
+LIMIT $6
+`
+
+type SearchExtractionSegmentsParams struct {
+	Embedding    pgvector.Vector
+	UserID       uuid.UUID
+	Model        string
+	CollectionID uuid.NullUUID
+	Course       sql.NullString
+	MaxResults   int32
+}
+
+type SearchExtractionSegmentsRow struct {
+	ID              uuid.UUID
+	Content         string
+	Page            int32
+	DocumentID      uuid.UUID
+	DocumentTitle   string
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+	Score           float64
+}
+
+// The user's segments closest to a query embedding, optionally within one collection or course.
+// score is the cosine similarity, from -1 to 1.
+func (q *Queries) SearchExtractionSegments(ctx context.Context, arg SearchExtractionSegmentsParams) ([]SearchExtractionSegmentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchExtractionSegments,
+		arg.Embedding,
+		arg.UserID,
+		arg.Model,
+		arg.CollectionID,
+		arg.Course,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchExtractionSegmentsRow
+	for rows.Next() {
+		var i SearchExtractionSegmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Content,
+			&i.Page,
+			&i.DocumentID,
+			&i.DocumentTitle,
+			&i.CollectionID,
+			&i.CollectionTitle,
+			&i.Course,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
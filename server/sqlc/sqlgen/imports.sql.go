@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: imports.sql
+
+package sqlgen
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const createImportedAnalysis = `-- name: CreateImportedAnalysis :one
+INSERT INTO collection_analyses (snapshot_id, type, result, imported)
+VALUES ($1, $2, '[]', true)
+RETURNING id, snapshot_id, type, result, created_at, parameters, template_id, model, prompt_hash, input_tokens, output_tokens, latency_ms, requests, imported
+`
+
+type CreateImportedAnalysisParams struct {
+	SnapshotID uuid.UUID
+	Type       string
+}
+
+func (q *Queries) CreateImportedAnalysis(ctx context.Context, arg CreateImportedAnalysisParams) (CollectionAnalysis, error) {
+	row := q.db.QueryRowContext(ctx, createImportedAnalysis, arg.SnapshotID, arg.Type)
+	var i CollectionAnalysis
+	err := row.Scan(
+		&i.ID,
+		&i.SnapshotID,
+		&i.Type,
+		&i.Result,
+		&i.CreatedAt,
+		&i.Parameters,
+		&i.TemplateID,
+		&i.Model,
+		&i.PromptHash,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.Imported,
+	)
+	return i, err
+}
+
+const getImportedAnalysis = `-- name: GetImportedAnalysis :one
+SELECT a.id, a.snapshot_id, a.type, a.result, a.created_at, a.parameters, a.template_id, a.model, a.prompt_hash, a.input_tokens, a.output_tokens, a.latency_ms, a.requests, a.imported
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+WHERE s.collection_id = $1
+  AND a.type = $2
+  AND a.imported
+ORDER BY a.created_at
+LIMIT 1
+`
+
+type GetImportedAnalysisParams struct {
+	CollectionID uuid.UUID
+	Type         string
+}
+
+func (q *Queries) GetImportedAnalysis(ctx context.Context, arg GetImportedAnalysisParams) (CollectionAnalysis, error) {
+	row := q.db.QueryRowContext(ctx, getImportedAnalysis, arg.CollectionID, arg.Type)
+	var i CollectionAnalysis
+	err := row.Scan(
+		&i.ID,
+		&i.SnapshotID,
+		&i.Type,
+		&i.Result,
+		&i.CreatedAt,
+		&i.Parameters,
+		&i.TemplateID,
+		&i.Model,
+		&i.PromptHash,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.Imported,
+	)
+	return i, err
+}
+
+const lockUserCollection = `-- name: LockUserCollection :one
+SELECT id FROM collections
+WHERE id = $1
+  AND creator_id = $2
+FOR UPDATE
+`
+
+type LockUserCollectionParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// Serializes imports into a collection
+func (q *Queries) LockUserCollection(ctx context.Context, arg LockUserCollectionParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, lockUserCollection, arg.ID, arg.UserID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertImportedAnalysisItem = `-- name: UpsertImportedAnalysisItem :one
+INSERT INTO analysis_items (analysis_id, external_id, generated, position)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (analysis_id, external_id) DO UPDATE
+SET generated = EXCLUDED.generated,
+    updated_at = NOW()
+WHERE analysis_items.deleted_at IS NULL
+  AND analysis_items.generated IS DISTINCT FROM EXCLUDED.generated
+RETURNING (xmax = 0)::boolean AS created
+`
+
+type UpsertImportedAnalysisItemParams struct {
+	AnalysisID uuid.UUID
+	ExternalID sql.NullString
+	Generated  json.RawMessage
+	Position   int32
+}
+
+// Returns nothing when the item is already imported as it is, or the user deleted it
+func (q *Queries) UpsertImportedAnalysisItem(ctx context.Context, arg UpsertImportedAnalysisItemParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, upsertImportedAnalysisItem,
+		arg.AnalysisID,
+		arg.ExternalID,
+		arg.Generated,
+		arg.Position,
+	)
+	var created bool
+	err := row.Scan(&created)
+	return created, err
+}
@@ -5,56 +5,199 @@
 package sqlgen
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	pgvector "github.com/pgvector/pgvector-go"
 )
 
-type AnalysisType string
+type AnalysisJobStatus string
 
 const (
-	AnalysisTypeSummary     AnalysisType = "summary"
-	AnalysisTypeFlashcards  AnalysisType = "flashcards"
-	AnalysisTypeQuiz        AnalysisType = "quiz"
-	AnalysisTypeDeepSummary AnalysisType = "deep_summary"
+	AnalysisJobStatusQueued     AnalysisJobStatus = "queued"
+	AnalysisJobStatusExtracting AnalysisJobStatus = "extracting"
+	AnalysisJobStatusAnalyzing  AnalysisJobStatus = "analyzing"
+	AnalysisJobStatusSucceeded  AnalysisJobStatus = "succeeded"
+	AnalysisJobStatusFailed     AnalysisJobStatus = "failed"
 )
 
-func (e *AnalysisType) Scan(src interface{}) error {
+func (e *AnalysisJobStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = AnalysisType(s)
+		*e = AnalysisJobStatus(s)
 	case string:
-		*e = AnalysisType(s)
+		*e = AnalysisJobStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for AnalysisType: %T", src)
+		return fmt.Errorf("unsupported scan type for AnalysisJobStatus: %T", src)
 	}
 	return nil
 }
 
-type NullAnalysisType struct {
-	AnalysisType AnalysisType
-	Valid        bool // Valid is true if AnalysisType is not NULL
+type NullAnalysisJobStatus struct {
+	AnalysisJobStatus AnalysisJobStatus
+	Valid             bool // Valid is true if AnalysisJobStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullAnalysisType) Scan(value interface{}) error {
+func (ns *NullAnalysisJobStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.AnalysisType, ns.Valid = "", false
+		ns.AnalysisJobStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.AnalysisType.Scan(value)
+	return ns.AnalysisJobStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullAnalysisType) Value() (driver.Value, error) {
+func (ns NullAnalysisJobStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.AnalysisType), nil
+	return string(ns.AnalysisJobStatus), nil
+}
+
+type ExtractionMethod string
+
+const (
+	ExtractionMethodTextLayer ExtractionMethod = "text_layer"
+	ExtractionMethodVision    ExtractionMethod = "vision"
+)
+
+func (e *ExtractionMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ExtractionMethod(s)
+	case string:
+		*e = ExtractionMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ExtractionMethod: %T", src)
+	}
+	return nil
+}
+
+type NullExtractionMethod struct {
+	ExtractionMethod ExtractionMethod
+	Valid            bool // Valid is true if ExtractionMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullExtractionMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.ExtractionMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ExtractionMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullExtractionMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ExtractionMethod), nil
+}
+
+type UsageKind string
+
+const (
+	UsageKindExtraction UsageKind = "extraction"
+	UsageKindAnalysis   UsageKind = "analysis"
+	UsageKindEmbedding  UsageKind = "embedding"
+)
+
+func (e *UsageKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UsageKind(s)
+	case string:
+		*e = UsageKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UsageKind: %T", src)
+	}
+	return nil
+}
+
+type NullUsageKind struct {
+	UsageKind UsageKind
+	Valid     bool // Valid is true if UsageKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUsageKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.UsageKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UsageKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUsageKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UsageKind), nil
+}
+
+type AnalysisItem struct {
+	ID             uuid.UUID
+	AnalysisID     uuid.UUID
+	GeneratedIndex sql.NullInt32
+	Generated      json.RawMessage
+	Edited         json.RawMessage
+	Position       int32
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      sql.NullTime
+	ExternalID     sql.NullString
+}
+
+type AnalysisJob struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	Type         string
+	Status       AnalysisJobStatus
+	AnalysisID   uuid.NullUUID
+	Error        sql.NullString
+	Attempts     int32
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	StartedAt    sql.NullTime
+	FinishedAt   sql.NullTime
+	Provider     string
+	Regenerate   bool
+	Parameters   json.RawMessage
+}
+
+type Chat struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CollectionID uuid.NullUUID
+	Course       sql.NullString
+	Title        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type ChatMessage struct {
+	ID           uuid.UUID
+	ChatID       uuid.UUID
+	Role         string
+	Content      string
+	Sources      json.RawMessage
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	LatencyMs    int64
+	Requests     int32
+	CreatedAt    time.Time
 }
 
 type Collection struct {
@@ -66,11 +209,20 @@ type Collection struct {
 }
 
 type CollectionAnalysis struct {
-	ID         uuid.UUID
-	SnapshotID uuid.UUID
-	Type       AnalysisType
-	Result     json.RawMessage
-	CreatedAt  time.Time
+	ID           uuid.UUID
+	SnapshotID   uuid.UUID
+	Type         string
+	Result       json.RawMessage
+	CreatedAt    time.Time
+	Parameters   json.RawMessage
+	TemplateID   uuid.NullUUID
+	Model        string
+	PromptHash   string
+	InputTokens  int64
+	OutputTokens int64
+	LatencyMs    int64
+	Requests     int32
+	Imported     bool
 }
 
 type CollectionSnapshot struct {
@@ -78,6 +230,8 @@ type CollectionSnapshot struct {
 	CollectionID    uuid.UUID
 	CombinedContent string
 	CreatedAt       time.Time
+	ContentHash     string
+	Sources         json.RawMessage
 }
 
 type Course struct {
@@ -95,9 +249,99 @@ type Document struct {
 }
 
 type DocumentExtraction struct {
-	ID         uuid.UUID
-	DocumentID uuid.UUID
-	Content    string
+	ID           uuid.UUID
+	DocumentID   uuid.UUID
+	Content      string
+	Page         int32
+	Method       ExtractionMethod
+	Model        string
+	PromptHash   string
+	InputTokens  int64
+	OutputTokens int64
+	LatencyMs    int64
+}
+
+type ExtractionSegment struct {
+	ID           uuid.UUID
+	ExtractionID uuid.UUID
+	Segment      int32
+	Content      string
+	Model        string
+	Embedding    pgvector.Vector
+	CreatedAt    time.Time
+}
+
+type PromptTemplate struct {
+	ID        uuid.UUID
+	CreatorID uuid.UUID
+	Course    string
+	Kind      string
+	Version   int32
+	Body      string
+	CreatedAt time.Time
+	RetiredAt sql.NullTime
+}
+
+type QuizAttempt struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	AnalysisID  uuid.UUID
+	StartedAt   time.Time
+	SubmittedAt sql.NullTime
+	Score       sql.NullInt32
+	Total       sql.NullInt32
+	Questions   json.RawMessage
+}
+
+type QuizAttemptAnswer struct {
+	AttemptID     uuid.UUID
+	QuestionIndex int32
+	Question      string
+	SelectedIndex sql.NullInt32
+	CorrectIndex  int32
+	Correct       bool
+	TimeMs        int64
+}
+
+type ReviewCard struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	AnalysisID     uuid.UUID
+	Repetitions    int32
+	IntervalDays   int32
+	EaseFactor     float64
+	DueAt          time.Time
+	LastReviewedAt sql.NullTime
+	CreatedAt      time.Time
+	ItemID         uuid.UUID
+}
+
+type ReviewLog struct {
+	ID           uuid.UUID
+	CardID       uuid.UUID
+	Grade        int16
+	IntervalDays int32
+	EaseFactor   float64
+	ReviewedAt   time.Time
+}
+
+type UsageEvent struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Kind         UsageKind
+	Requests     int32
+	InputTokens  int64
+	OutputTokens int64
+	CreatedAt    time.Time
+}
+
+type UsageLimitOverride struct {
+	UserID          uuid.UUID
+	DailyTokens     sql.NullInt64
+	MonthlyTokens   sql.NullInt64
+	DailyRequests   sql.NullInt32
+	MonthlyRequests sql.NullInt32
+	UpdatedAt       time.Time
 }
 
 type UserAccount struct {
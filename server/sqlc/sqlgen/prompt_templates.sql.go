@@ -0,0 +1,157 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: prompt_templates.sql
+
+package sqlgen
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createPromptTemplate = `-- name: CreatePromptTemplate :one
+INSERT INTO prompt_templates (creator_id, course, kind, version, body)
+VALUES (
+    $1, $2, $3,
+    (
+        SELECT COALESCE(MAX(t.version), 0) + 1
+        FROM prompt_templates t
+        WHERE t.creator_id = $1
+          AND t.course = $2
+          AND t.kind = $3
+    ),
+    $4
+)
+RETURNING id, creator_id, course, kind, version, body, created_at, retired_at
+`
+
+type CreatePromptTemplateParams struct {
+	CreatorID uuid.UUID
+	Course    string
+	Kind      string
+	Body      string
+}
+
+// Versions count up per course and kind, starting at 1
+func (q *Queries) CreatePromptTemplate(ctx context.Context, arg CreatePromptTemplateParams) (PromptTemplate, error) {
+	row := q.db.QueryRowContext(ctx, createPromptTemplate,
+		arg.CreatorID,
+		arg.Course,
+		arg.Kind,
+		arg.Body,
+	)
+	var i PromptTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.CreatorID,
+		&i.Course,
+		&i.Kind,
+		&i.Version,
+		&i.Body,
+		&i.CreatedAt,
+		&i.RetiredAt,
+	)
+	return i, err
+}
+
+const getActivePromptTemplate = `-- name: GetActivePromptTemplate :one
+SELECT id, creator_id, course, kind, version, body, created_at, retired_at FROM prompt_templates
+WHERE creator_id = $1
+  AND course = $2
+  AND kind = $3
+  AND retired_at IS NULL
+ORDER BY version DESC
+LIMIT 1
+`
+
+type GetActivePromptTemplateParams struct {
+	CreatorID uuid.UUID
+	Course    string
+	Kind      string
+}
+
+func (q *Queries) GetActivePromptTemplate(ctx context.Context, arg GetActivePromptTemplateParams) (PromptTemplate, error) {
+	row := q.db.QueryRowContext(ctx, getActivePromptTemplate, arg.CreatorID, arg.Course, arg.Kind)
+	var i PromptTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.CreatorID,
+		&i.Course,
+		&i.Kind,
+		&i.Version,
+		&i.Body,
+		&i.CreatedAt,
+		&i.RetiredAt,
+	)
+	return i, err
+}
+
+const getPromptTemplates = `-- name: GetPromptTemplates :many
+SELECT id, creator_id, course, kind, version, body, created_at, retired_at FROM prompt_templates
+WHERE creator_id = $1
+  AND course = $2
+ORDER BY kind, version DESC
+`
+
+type GetPromptTemplatesParams struct {
+	CreatorID uuid.UUID
+	Course    string
+}
+
+func (q *Queries) GetPromptTemplates(ctx context.Context, arg GetPromptTemplatesParams) ([]PromptTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, getPromptTemplates, arg.CreatorID, arg.Course)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PromptTemplate
+	for rows.Next() {
+		var i PromptTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatorID,
+			&i.Course,
+			&i.Kind,
+			&i.Version,
+			&i.Body,
+			&i.CreatedAt,
+			&i.RetiredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retirePromptTemplates = `-- name: RetirePromptTemplates :execrows
+UPDATE prompt_templates
+SET retired_at = now()
+WHERE creator_id = $1
+  AND course = $2
+  AND kind = $3
+  AND retired_at IS NULL
+`
+
+type RetirePromptTemplatesParams struct {
+	CreatorID uuid.UUID
+	Course    string
+	Kind      string
+}
+
+// Analyses of the kind go back to the default instructions until a new version is saved
+func (q *Queries) RetirePromptTemplates(ctx context.Context, arg RetirePromptTemplatesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, retirePromptTemplates, arg.CreatorID, arg.Course, arg.Kind)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
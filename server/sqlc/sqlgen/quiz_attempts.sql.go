@@ -0,0 +1,320 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: quiz_attempts.sql
+
+package sqlgen
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const completeQuizAttempt = `-- name: CompleteQuizAttempt :execrows
+UPDATE quiz_attempts
+SET submitted_at = $1,
+    score = $2,
+    total = $3
+WHERE id = $4
+  AND submitted_at IS NULL
+`
+
+type CompleteQuizAttemptParams struct {
+	SubmittedAt sql.NullTime
+	Score       sql.NullInt32
+	Total       sql.NullInt32
+	ID          uuid.UUID
+}
+
+// Affects no rows when the attempt was already submitted
+func (q *Queries) CompleteQuizAttempt(ctx context.Context, arg CompleteQuizAttemptParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeQuizAttempt,
+		arg.SubmittedAt,
+		arg.Score,
+		arg.Total,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createQuizAttempt = `-- name: CreateQuizAttempt :one
+INSERT INTO quiz_attempts (user_id, analysis_id, questions)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, analysis_id, started_at, submitted_at, score, total, questions
+`
+
+type CreateQuizAttemptParams struct {
+	UserID     uuid.UUID
+	AnalysisID uuid.UUID
+	Questions  json.RawMessage
+}
+
+func (q *Queries) CreateQuizAttempt(ctx context.Context, arg CreateQuizAttemptParams) (QuizAttempt, error) {
+	row := q.db.QueryRowContext(ctx, createQuizAttempt, arg.UserID, arg.AnalysisID, arg.Questions)
+	var i QuizAttempt
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AnalysisID,
+		&i.StartedAt,
+		&i.SubmittedAt,
+		&i.Score,
+		&i.Total,
+		&i.Questions,
+	)
+	return i, err
+}
+
+const createQuizAttemptAnswer = `-- name: CreateQuizAttemptAnswer :exec
+INSERT INTO quiz_attempt_answers (
+    attempt_id, question_index, question, selected_index, correct_index, correct, time_ms
+)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+`
+
+type CreateQuizAttemptAnswerParams struct {
+	AttemptID     uuid.UUID
+	QuestionIndex int32
+	Question      string
+	SelectedIndex sql.NullInt32
+	CorrectIndex  int32
+	Correct       bool
+	TimeMs        int64
+}
+
+func (q *Queries) CreateQuizAttemptAnswer(ctx context.Context, arg CreateQuizAttemptAnswerParams) error {
+	_, err := q.db.ExecContext(ctx, createQuizAttemptAnswer,
+		arg.AttemptID,
+		arg.QuestionIndex,
+		arg.Question,
+		arg.SelectedIndex,
+		arg.CorrectIndex,
+		arg.Correct,
+		arg.TimeMs,
+	)
+	return err
+}
+
+const getQuizAttempt = `-- name: GetQuizAttempt :one
+SELECT q.id, q.user_id, q.analysis_id, q.started_at, q.submitted_at, q.score, q.total, q.questions, c.id AS collection_id, c.title AS collection_title, c.course
+FROM quiz_attempts q
+JOIN collection_analyses a ON a.id = q.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE q.id = $1
+  AND q.user_id = $2
+`
+
+type GetQuizAttemptParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetQuizAttemptRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AnalysisID      uuid.UUID
+	StartedAt       time.Time
+	SubmittedAt     sql.NullTime
+	Score           sql.NullInt32
+	Total           sql.NullInt32
+	Questions       json.RawMessage
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+func (q *Queries) GetQuizAttempt(ctx context.Context, arg GetQuizAttemptParams) (GetQuizAttemptRow, error) {
+	row := q.db.QueryRowContext(ctx, getQuizAttempt, arg.ID, arg.UserID)
+	var i GetQuizAttemptRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AnalysisID,
+		&i.StartedAt,
+		&i.SubmittedAt,
+		&i.Score,
+		&i.Total,
+		&i.Questions,
+		&i.CollectionID,
+		&i.CollectionTitle,
+		&i.Course,
+	)
+	return i, err
+}
+
+const getQuizAttemptAnswers = `-- name: GetQuizAttemptAnswers :many
+SELECT attempt_id, question_index, question, selected_index, correct_index, correct, time_ms FROM quiz_attempt_answers
+WHERE attempt_id = $1
+ORDER BY question_index
+`
+
+func (q *Queries) GetQuizAttemptAnswers(ctx context.Context, attemptID uuid.UUID) ([]QuizAttemptAnswer, error) {
+	rows, err := q.db.QueryContext(ctx, getQuizAttemptAnswers, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuizAttemptAnswer
+	for rows.Next() {
+		var i QuizAttemptAnswer
+		if err := rows.Scan(
+			&i.AttemptID,
+			&i.QuestionIndex,
+			&i.Question,
+			&i.SelectedIndex,
+			&i.CorrectIndex,
+			&i.Correct,
+			&i.TimeMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuizAttemptsByAnalysis = `-- name: GetQuizAttemptsByAnalysis :many
+SELECT q.id, q.user_id, q.analysis_id, q.started_at, q.submitted_at, q.score, q.total, q.questions, c.id AS collection_id, c.title AS collection_title, c.course
+FROM quiz_attempts q
+JOIN collection_analyses a ON a.id = q.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE q.user_id = $1
+  AND q.analysis_id = $2
+ORDER BY q.started_at DESC
+`
+
+type GetQuizAttemptsByAnalysisParams struct {
+	UserID     uuid.UUID
+	AnalysisID uuid.UUID
+}
+
+type GetQuizAttemptsByAnalysisRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AnalysisID      uuid.UUID
+	StartedAt       time.Time
+	SubmittedAt     sql.NullTime
+	Score           sql.NullInt32
+	Total           sql.NullInt32
+	Questions       json.RawMessage
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+func (q *Queries) GetQuizAttemptsByAnalysis(ctx context.Context, arg GetQuizAttemptsByAnalysisParams) ([]GetQuizAttemptsByAnalysisRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuizAttemptsByAnalysis, arg.UserID, arg.AnalysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuizAttemptsByAnalysisRow
+	for rows.Next() {
+		var i GetQuizAttemptsByAnalysisRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AnalysisID,
+			&i.StartedAt,
+			&i.SubmittedAt,
+			&i.Score,
+			&i.Total,
+			&i.Questions,
+			&i.CollectionID,
+			&i.CollectionTitle,
+			&i.Course,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuizAttemptsByCourse = `-- name: GetQuizAttemptsByCourse :many
+SELECT q.id, q.user_id, q.analysis_id, q.started_at, q.submitted_at, q.score, q.total, q.questions, c.id AS collection_id, c.title AS collection_title, c.course
+FROM quiz_attempts q
+JOIN collection_analyses a ON a.id = q.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE q.user_id = $1
+  AND c.course = $2
+ORDER BY q.started_at DESC
+`
+
+type GetQuizAttemptsByCourseParams struct {
+	UserID uuid.UUID
+	Course string
+}
+
+type GetQuizAttemptsByCourseRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AnalysisID      uuid.UUID
+	StartedAt       time.Time
+	SubmittedAt     sql.NullTime
+	Score           sql.NullInt32
+	Total           sql.NullInt32
+	Questions       json.RawMessage
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+func (q *Queries) GetQuizAttemptsByCourse(ctx context.Context, arg GetQuizAttemptsByCourseParams) ([]GetQuizAttemptsByCourseRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuizAttemptsByCourse, arg.UserID, arg.Course)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuizAttemptsByCourseRow
+	for rows.Next() {
+		var i GetQuizAttemptsByCourseRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AnalysisID,
+			&i.StartedAt,
+			&i.SubmittedAt,
+			&i.Score,
+			&i.Total,
+			&i.Questions,
+			&i.CollectionID,
+			&i.CollectionTitle,
+			&i.Course,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,385 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: reviews.sql
+
+package sqlgen
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countDueReviewCards = `-- name: CountDueReviewCards :one
+SELECT COUNT(*)
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.user_id = $1
+  AND r.due_at <= $2
+  AND i.deleted_at IS NULL
+  AND ($3::text IS NULL OR c.course = $3)
+  AND ($4::uuid IS NULL OR c.id = $4)
+`
+
+type CountDueReviewCardsParams struct {
+	UserID       uuid.UUID
+	DueBy        time.Time
+	Course       sql.NullString
+	CollectionID uuid.NullUUID
+}
+
+func (q *Queries) CountDueReviewCards(ctx context.Context, arg CountDueReviewCardsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDueReviewCards,
+		arg.UserID,
+		arg.DueBy,
+		arg.Course,
+		arg.CollectionID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createReviewLog = `-- name: CreateReviewLog :exec
+INSERT INTO review_logs (card_id, grade, interval_days, ease_factor, reviewed_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateReviewLogParams struct {
+	CardID       uuid.UUID
+	Grade        int16
+	IntervalDays int32
+	EaseFactor   float64
+	ReviewedAt   time.Time
+}
+
+func (q *Queries) CreateReviewLog(ctx context.Context, arg CreateReviewLogParams) error {
+	_, err := q.db.ExecContext(ctx, createReviewLog,
+		arg.CardID,
+		arg.Grade,
+		arg.IntervalDays,
+		arg.EaseFactor,
+		arg.ReviewedAt,
+	)
+	return err
+}
+
+const enrollReviewCard = `-- name: EnrollReviewCard :exec
+INSERT INTO review_cards (user_id, analysis_id, item_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, item_id) DO NOTHING
+`
+
+type EnrollReviewCardParams struct {
+	UserID     uuid.UUID
+	AnalysisID uuid.UUID
+	ItemID     uuid.UUID
+}
+
+// Cards already enrolled keep their schedule
+func (q *Queries) EnrollReviewCard(ctx context.Context, arg EnrollReviewCardParams) error {
+	_, err := q.db.ExecContext(ctx, enrollReviewCard, arg.UserID, arg.AnalysisID, arg.ItemID)
+	return err
+}
+
+const getDueReviewCards = `-- name: GetDueReviewCards :many
+SELECT r.id, r.user_id, r.analysis_id, r.repetitions, r.interval_days, r.ease_factor, r.due_at, r.last_reviewed_at, r.created_at, r.item_id, i.position, COALESCE(NULLIF(i.edited, 'null'), i.generated)::jsonb AS content,
+       c.id AS collection_id, c.title AS collection_title, c.course
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.user_id = $1
+  AND r.due_at <= $2
+  AND i.deleted_at IS NULL
+  AND ($3::text IS NULL OR c.course = $3)
+  AND ($4::uuid IS NULL OR c.id = $4)
+ORDER BY r.due_at, i.position
+LIMIT $5
+`
+
+type GetDueReviewCardsParams struct {
+	UserID       uuid.UUID
+	DueBy        time.Time
+	Course       sql.NullString
+	CollectionID uuid.NullUUID
+	MaxCards     int32
+}
+
+type GetDueReviewCardsRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AnalysisID      uuid.UUID
+	Repetitions     int32
+	IntervalDays    int32
+	EaseFactor      float64
+	DueAt           time.Time
+	LastReviewedAt  sql.NullTime
+	CreatedAt       time.Time
+	ItemID          uuid.UUID
+	Position        int32
+	Content         json.RawMessage
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+// Cards due by a time across the user's collections, most overdue first. Cards whose item was deleted are skipped.
+// Course and collection narrow the queue when set.
+func (q *Queries) GetDueReviewCards(ctx context.Context, arg GetDueReviewCardsParams) ([]GetDueReviewCardsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDueReviewCards,
+		arg.UserID,
+		arg.DueBy,
+		arg.Course,
+		arg.CollectionID,
+		arg.MaxCards,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDueReviewCardsRow
+	for rows.Next() {
+		var i GetDueReviewCardsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AnalysisID,
+			&i.Repetitions,
+			&i.IntervalDays,
+			&i.EaseFactor,
+			&i.DueAt,
+			&i.LastReviewedAt,
+			&i.CreatedAt,
+			&i.ItemID,
+			&i.Position,
+			&i.Content,
+			&i.CollectionID,
+			&i.CollectionTitle,
+			&i.Course,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReviewCardForUpdate = `-- name: GetReviewCardForUpdate :one
+SELECT r.id, r.user_id, r.analysis_id, r.repetitions, r.interval_days, r.ease_factor, r.due_at, r.last_reviewed_at, r.created_at, r.item_id, i.position, COALESCE(NULLIF(i.edited, 'null'), i.generated)::jsonb AS content,
+       c.id AS collection_id, c.title AS collection_title, c.course
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.id = $1
+  AND r.user_id = $2
+  AND i.deleted_at IS NULL
+FOR UPDATE OF r
+`
+
+type GetReviewCardForUpdateParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetReviewCardForUpdateRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AnalysisID      uuid.UUID
+	Repetitions     int32
+	IntervalDays    int32
+	EaseFactor      float64
+	DueAt           time.Time
+	LastReviewedAt  sql.NullTime
+	CreatedAt       time.Time
+	ItemID          uuid.UUID
+	Position        int32
+	Content         json.RawMessage
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+// Locks the card so concurrent grades are applied one after the other
+func (q *Queries) GetReviewCardForUpdate(ctx context.Context, arg GetReviewCardForUpdateParams) (GetReviewCardForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getReviewCardForUpdate, arg.ID, arg.UserID)
+	var i GetReviewCardForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AnalysisID,
+		&i.Repetitions,
+		&i.IntervalDays,
+		&i.EaseFactor,
+		&i.DueAt,
+		&i.LastReviewedAt,
+		&i.CreatedAt,
+		&i.ItemID,
+		&i.Position,
+		&i.Content,
+		&i.CollectionID,
+		&i.CollectionTitle,
+		&i.Course,
+	)
+	return i, err
+}
+
+const getReviewCardsByAnalysis = `-- name: GetReviewCardsByAnalysis :many
+SELECT r.id, r.user_id, r.analysis_id, r.repetitions, r.interval_days, r.ease_factor, r.due_at, r.last_reviewed_at, r.created_at, r.item_id, i.position, COALESCE(NULLIF(i.edited, 'null'), i.generated)::jsonb AS content,
+       c.id AS collection_id, c.title AS collection_title, c.course
+FROM review_cards r
+JOIN analysis_items i ON i.id = r.item_id
+JOIN collection_analyses a ON a.id = r.analysis_id
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE r.user_id = $1
+  AND r.analysis_id = $2
+  AND i.deleted_at IS NULL
+ORDER BY i.position
+`
+
+type GetReviewCardsByAnalysisParams struct {
+	UserID     uuid.UUID
+	AnalysisID uuid.UUID
+}
+
+type GetReviewCardsByAnalysisRow struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	AnalysisID      uuid.UUID
+	Repetitions     int32
+	IntervalDays    int32
+	EaseFactor      float64
+	DueAt           time.Time
+	LastReviewedAt  sql.NullTime
+	CreatedAt       time.Time
+	ItemID          uuid.UUID
+	Position        int32
+	Content         json.RawMessage
+	CollectionID    uuid.UUID
+	CollectionTitle string
+	Course          string
+}
+
+func (q *Queries) GetReviewCardsByAnalysis(ctx context.Context, arg GetReviewCardsByAnalysisParams) ([]GetReviewCardsByAnalysisRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReviewCardsByAnalysis, arg.UserID, arg.AnalysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReviewCardsByAnalysisRow
+	for rows.Next() {
+		var i GetReviewCardsByAnalysisRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AnalysisID,
+			&i.Repetitions,
+			&i.IntervalDays,
+			&i.EaseFactor,
+			&i.DueAt,
+			&i.LastReviewedAt,
+			&i.CreatedAt,
+			&i.ItemID,
+			&i.Position,
+			&i.Content,
+			&i.CollectionID,
+			&i.CollectionTitle,
+			&i.Course,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserAnalysis = `-- name: GetUserAnalysis :one
+SELECT a.id, a.snapshot_id, a.type, a.result, a.created_at, a.parameters, a.template_id, a.model, a.prompt_hash, a.input_tokens, a.output_tokens, a.latency_ms, a.requests, a.imported
+FROM collection_analyses a
+JOIN collection_snapshots s ON s.id = a.snapshot_id
+JOIN collections c ON c.id = s.collection_id
+WHERE a.id = $1
+  AND c.creator_id = $2
+`
+
+type GetUserAnalysisParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// An analysis of one of the user's collections
+func (q *Queries) GetUserAnalysis(ctx context.Context, arg GetUserAnalysisParams) (CollectionAnalysis, error) {
+	row := q.db.QueryRowContext(ctx, getUserAnalysis, arg.ID, arg.UserID)
+	var i CollectionAnalysis
+	err := row.Scan(
+		&i.ID,
+		&i.SnapshotID,
+		&i.Type,
+		&i.Result,
+		&i.CreatedAt,
+		&i.Parameters,
+		&i.TemplateID,
+		&i.Model,
+		&i.PromptHash,
+		&i.InputTokens,
+		&i.OutputTokens,
+		&i.LatencyMs,
+		&i.Requests,
+		&i.Imported,
+	)
+	return i, err
+}
+
+const updateReviewSchedule = `-- name: UpdateReviewSchedule :exec
+UPDATE review_cards
+SET repetitions = $1,
+    interval_days = $2,
+    ease_factor = $3,
+    due_at = $4,
+    last_reviewed_at = $5
+WHERE id = $6
+`
+
+type UpdateReviewScheduleParams struct {
+	Repetitions  int32
+	IntervalDays int32
+	EaseFactor   float64
+	DueAt        time.Time
+	ReviewedAt   sql.NullTime
+	ID           uuid.UUID
+}
+
+func (q *Queries) UpdateReviewSchedule(ctx context.Context, arg UpdateReviewScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, updateReviewSchedule,
+		arg.Repetitions,
+		arg.IntervalDays,
+		arg.EaseFactor,
+		arg.DueAt,
+		arg.ReviewedAt,
+		arg.ID,
+	)
+	return err
+}
@@ -0,0 +1,101 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: usage.sql
+
+package sqlgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createUsageEvent = `-- name: CreateUsageEvent :exec
+INSERT INTO usage_events (user_id, kind, requests, input_tokens, output_tokens)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateUsageEventParams struct {
+	UserID       uuid.UUID
+	Kind         UsageKind
+	Requests     int32
+	InputTokens  int64
+	OutputTokens int64
+}
+
+func (q *Queries) CreateUsageEvent(ctx context.Context, arg CreateUsageEventParams) error {
+	_, err := q.db.ExecContext(ctx, createUsageEvent,
+		arg.UserID,
+		arg.Kind,
+		arg.Requests,
+		arg.InputTokens,
+		arg.OutputTokens,
+	)
+	return err
+}
+
+const getUsageLimitOverride = `-- name: GetUsageLimitOverride :one
+SELECT user_id, daily_tokens, monthly_tokens, daily_requests, monthly_requests, updated_at FROM usage_limit_overrides
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUsageLimitOverride(ctx context.Context, userID uuid.UUID) (UsageLimitOverride, error) {
+	row := q.db.QueryRowContext(ctx, getUsageLimitOverride, userID)
+	var i UsageLimitOverride
+	err := row.Scan(
+		&i.UserID,
+		&i.DailyTokens,
+		&i.MonthlyTokens,
+		&i.DailyRequests,
+		&i.MonthlyRequests,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUsageTotals = `-- name: GetUsageTotals :many
+SELECT kind,
+       COALESCE(SUM(requests), 0)::bigint AS requests,
+       COALESCE(SUM(input_tokens + output_tokens), 0)::bigint AS tokens
+FROM usage_events
+WHERE user_id = $1
+  AND created_at >= $2
+GROUP BY kind
+`
+
+type GetUsageTotalsParams struct {
+	UserID uuid.UUID
+	Since  time.Time
+}
+
+type GetUsageTotalsRow struct {
+	Kind     UsageKind
+	Requests int64
+	Tokens   int64
+}
+
+// Consumption since a point in time, split by kind
+func (q *Queries) GetUsageTotals(ctx context.Context, arg GetUsageTotalsParams) ([]GetUsageTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsageTotals, arg.UserID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsageTotalsRow
+	for rows.Next() {
+		var i GetUsageTotalsRow
+		if err := rows.Scan(&i.Kind, &i.Requests, &i.Tokens); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}